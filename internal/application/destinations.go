@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+// DefaultDestination names the note repository passed to NewRSSFeedService.
+const DefaultDestination = "misskey"
+
+type destination struct {
+	name string
+	repo repository.NoteRepository
+}
+
+func WithDestination(name string, repo repository.NoteRepository) RSSFeedServiceOption {
+	return func(s *RSSFeedService) {
+		if s.destinations == nil {
+			s.destinations = make(map[string]repository.NoteRepository)
+		}
+		s.destinations[name] = repo
+	}
+}
+
+func (s *RSSFeedService) destinationsFor(feed FeedSettings) []destination {
+	if len(feed.Destinations) == 0 {
+		return []destination{{name: DefaultDestination, repo: s.noteRepo}}
+	}
+
+	destinations := make([]destination, 0, len(feed.Destinations))
+	for _, name := range feed.Destinations {
+		repo, ok := s.destinations[name]
+		if name == DefaultDestination {
+			repo, ok = s.noteRepo, true
+		}
+		if !ok {
+			log.Printf("Warning: Unknown destination %q for feed [%s]", name, feed.URL)
+			continue
+		}
+		destinations = append(destinations, destination{name: name, repo: repo})
+	}
+	return destinations
+}
+
+type noteTextLimiter interface {
+	MaxNoteLength(ctx context.Context) int
+}
+
+func noteTextLimit(ctx context.Context, repo repository.NoteRepository) int {
+	if limiter, ok := repo.(noteTextLimiter); ok {
+		if limit := limiter.MaxNoteLength(ctx); limit > 0 {
+			return limit
+		}
+	}
+	return defaultNoteTextLimit
+}
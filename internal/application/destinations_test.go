@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type limitedNoteRepository struct {
+	mockNoteRepository
+	limit int
+}
+
+func (m *limitedNoteRepository) MaxNoteLength(ctx context.Context) int {
+	return m.limit
+}
+
+func TestRSSFeedService_Destinations(t *testing.T) {
+	tests := []struct {
+		name             string
+		destinations     []string
+		mastodonErr      error
+		expectedMisskey  int
+		expectedMastodon int
+		expectProcessed  bool
+	}{
+		{"default destination", nil, nil, 1, 0, true},
+		{"cross-post", []string{"misskey", "mastodon"}, nil, 1, 1, true},
+		{"mastodon only", []string{"mastodon"}, nil, 0, 1, true},
+		{"unknown destination skipped", []string{"misskey", "bluesky"}, nil, 1, 0, true},
+		{"partial failure still marks processed", []string{"misskey", "mastodon"}, errors.New("boom"), 1, 0, true},
+		{"all destinations failed", []string{"mastodon"}, errors.New("boom"), 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []*entity.FeedEntry{entity.NewFeedEntry("Article", "https://example.tld/1", "Desc", time.Now(), "guid-1")}
+			misskeyRepo := &mockNoteRepository{}
+			mastodonRepo := &mockNoteRepository{err: tt.mastodonErr}
+			cacheRepo := newMockCacheRepository()
+			service := NewRSSFeedService(&mockFeedRepository{entries: entries}, misskeyRepo, cacheRepo, nil, WithDestination("mastodon", mastodonRepo))
+
+			err := service.ProcessFeedWithSettings(context.Background(), FeedSettings{URL: "https://example.tld/rss", Destinations: tt.destinations})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(misskeyRepo.posted) != tt.expectedMisskey {
+				t.Errorf("expected %d Misskey posts, got %d", tt.expectedMisskey, len(misskeyRepo.posted))
+			}
+			if len(mastodonRepo.posted) != tt.expectedMastodon {
+				t.Errorf("expected %d Mastodon posts, got %d", tt.expectedMastodon, len(mastodonRepo.posted))
+			}
+			if cacheRepo.processedGUIDs["guid-1"] != tt.expectProcessed {
+				t.Errorf("expected processed=%v, got %v", tt.expectProcessed, cacheRepo.processedGUIDs["guid-1"])
+			}
+		})
+	}
+}
+
+func TestRSSFeedService_DestinationTextLimits(t *testing.T) {
+	entries := []*entity.FeedEntry{entity.NewFeedEntry("A long article title", "https://example.tld/1", "", time.Now(), "guid-1")}
+	misskeyRepo := &mockNoteRepository{}
+	mastodonRepo := &limitedNoteRepository{limit: 10}
+	service := NewRSSFeedService(&mockFeedRepository{entries: entries}, misskeyRepo, newMockCacheRepository(), nil, WithDestination("mastodon", mastodonRepo))
+
+	err := service.ProcessFeedWithSettings(context.Background(), FeedSettings{
+		URL:          "https://example.tld/rss",
+		Template:     "{{.Title}}",
+		Destinations: []string{"misskey", "mastodon"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := misskeyRepo.posted[0].Text; got != "A long article title" {
+		t.Errorf("expected full text for Misskey, got %q", got)
+	}
+	if got := mastodonRepo.posted[0].Text; got != "A long ar…" {
+		t.Errorf("expected text cut to the Mastodon limit, got %q", got)
+	}
+}
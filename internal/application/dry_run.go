@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+type payloadPreviewer interface {
+	PreviewPayload(ctx context.Context, note *entity.Note) (map[string]interface{}, error)
+}
+
+// DryRunNoteRepository logs the request each note would produce instead of
+// posting it.
+type DryRunNoteRepository struct {
+	name string
+	next repository.NoteRepository
+}
+
+func NewDryRunNoteRepository(name string, next repository.NoteRepository) *DryRunNoteRepository {
+	return &DryRunNoteRepository{name: name, next: next}
+}
+
+func (d *DryRunNoteRepository) Post(ctx context.Context, note *entity.Note) error {
+	var payload interface{} = note
+	if previewer, ok := d.next.(payloadPreviewer); ok {
+		preview, err := previewer.PreviewPayload(ctx, note)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		payload = preview
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("dry run: failed to encode note: %w", err)
+	}
+	log.Printf("Dry run: would post to %s:\n%s", d.name, data)
+	return nil
+}
+
+func (d *DryRunNoteRepository) MaxNoteLength(ctx context.Context) int {
+	return noteTextLimit(ctx, d.next)
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type previewingNoteRepository struct {
+	mockNoteRepository
+	payload map[string]interface{}
+	err     error
+}
+
+func (m *previewingNoteRepository) PreviewPayload(ctx context.Context, note *entity.Note) (map[string]interface{}, error) {
+	return m.payload, m.err
+}
+
+func TestDryRunNoteRepository_Post(t *testing.T) {
+	previewErr := errors.New("text too long")
+
+	tests := []struct {
+		name         string
+		repo         *previewingNoteRepository
+		expectErr    error
+		expectLogged string
+	}{
+		{"logs payload", &previewingNoteRepository{payload: map[string]interface{}{"text": "rendered"}}, nil, `"text": "rendered"`},
+		{"preview error", &previewingNoteRepository{err: previewErr}, previewErr, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log.SetOutput(&buf)
+			defer log.SetOutput(os.Stderr)
+
+			err := NewDryRunNoteRepository("misskey", tt.repo).Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if len(tt.repo.posted) != 0 {
+				t.Errorf("dry run should not post, got %d notes", len(tt.repo.posted))
+			}
+			if !strings.Contains(buf.String(), tt.expectLogged) {
+				t.Errorf("expected log to contain %q, got %q", tt.expectLogged, buf.String())
+			}
+		})
+	}
+}
+
+func TestDryRunNoteRepository_PostWithoutPreview(t *testing.T) {
+	next := &mockNoteRepository{}
+	if err := NewDryRunNoteRepository("mastodon", next).Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.posted) != 0 {
+		t.Errorf("dry run should not post, got %d notes", len(next.posted))
+	}
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+type feedHealth struct {
+	failures  int
+	lastErr   error
+	alerted   bool
+	lastAlert time.Time
+}
+
+// FeedHealthNotifier sends a direct note to an admin when a feed has failed
+// a number of times in a row, and another when it recovers. Only one alert
+// is sent per failure streak, and at most one per cooldown for each feed.
+type FeedHealthNotifier struct {
+	repo        repository.NoteRepository
+	adminUserID string
+	threshold   int
+	cooldown    time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	feeds       map[string]*feedHealth
+}
+
+func NewFeedHealthNotifier(repo repository.NoteRepository, adminUserID string, threshold int, cooldown time.Duration) *FeedHealthNotifier {
+	return &FeedHealthNotifier{
+		repo:        repo,
+		adminUserID: adminUserID,
+		threshold:   max(threshold, 1),
+		cooldown:    cooldown,
+		now:         time.Now,
+		feeds:       make(map[string]*feedHealth),
+	}
+}
+
+func WithFeedHealthNotifier(notifier *FeedHealthNotifier) RSSFeedServiceOption {
+	return func(s *RSSFeedService) {
+		s.healthNotifier = notifier
+	}
+}
+
+// Observe records the outcome of processing feedURL. A 304 counts as a
+// success, and errors caused by cancelling ctx are ignored.
+func (n *FeedHealthNotifier) Observe(ctx context.Context, feedURL string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	if errors.Is(err, repository.ErrFeedNotModified) {
+		err = nil
+	}
+
+	text := n.record(feedURL, err)
+	if text == "" {
+		return
+	}
+	note := entity.NewNote(text, entity.VisibilitySpecified)
+	note.VisibleUserIDs = []string{n.adminUserID}
+	if err := n.repo.Post(ctx, note); err != nil {
+		log.Printf("Failed to notify admin about feed [%s]: %v", feedURL, err)
+	}
+}
+
+// record updates the feed's state and returns the note text to send, if
+// any.
+func (n *FeedHealthNotifier) record(feedURL string, err error) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	health, ok := n.feeds[feedURL]
+	if !ok {
+		health = &feedHealth{}
+		n.feeds[feedURL] = health
+	}
+
+	if err == nil {
+		recovered := health.alerted
+		failures := health.failures
+		health.failures, health.lastErr, health.alerted = 0, nil, false
+		if !recovered {
+			return ""
+		}
+		return fmt.Sprintf("✅ Feed recovered after %d failed attempts\n%s", failures, feedURL)
+	}
+
+	health.failures++
+	health.lastErr = err
+	now := n.now()
+	if health.alerted || health.failures < n.threshold {
+		return ""
+	}
+	if !health.lastAlert.IsZero() && now.Sub(health.lastAlert) < n.cooldown {
+		return ""
+	}
+	health.alerted = true
+	health.lastAlert = now
+	return fmt.Sprintf("⚠️ Feed failed %d times in a row\n%s\n\n%v", health.failures, feedURL, err)
+}
@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+func TestFeedHealthNotifier_Observe(t *testing.T) {
+	fetchErr := errors.New("dial tcp: lookup example.tld: no such host")
+
+	tests := []struct {
+		name           string
+		outcomes       []error
+		advance        time.Duration
+		expectedPrefix []string
+	}{
+		{"below threshold", []error{fetchErr, fetchErr}, 0, nil},
+		{"alert once per streak", []error{fetchErr, fetchErr, fetchErr, fetchErr, fetchErr}, 0, []string{"⚠️"}},
+		{"success resets count", []error{fetchErr, fetchErr, nil, fetchErr, fetchErr}, 0, nil},
+		{"recovery after alert", []error{fetchErr, fetchErr, fetchErr, nil}, 0, []string{"⚠️", "✅"}},
+		{"not modified counts as success", []error{fetchErr, fetchErr, fetchErr, repository.ErrFeedNotModified}, 0, []string{"⚠️", "✅"}},
+		{"cooldown suppresses flapping", []error{fetchErr, fetchErr, fetchErr, nil, fetchErr, fetchErr, fetchErr}, time.Minute, []string{"⚠️", "✅"}},
+		{"alert again after cooldown", []error{fetchErr, fetchErr, fetchErr, nil, fetchErr, fetchErr, fetchErr}, 2 * time.Hour, []string{"⚠️", "✅", "⚠️"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noteRepo := &mockNoteRepository{}
+			notifier := NewFeedHealthNotifier(noteRepo, "admin-id", 3, time.Hour)
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			notifier.now = func() time.Time { return now }
+
+			for _, err := range tt.outcomes {
+				notifier.Observe(context.Background(), "https://example.tld/rss", err)
+				now = now.Add(tt.advance)
+			}
+
+			if len(noteRepo.posted) != len(tt.expectedPrefix) {
+				t.Fatalf("expected %d notes, got %d", len(tt.expectedPrefix), len(noteRepo.posted))
+			}
+			for i, prefix := range tt.expectedPrefix {
+				note := noteRepo.posted[i]
+				if !strings.HasPrefix(note.Text, prefix) {
+					t.Errorf("note %d: expected prefix %q, got %q", i, prefix, note.Text)
+				}
+				if note.Visibility != entity.VisibilitySpecified || len(note.VisibleUserIDs) != 1 || note.VisibleUserIDs[0] != "admin-id" {
+					t.Errorf("note %d: expected a specified note to admin-id, got %s to %v", i, note.Visibility, note.VisibleUserIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedHealthNotifier_AlertIncludesError(t *testing.T) {
+	noteRepo := &mockNoteRepository{}
+	notifier := NewFeedHealthNotifier(noteRepo, "admin-id", 1, time.Hour)
+
+	notifier.Observe(context.Background(), "https://example.tld/rss", fmt.Errorf("failed to fetch RSS feed: unexpected status %d", 404))
+
+	if len(noteRepo.posted) != 1 || !strings.Contains(noteRepo.posted[0].Text, "unexpected status 404") {
+		t.Errorf("expected alert with the error, got %v", noteRepo.posted)
+	}
+}
+
+func TestFeedHealthNotifier_IgnoresCancellation(t *testing.T) {
+	noteRepo := &mockNoteRepository{}
+	notifier := NewFeedHealthNotifier(noteRepo, "admin-id", 1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	notifier.Observe(ctx, "https://example.tld/rss", ctx.Err())
+
+	if len(noteRepo.posted) != 0 {
+		t.Errorf("expected no alert during shutdown, got %d notes", len(noteRepo.posted))
+	}
+}
+
+func TestRSSFeedService_NotifiesFeedHealth(t *testing.T) {
+	noteRepo := &mockNoteRepository{}
+	service := NewRSSFeedService(
+		&mockFeedRepository{err: errors.New("fetch error")},
+		noteRepo,
+		newMockCacheRepository(),
+		nil,
+		WithFeedHealthNotifier(NewFeedHealthNotifier(noteRepo, "admin-id", 2, time.Hour)),
+	)
+
+	for i := 0; i < 2; i++ {
+		service.ProcessFeed(context.Background(), "https://example.tld/rss")
+	}
+	if len(noteRepo.posted) != 1 {
+		t.Errorf("expected 1 alert, got %d notes", len(noteRepo.posted))
+	}
+}
@@ -0,0 +1,240 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+type FeedSettings struct {
+	URL                string
+	Interval           time.Duration
+	Visibility         entity.NoteVisibility
+	Hashtags           []string
+	ChannelID          string
+	Template           string
+	CW                 string
+	LocalOnly          *bool
+	ReactionAcceptance entity.ReactionAcceptance
+	Destinations       []string
+	Filter             *entity.FeedFilter
+	Backfill           *int
+}
+
+func (f FeedSettings) Validate() error {
+	if f.Template == "" {
+		return nil
+	}
+	if _, err := parseNoteTemplate(f.Template); err != nil {
+		return fmt.Errorf("feed [%s]: %w", f.URL, err)
+	}
+	return nil
+}
+
+func (f FeedSettings) buildNote(entry *entity.FeedEntry, summary string, limit int) *entity.Note {
+	visibility := f.Visibility
+	if visibility == "" {
+		visibility = entity.VisibilityHome
+	}
+	note := entity.NewNoteFromFeedWithSummary(entry, summary, visibility)
+	note.ChannelID = f.ChannelID
+	note.CW = f.CW
+	note.ReactionAcceptance = f.ReactionAcceptance
+	if f.LocalOnly != nil {
+		note.SetFederate(!*f.LocalOnly)
+	}
+
+	tags := hashtagify(f.Hashtags)
+	if tags != "" {
+		limit = max(limit-utf8.RuneCountInString(tags)-2, 0)
+	}
+	rendered := false
+	if f.Template != "" {
+		text, err := renderNoteTemplate(f.Template, entry, summary, limit)
+		if err != nil {
+			log.Printf("Warning: Falling back to the default note layout [%s]: %v", f.URL, err)
+		} else {
+			note.Text = text
+			rendered = true
+		}
+	}
+	if !rendered {
+		note.Text = fitDefaultLayout(note.Text, entry.Link, limit)
+	}
+	if tags != "" {
+		if note.Text == "" {
+			note.Text = tags
+		} else {
+			note.Text = strings.TrimRight(note.Text, "\n") + "\n\n" + tags
+		}
+	}
+	return note
+}
+
+type FeedScheduler struct {
+	service         *RSSFeedService
+	feeds           []FeedSettings
+	defaultInterval time.Duration
+	mu              sync.Mutex
+	updates         chan schedule
+}
+
+type schedule struct {
+	feeds           []FeedSettings
+	defaultInterval time.Duration
+}
+
+type feedWorker struct {
+	settings atomic.Pointer[FeedSettings]
+	changed  chan struct{}
+	cancel   context.CancelFunc
+}
+
+func NewFeedScheduler(service *RSSFeedService, feeds []FeedSettings, defaultInterval time.Duration) *FeedScheduler {
+	return &FeedScheduler{
+		service:         service,
+		feeds:           feeds,
+		defaultInterval: defaultInterval,
+		updates:         make(chan schedule, 1),
+	}
+}
+
+// Run polls every feed on its own schedule until ctx is done, applying any
+// changes passed to Update along the way.
+func (s *FeedScheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	workers := make(map[string]*feedWorker)
+	s.apply(ctx, &wg, workers, schedule{feeds: s.feeds, defaultInterval: s.defaultInterval})
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case update := <-s.updates:
+			s.apply(ctx, &wg, workers, update)
+		}
+	}
+}
+
+// Update replaces the scheduled feeds without restarting Run. New feeds
+// start polling, removed feeds stop, and feeds whose settings changed are
+// polled again right away with the new settings.
+func (s *FeedScheduler) Update(feeds []FeedSettings, defaultInterval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.updates:
+	default:
+	}
+	s.updates <- schedule{feeds: feeds, defaultInterval: defaultInterval}
+}
+
+func (s *FeedScheduler) apply(ctx context.Context, wg *sync.WaitGroup, workers map[string]*feedWorker, update schedule) {
+	wanted := make(map[string]bool, len(update.feeds))
+	for _, feed := range update.feeds {
+		if feed.Interval <= 0 {
+			feed.Interval = update.defaultInterval
+		}
+		wanted[feed.URL] = true
+
+		if worker, ok := workers[feed.URL]; ok {
+			if !reflect.DeepEqual(*worker.settings.Load(), feed) {
+				log.Printf("Updating settings of RSS feed [%s]", feed.URL)
+				worker.update(feed)
+			}
+			continue
+		}
+
+		workerCtx, cancel := context.WithCancel(ctx)
+		worker := &feedWorker{changed: make(chan struct{}, 1), cancel: cancel}
+		worker.settings.Store(&feed)
+		workers[feed.URL] = worker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runFeed(workerCtx, worker)
+		}()
+	}
+
+	for url, worker := range workers {
+		if !wanted[url] {
+			log.Printf("Stopping RSS feed [%s]", url)
+			worker.cancel()
+			delete(workers, url)
+		}
+	}
+}
+
+func (w *feedWorker) update(feed FeedSettings) {
+	w.settings.Store(&feed)
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// RunOnce processes every feed a single time and returns the errors of the
+// feeds that failed.
+func (s *FeedScheduler) RunOnce(ctx context.Context) error {
+	var errs []error
+	for _, feed := range s.feeds {
+		err := s.service.ProcessFeedWithSettings(ctx, feed)
+		if err != nil && !errors.Is(err, repository.ErrFeedNotModified) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// maxPollBackoffFactor caps how far a feed that keeps returning 304 or
+// errors is slowed down, as a multiple of its configured interval.
+const maxPollBackoffFactor = 8
+
+func (s *FeedScheduler) runFeed(ctx context.Context, worker *feedWorker) {
+	feed := *worker.settings.Load()
+	log.Printf("Scheduling RSS feed [%s] every %v", feed.URL, feed.Interval)
+
+	interval := feed.Interval
+	for {
+		feed = *worker.settings.Load()
+		err := s.service.ProcessFeedWithSettings(ctx, feed)
+		if err != nil && ctx.Err() == nil && !errors.Is(err, repository.ErrFeedNotModified) {
+			log.Printf("RSS processing error [%s]: %v", feed.URL, err)
+		}
+
+		next := nextPollInterval(interval, feed.Interval, err)
+		if next > interval {
+			log.Printf("Slowing down RSS feed [%s] to every %v", feed.URL, next)
+		}
+		interval = next
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-worker.changed:
+			timer.Stop()
+			interval = worker.settings.Load().Interval
+		case <-timer.C:
+		}
+	}
+}
+
+func nextPollInterval(current, base time.Duration, err error) time.Duration {
+	if err == nil {
+		return base
+	}
+	return min(current*2, base*maxPollBackoffFactor)
+}
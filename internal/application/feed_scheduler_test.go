@@ -0,0 +1,253 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+type countingFeedRepository struct {
+	mu      sync.Mutex
+	fetches map[string]int
+}
+
+func (m *countingFeedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, entity.FeedValidators, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetches[url]++
+	return nil, entity.FeedValidators{}, nil
+}
+
+func (m *countingFeedRepository) count(url string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fetches[url]
+}
+
+func TestFeedScheduler_Run(t *testing.T) {
+	feedRepo := &countingFeedRepository{fetches: make(map[string]int)}
+	service := NewRSSFeedService(feedRepo, &mockNoteRepository{}, newMockCacheRepository(), nil)
+
+	scheduler := NewFeedScheduler(service, []FeedSettings{
+		{URL: "https://example.tld/fast", Interval: 20 * time.Millisecond},
+		{URL: "https://example.tld/default"},
+	}, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	scheduler.Run(ctx)
+
+	if fast := feedRepo.count("https://example.tld/fast"); fast < 3 {
+		t.Errorf("expected fast feed to be polled repeatedly, got %d fetches", fast)
+	}
+	if slow := feedRepo.count("https://example.tld/default"); slow != 1 {
+		t.Errorf("expected default-interval feed to be polled once, got %d fetches", slow)
+	}
+}
+
+func TestFeedScheduler_Update(t *testing.T) {
+	feedRepo := &countingFeedRepository{fetches: make(map[string]int)}
+	service := NewRSSFeedService(feedRepo, &mockNoteRepository{}, newMockCacheRepository(), nil)
+
+	const feedA, feedB = "https://example.tld/a", "https://example.tld/b"
+	scheduler := NewFeedScheduler(service, []FeedSettings{{URL: feedA}}, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(ctx)
+	}()
+
+	waitForFetches := func(url string, expected int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for feedRepo.count(url) < expected {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d fetches of %s, got %d", expected, url, feedRepo.count(url))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	steps := []struct {
+		name     string
+		feeds    []FeedSettings
+		expected map[string]int
+	}{
+		{"add feed", []FeedSettings{{URL: feedA}, {URL: feedB}}, map[string]int{feedA: 1, feedB: 1}},
+		{"unchanged feeds keep their schedule", []FeedSettings{{URL: feedA}, {URL: feedB}}, map[string]int{feedA: 1, feedB: 1}},
+		{"changed settings poll again", []FeedSettings{{URL: feedA, Hashtags: []string{"news"}}, {URL: feedB}}, map[string]int{feedA: 2, feedB: 1}},
+		{"explicit default interval is unchanged", []FeedSettings{{URL: feedA, Hashtags: []string{"news"}}, {URL: feedB, Interval: time.Hour}}, map[string]int{feedA: 2, feedB: 1}},
+		{"remove feed", []FeedSettings{{URL: feedB, Interval: time.Minute}}, map[string]int{feedA: 2, feedB: 2}},
+	}
+
+	waitForFetches(feedA, 1)
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			scheduler.Update(step.feeds, time.Hour)
+			for url, expected := range step.expected {
+				waitForFetches(url, expected)
+			}
+			time.Sleep(30 * time.Millisecond)
+			for url, expected := range step.expected {
+				if got := feedRepo.count(url); got != expected {
+					t.Errorf("expected %d fetches of %s, got %d", expected, url, got)
+				}
+			}
+		})
+	}
+
+	cancel()
+	<-done
+}
+
+func TestFeedScheduler_RunOnce(t *testing.T) {
+	tests := []struct {
+		name      string
+		feedErr   error
+		expectErr bool
+	}{
+		{"success", nil, false},
+		{"not modified", repository.ErrFeedNotModified, false},
+		{"fetch error", errors.New("fetch error"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRSSFeedService(&mockFeedRepository{err: tt.feedErr}, &mockNoteRepository{}, newMockCacheRepository(), nil)
+			scheduler := NewFeedScheduler(service, []FeedSettings{
+				{URL: "https://example.tld/a"},
+				{URL: "https://example.tld/b"},
+			}, time.Hour)
+
+			err := scheduler.RunOnce(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestNextPollInterval(t *testing.T) {
+	base := time.Minute
+	tests := []struct {
+		name     string
+		current  time.Duration
+		err      error
+		expected time.Duration
+	}{
+		{"success keeps base", base, nil, base},
+		{"success resets backoff", 4 * base, nil, base},
+		{"not modified doubles", base, repository.ErrFeedNotModified, 2 * base},
+		{"error doubles", 2 * base, errors.New("boom"), 4 * base},
+		{"capped", 8 * base, repository.ErrFeedNotModified, 8 * base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPollInterval(tt.current, base, tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFeedSettings_BuildNote(t *testing.T) {
+	entry := entity.NewFeedEntry("Title", "https://example.tld/1", "Desc", time.Now(), "guid-1")
+
+	tests := []struct {
+		name               string
+		settings           FeedSettings
+		expectedText       string
+		expectedVisibility entity.NoteVisibility
+	}{
+		{"defaults", FeedSettings{}, "📰 Title\nhttps://example.tld/1", entity.VisibilityHome},
+		{"template and hashtags", FeedSettings{Template: "{{.Title}}: {{.Link}}", Hashtags: []string{"news", "#tech"}, Visibility: entity.VisibilityPublic}, "Title: https://example.tld/1\n\n#news #tech", entity.VisibilityPublic},
+		{"broken template falls back", FeedSettings{Template: "{{.Missing}}"}, "📰 Title\nhttps://example.tld/1", entity.VisibilityHome},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := tt.settings.buildNote(entry, "", defaultNoteTextLimit)
+			if note.Text != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, note.Text)
+			}
+			if note.Visibility != tt.expectedVisibility {
+				t.Errorf("expected visibility %s, got %s", tt.expectedVisibility, note.Visibility)
+			}
+		})
+	}
+}
+
+func TestFeedSettings_BuildNoteNoteOptions(t *testing.T) {
+	entry := entity.NewFeedEntry("Title", "https://example.tld/1", "Desc", time.Now(), "guid-1")
+	localOnly := true
+
+	tests := []struct {
+		name             string
+		settings         FeedSettings
+		expectedCW       string
+		expectedFederate *bool
+		expectedAccept   entity.ReactionAcceptance
+	}{
+		{"defaults", FeedSettings{}, "", nil, entity.ReactionAcceptanceAll},
+		{"cw and reactions", FeedSettings{CW: "Spoilers", ReactionAcceptance: entity.ReactionAcceptanceLikeOnly}, "Spoilers", nil, entity.ReactionAcceptanceLikeOnly},
+		{"local only", FeedSettings{LocalOnly: &localOnly}, "", new(bool), entity.ReactionAcceptanceAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := tt.settings.buildNote(entry, "", defaultNoteTextLimit)
+			if note.CW != tt.expectedCW {
+				t.Errorf("expected cw %q, got %q", tt.expectedCW, note.CW)
+			}
+			if (note.Federate == nil) != (tt.expectedFederate == nil) || (note.Federate != nil && *note.Federate != *tt.expectedFederate) {
+				t.Errorf("expected federate %v, got %v", tt.expectedFederate, note.Federate)
+			}
+			if note.ReactionAcceptance != tt.expectedAccept {
+				t.Errorf("expected reaction acceptance %q, got %q", tt.expectedAccept, note.ReactionAcceptance)
+			}
+		})
+	}
+}
+
+func TestFeedSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		expectError bool
+	}{
+		{"no template", "", false},
+		{"valid template", "{{bold .Title}}\n{{link \"source\" .Link}}", false},
+		{"unknown function", "{{shout .Title}}", true},
+		{"unclosed action", "{{.Title", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FeedSettings{URL: "https://example.tld/rss", Template: tt.template}.Validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error=%v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestRSSFeedService_ProcessFeedWithSettings_Channel(t *testing.T) {
+	entries := []*entity.FeedEntry{entity.NewFeedEntry("Article", "https://example.tld/1", "Desc", time.Now(), "guid-1")}
+	noteRepo := &mockNoteRepository{}
+	service := NewRSSFeedService(&mockFeedRepository{entries: entries}, noteRepo, newMockCacheRepository(), nil)
+
+	if err := service.ProcessFeedWithSettings(context.Background(), FeedSettings{URL: "https://example.tld/rss", ChannelID: "channel1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(noteRepo.posted) != 1 || noteRepo.posted[0].ChannelID != "channel1" {
+		t.Errorf("expected note posted to channel1, got %+v", noteRepo.posted)
+	}
+}
@@ -0,0 +1,22 @@
+package application
+
+// MetricsRecorder receives events from RSSFeedService for monitoring.
+type MetricsRecorder interface {
+	FeedFetched(feedURL string, err error)
+	ItemsDiscovered(feedURL string, count int)
+	NotePosted(feedURL, destination string, err error)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) FeedFetched(string, error)        {}
+func (noopMetrics) ItemsDiscovered(string, int)      {}
+func (noopMetrics) NotePosted(string, string, error) {}
+
+func WithMetrics(recorder MetricsRecorder) RSSFeedServiceOption {
+	return func(s *RSSFeedService) {
+		if recorder != nil {
+			s.metrics = recorder
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type recordingMetrics struct {
+	fetches    int
+	fetchErrs  int
+	discovered int
+	posted     map[string]int
+	postErrs   int
+}
+
+func (m *recordingMetrics) FeedFetched(feedURL string, err error) {
+	m.fetches++
+	if err != nil {
+		m.fetchErrs++
+	}
+}
+
+func (m *recordingMetrics) ItemsDiscovered(feedURL string, count int) {
+	m.discovered += count
+}
+
+func (m *recordingMetrics) NotePosted(feedURL, destination string, err error) {
+	if err != nil {
+		m.postErrs++
+		return
+	}
+	if m.posted == nil {
+		m.posted = make(map[string]int)
+	}
+	m.posted[destination]++
+}
+
+func TestRSSFeedService_Metrics(t *testing.T) {
+	now := time.Now()
+	entries := []*entity.FeedEntry{
+		entity.NewFeedEntry("Article 1", "https://example.tld/1", "Desc 1", now.Add(-time.Hour), "guid-1"),
+		entity.NewFeedEntry("Article 2", "https://example.tld/2", "Desc 2", now, "guid-2"),
+	}
+
+	tests := []struct {
+		name               string
+		feedErr            error
+		postErr            error
+		expectedFetchErrs  int
+		expectedDiscovered int
+		expectedPosted     int
+		expectedPostErrs   int
+	}{
+		{"posted", nil, nil, 0, 2, 2, 0},
+		{"fetch error", errors.New("fetch error"), nil, 1, 0, 0, 0},
+		{"post error", nil, errors.New("post error"), 0, 2, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &recordingMetrics{}
+			service := NewRSSFeedService(
+				&mockFeedRepository{entries: entries, err: tt.feedErr},
+				&mockNoteRepository{err: tt.postErr},
+				newMockCacheRepository(),
+				nil,
+				WithFirstRunLatestOnly(false),
+				WithMetrics(metrics),
+			)
+
+			service.ProcessFeed(context.Background(), "https://example.tld/rss")
+
+			if metrics.fetches != 1 {
+				t.Errorf("expected 1 fetch, got %d", metrics.fetches)
+			}
+			if metrics.fetchErrs != tt.expectedFetchErrs {
+				t.Errorf("expected %d fetch errors, got %d", tt.expectedFetchErrs, metrics.fetchErrs)
+			}
+			if metrics.discovered != tt.expectedDiscovered {
+				t.Errorf("expected %d discovered items, got %d", tt.expectedDiscovered, metrics.discovered)
+			}
+			if got := metrics.posted[DefaultDestination]; got != tt.expectedPosted {
+				t.Errorf("expected %d posted notes, got %d", tt.expectedPosted, got)
+			}
+			if metrics.postErrs != tt.expectedPostErrs {
+				t.Errorf("expected %d post errors, got %d", tt.expectedPostErrs, metrics.postErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package application
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const defaultNoteTextLimit = 3000
+
+type noteTemplateData struct {
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+	Summary     string
+}
+
+func noteTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"bold":       mfmBold,
+		"link":       mfmLink,
+		"hashtagify": hashtagify,
+		"plaintext":  htmlToPlainText,
+		"truncate":   func(limit int, text string) string { return entity.TruncateRunes(text, limit) },
+	}
+}
+
+func parseNoteTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("note").Funcs(noteTemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse note template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func renderNoteTemplate(text string, entry *entity.FeedEntry, summary string, limit int) (string, error) {
+	tmpl, err := parseNoteTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, noteTemplateData{
+		Title:       entry.Title,
+		Link:        entry.Link,
+		Description: entry.Description,
+		Content:     entry.Content,
+		Categories:  entry.Categories,
+		Published:   entry.Published,
+		Updated:     entry.Updated,
+		Summary:     summary,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render note template: %w", err)
+	}
+	return entity.TruncateRunes(b.String(), limit), nil
+}
+
+func mfmBold(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "**" + text + "**"
+}
+
+func mfmLink(label, url string) string {
+	if label == "" {
+		return url
+	}
+	return "[" + label + "](" + url + ")"
+}
+
+// hashtagify turns a category name or a list of them into Misskey hashtags,
+// dropping characters that would end a hashtag early.
+func hashtagify(v interface{}) string {
+	var names []string
+	switch value := v.(type) {
+	case string:
+		names = []string{value}
+	case []string:
+		names = value
+	default:
+		return ""
+	}
+
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		var b strings.Builder
+		for _, r := range strings.TrimPrefix(strings.TrimSpace(name), "#") {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			tags = append(tags, "#"+b.String())
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// fitDefaultLayout shortens the default layout to limit, keeping the
+// trailing link whole and cutting the title or summary before it.
+func fitDefaultLayout(text, link string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	body := strings.TrimSuffix(text, link)
+	if link == "" || len(body) == len(text) {
+		return entity.TruncateRunes(text, limit)
+	}
+	trimmed := strings.TrimRight(body, "\n")
+	separator := body[len(trimmed):]
+	available := limit - utf8.RuneCountInString(separator+link)
+	if available <= 1 {
+		return entity.TruncateRunes(link, limit)
+	}
+	return entity.TruncateRunes(trimmed, available) + separator + link
+}
+
+// htmlToPlainText strips tags, keeps paragraph and line breaks, and decodes
+// entities so feed descriptions can be posted as plain text.
+func htmlToPlainText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			next := strings.IndexByte(s[i:], '<')
+			if next < 0 {
+				next = len(s) - i
+			}
+			b.WriteString(s[i : i+next])
+			i += next
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			b.WriteString(s[i:])
+			break
+		}
+		switch tagName(s[i+1 : i+end]) {
+		case "br":
+			b.WriteString("\n")
+		case "p", "/p", "div", "/div", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+			b.WriteString("\n\n")
+		}
+		i += end + 1
+	}
+	return collapseBlankLines(html.UnescapeString(b.String()))
+}
+
+func tagName(tag string) string {
+	tag = strings.TrimSpace(tag)
+	end := 0
+	for end < len(tag) && tag[end] != ' ' && tag[end] != '/' && tag[end] != '\t' && tag[end] != '\n' {
+		end++
+	}
+	if end == 0 && strings.HasPrefix(tag, "/") {
+		end = 1
+		for end < len(tag) && tag[end] != ' ' && tag[end] != '>' {
+			end++
+		}
+	}
+	return strings.ToLower(tag[:end])
+}
+
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(kept) > 0 {
+				blank = true
+			}
+			continue
+		}
+		if blank {
+			kept = append(kept, "")
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestRenderNoteTemplate(t *testing.T) {
+	entry := entity.NewFeedEntry("Title", "https://example.tld/1", "<p>First &amp; second</p><p>Third<br>line</p>", time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC), "guid-1")
+	entry.Categories = []string{"Go", "open source", "#misskey"}
+	entry.Content = "<p>Full <b>body</b></p>"
+	entry.Updated = time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template string
+		summary  string
+		limit    int
+		expected string
+	}{
+		{"fields", "{{.Title}} {{.Link}} {{.Summary}}", "Short", 500, "Title https://example.tld/1 Short"},
+		{"mfm helpers", "{{bold .Title}}\n{{link \"Read more\" .Link}}", "", 500, "**Title**\n[Read more](https://example.tld/1)"},
+		{"hashtagify categories", "{{hashtagify .Categories}}", "", 500, "#Go #opensource #misskey"},
+		{"plaintext description", "{{plaintext .Description}}", "", 500, "First & second\n\nThird\nline"},
+		{"published", "{{.Published.Format \"2006-01-02\"}}", "", 500, "2024-05-01"},
+		{"content", "{{plaintext .Content}}", "", 500, "Full body"},
+		{"updated", "{{.Updated.Format \"2006-01-02\"}}", "", 500, "2024-05-02"},
+		{"truncate helper", "{{truncate 4 .Title}}", "", 500, "Tit…"},
+		{"limit applied", "{{.Title}} {{.Link}}", "", 8, "Title h…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := renderNoteTemplate(tt.template, entry, tt.summary, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestHTMLToPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "Hello world", "Hello world"},
+		{"inline tags", "<b>Bold</b> and <a href=\"https://example.tld\">link</a>", "Bold and link"},
+		{"self-closing break", "one<br/>two<br />three", "one\ntwo\nthree"},
+		{"list items", "<ul><li>a</li><li>b</li></ul>", "a\n\nb"},
+		{"entities", "&lt;tag&gt; &quot;quoted&quot;", "<tag> \"quoted\""},
+		{"unterminated tag", "text <b", "text <b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToPlainText(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFeedSettings_BuildNoteReservesHashtags(t *testing.T) {
+	entry := entity.NewFeedEntry(strings.Repeat("a", 50), "https://example.tld/1", "", time.Now(), "guid-1")
+	settings := FeedSettings{Template: "{{.Title}}", Hashtags: []string{"news"}}
+
+	note := settings.buildNote(entry, "", 20)
+	if got := len([]rune(note.Text)); got > 20 {
+		t.Errorf("expected at most 20 characters, got %d: %q", got, note.Text)
+	}
+	if !strings.HasSuffix(note.Text, "\n\n#news") {
+		t.Errorf("expected hashtags to survive truncation, got %q", note.Text)
+	}
+}
+
+func TestFeedSettings_BuildNoteHashtagsFillLimit(t *testing.T) {
+	entry := entity.NewFeedEntry(strings.Repeat("a", 50), "https://example.tld/1", "", time.Now(), "guid-1")
+
+	tests := []struct {
+		name     string
+		settings FeedSettings
+	}{
+		{"template", FeedSettings{Template: "{{.Title}}", Hashtags: []string{"news"}}},
+		{"default layout", FeedSettings{Hashtags: []string{"news"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := tt.settings.buildNote(entry, "", 6)
+			if note.Text != "#news" {
+				t.Errorf("expected only the hashtags to fit, got %q", note.Text)
+			}
+		})
+	}
+}
+
+func TestFeedSettings_BuildNoteFitsDefaultLayout(t *testing.T) {
+	entry := entity.NewFeedEntry(strings.Repeat("a", 600), "https://example.tld/1", "", time.Now(), "guid-1")
+
+	tests := []struct {
+		name     string
+		summary  string
+		settings FeedSettings
+	}{
+		{"title only", "", FeedSettings{}},
+		{"with summary", strings.Repeat("b", 600), FeedSettings{}},
+		{"with hashtags", "", FeedSettings{Hashtags: []string{"news"}}},
+		{"broken template", "", FeedSettings{Template: "{{.Missing}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := tt.settings.buildNote(entry, tt.summary, 500)
+			if got := len([]rune(note.Text)); got > 500 {
+				t.Errorf("expected at most 500 characters, got %d", got)
+			}
+			if !strings.Contains(note.Text, "…\n") || !strings.Contains(note.Text, "\nhttps://example.tld/1") {
+				t.Errorf("expected the title or summary to be cut before the link, got %q", note.Text)
+			}
+		})
+	}
+}
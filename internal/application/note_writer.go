@@ -0,0 +1,146 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const defaultNoteWriterBufferSize = 3000
+
+var ErrNoteWriterClosed = errors.New("note writer is closed")
+
+type NoteWriter struct {
+	ctx           context.Context
+	noteRepo      repository.NoteRepository
+	visibility    entity.NoteVisibility
+	maxBufferSize int
+	flushInterval time.Duration
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	timer  *time.Timer
+	closed bool
+}
+
+type NoteWriterOption func(*NoteWriter)
+
+func WithMaxBufferSize(size int) NoteWriterOption {
+	return func(w *NoteWriter) {
+		w.maxBufferSize = size
+	}
+}
+
+func WithFlushInterval(interval time.Duration) NoteWriterOption {
+	return func(w *NoteWriter) {
+		w.flushInterval = interval
+	}
+}
+
+func NewNoteWriter(ctx context.Context, noteRepo repository.NoteRepository, visibility entity.NoteVisibility, opts ...NoteWriterOption) *NoteWriter {
+	w := &NoteWriter{
+		ctx:           ctx,
+		noteRepo:      noteRepo,
+		visibility:    visibility,
+		maxBufferSize: defaultNoteWriterBufferSize,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *NoteWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrNoteWriterClosed
+	}
+
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.buf.Next(i + 1))
+		if err := w.post(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	if w.maxBufferSize > 0 && w.buf.Len() >= w.maxBufferSize {
+		if err := w.flushLocked(); err != nil {
+			return len(p), err
+		}
+	}
+	w.armTimer()
+	return len(p), nil
+}
+
+func (w *NoteWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.flushLocked()
+}
+
+func (w *NoteWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	err := w.flushLocked()
+	w.closed = true
+	return err
+}
+
+func (w *NoteWriter) flushLocked() error {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	text := w.buf.String()
+	w.buf.Reset()
+	return w.post(text)
+}
+
+func (w *NoteWriter) armTimer() {
+	if w.flushInterval <= 0 || w.buf.Len() == 0 || w.timer != nil {
+		return
+	}
+	w.timer = time.AfterFunc(w.flushInterval, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		w.timer = nil
+		if err := w.flushLocked(); err != nil {
+			log.Printf("Failed to flush buffered note output: %v", err)
+		}
+	})
+}
+
+func (w *NoteWriter) post(text string) error {
+	text = strings.TrimRight(text, "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if err := w.noteRepo.Post(w.ctx, entity.NewNote(text, w.visibility)); err != nil {
+		return fmt.Errorf("failed to post written output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteWriter_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		writes        []string
+		maxBufferSize int
+		expected      []string
+	}{
+		{"single line", []string{"hello\n"}, 0, []string{"hello"}},
+		{"split across writes", []string{"hel", "lo\nwor", "ld\n"}, 0, []string{"hello", "world"}},
+		{"multiple lines in one write", []string{"a\nb\r\nc\n"}, 0, []string{"a", "b", "c"}},
+		{"blank lines skipped", []string{"a\n\n  \nb\n"}, 0, []string{"a", "b"}},
+		{"partial line kept until close", []string{"a\ntail"}, 0, []string{"a", "tail"}},
+		{"size threshold", []string{"abcdef"}, 4, []string{"abcdef"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noteRepo := &mockNoteRepository{}
+			var opts []NoteWriterOption
+			if tt.maxBufferSize > 0 {
+				opts = append(opts, WithMaxBufferSize(tt.maxBufferSize))
+			}
+			w := NewNoteWriter(context.Background(), noteRepo, entity.VisibilityHome, opts...)
+
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if n != len(s) {
+					t.Errorf("expected %d bytes written, got %d", len(s), n)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected close error: %v", err)
+			}
+
+			if len(noteRepo.posted) != len(tt.expected) {
+				t.Fatalf("expected %d notes, got %d", len(tt.expected), len(noteRepo.posted))
+			}
+			for i, note := range noteRepo.posted {
+				if note.Text != tt.expected[i] {
+					t.Errorf("note %d: expected %q, got %q", i, tt.expected[i], note.Text)
+				}
+				if note.Visibility != entity.VisibilityHome {
+					t.Errorf("note %d: expected home visibility, got %s", i, note.Visibility)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteWriter_FlushInterval(t *testing.T) {
+	noteRepo := &mockNoteRepository{}
+	w := NewNoteWriter(context.Background(), noteRepo, entity.VisibilityHome, WithFlushInterval(20*time.Millisecond))
+
+	if _, err := w.Write([]byte("partial")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	if len(noteRepo.posted) != 1 || noteRepo.posted[0].Text != "partial" {
+		t.Errorf("expected partial line to be flushed once, got %+v", noteRepo.posted)
+	}
+}
+
+func TestNoteWriter_Errors(t *testing.T) {
+	noteRepo := &mockNoteRepository{err: fmt.Errorf("post failed")}
+	w := NewNoteWriter(context.Background(), noteRepo, entity.VisibilityHome)
+
+	if _, err := w.Write([]byte("line\n")); err == nil {
+		t.Error("expected post error, got nil")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, ErrNoteWriterClosed) {
+		t.Errorf("expected ErrNoteWriterClosed, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const outboundQueueNamespace = "outbox."
+
+type queuedNote struct {
+	Note     *entity.Note `json:"note"`
+	QueuedAt time.Time    `json:"queuedAt"`
+}
+
+// OutboundQueue persists each note before handing it to the wrapped
+// repository. A post interrupted by context cancellation stays on disk and
+// is delivered by Drain on the next start.
+type OutboundQueue struct {
+	next      repository.NoteRepository
+	store     repository.StateStore
+	namespace string
+	mu        sync.Mutex
+	seq       uint64
+	now       func() time.Time
+}
+
+func NewOutboundQueue(name string, next repository.NoteRepository, store repository.StateStore) *OutboundQueue {
+	return &OutboundQueue{
+		next:      next,
+		store:     store,
+		namespace: outboundQueueNamespace + name,
+		now:       time.Now,
+	}
+}
+
+func (q *OutboundQueue) Post(ctx context.Context, note *entity.Note) error {
+	key, err := q.enqueue(note)
+	if err != nil {
+		log.Printf("Warning: Could not persist outbound note, posting without the queue: %v", err)
+		return q.next.Post(ctx, note)
+	}
+	return q.deliver(ctx, key, note)
+}
+
+func (q *OutboundQueue) deliver(ctx context.Context, key string, note *entity.Note) error {
+	err := q.next.Post(ctx, note)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %v", repository.ErrNoteQueued, err)
+	}
+	q.remove(key)
+	return err
+}
+
+// Drain posts notes left over from a previous run, oldest first. A note
+// that fails for any reason other than cancellation is dropped.
+func (q *OutboundQueue) Drain(ctx context.Context) (int, error) {
+	pending, err := q.store.List(ctx, q.namespace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load outbound queue: %w", err)
+	}
+
+	keys := make([]string, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	delivered := 0
+	for _, key := range keys {
+		var queued queuedNote
+		if err := json.Unmarshal(pending[key], &queued); err != nil || queued.Note == nil {
+			log.Printf("Warning: Dropping unreadable queued note [%s]: %v", key, err)
+			q.remove(key)
+			continue
+		}
+		err := q.deliver(ctx, key, queued.Note)
+		if errors.Is(err, repository.ErrNoteQueued) {
+			return delivered, err
+		}
+		if err != nil {
+			log.Printf("Warning: Dropping queued note from %v: %v", queued.QueuedAt, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (q *OutboundQueue) MaxNoteLength(ctx context.Context) int {
+	return noteTextLimit(ctx, q.next)
+}
+
+func (q *OutboundQueue) enqueue(note *entity.Note) (string, error) {
+	now := q.now()
+	data, err := json.Marshal(queuedNote{Note: note, QueuedAt: now})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode note: %w", err)
+	}
+
+	q.mu.Lock()
+	q.seq++
+	key := fmt.Sprintf("%020d-%06d", now.UnixNano(), q.seq)
+	q.mu.Unlock()
+
+	if err := q.store.Put(context.Background(), q.namespace, key, data); err != nil {
+		return "", fmt.Errorf("failed to store note: %w", err)
+	}
+	return key, nil
+}
+
+func (q *OutboundQueue) remove(key string) {
+	if err := q.store.Delete(context.Background(), q.namespace, key); err != nil {
+		log.Printf("Warning: Failed to remove delivered note [%s] from the outbound queue: %v", key, err)
+	}
+}
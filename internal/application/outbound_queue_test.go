@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+var errPostFailed = errors.New("post failed")
+
+type mockStateStore struct {
+	mu   sync.Mutex
+	data map[string]map[string][]byte
+}
+
+func newMockStateStore() *mockStateStore {
+	return &mockStateStore{data: make(map[string]map[string][]byte)}
+}
+
+func (m *mockStateStore) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.data[namespace][key]
+	return value, ok, nil
+}
+
+func (m *mockStateStore) Put(ctx context.Context, namespace, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data[namespace] == nil {
+		m.data[namespace] = make(map[string][]byte)
+	}
+	m.data[namespace][key] = value
+	return nil
+}
+
+func (m *mockStateStore) Delete(ctx context.Context, namespace, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data[namespace], key)
+	return nil
+}
+
+func (m *mockStateStore) List(ctx context.Context, namespace string) (map[string][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]byte, len(m.data[namespace]))
+	for key, value := range m.data[namespace] {
+		out[key] = value
+	}
+	return out, nil
+}
+
+// blockingNoteRepository waits for the context like a rate limiter holding
+// back a post.
+type blockingNoteRepository struct{}
+
+func (blockingNoteRepository) Post(ctx context.Context, note *entity.Note) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestOutboundQueue_Post(t *testing.T) {
+	tests := []struct {
+		name          string
+		repo          repository.NoteRepository
+		cancel        bool
+		expectErr     error
+		expectPending int
+	}{
+		{"delivered", &mockNoteRepository{}, false, nil, 0},
+		{"interrupted by shutdown", blockingNoteRepository{}, true, repository.ErrNoteQueued, 1},
+		{"failed without shutdown", &mockNoteRepository{err: errPostFailed}, false, errPostFailed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStateStore()
+			queue := NewOutboundQueue("misskey", tt.repo, store)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			err := queue.Post(ctx, entity.NewNote("Hello", entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			pending, _ := store.List(context.Background(), outboundQueueNamespace+"misskey")
+			if len(pending) != tt.expectPending {
+				t.Errorf("expected %d pending notes, got %d", tt.expectPending, len(pending))
+			}
+		})
+	}
+}
+
+func TestOutboundQueue_DrainAfterRestart(t *testing.T) {
+	store := newMockStateStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	interrupted := NewOutboundQueue("misskey", blockingNoteRepository{}, store)
+	for _, text := range []string{"First", "Second", "Third"} {
+		if err := interrupted.Post(ctx, entity.NewNote(text, entity.VisibilityHome)); !errors.Is(err, repository.ErrNoteQueued) {
+			t.Fatalf("expected ErrNoteQueued, got %v", err)
+		}
+	}
+
+	noteRepo := &mockNoteRepository{}
+	restarted := NewOutboundQueue("misskey", noteRepo, store)
+	delivered, err := restarted.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivered != 3 {
+		t.Errorf("expected 3 delivered notes, got %d", delivered)
+	}
+	for i, expected := range []string{"First", "Second", "Third"} {
+		if i >= len(noteRepo.posted) || noteRepo.posted[i].Text != expected {
+			t.Fatalf("expected notes in queue order, got %v", noteRepo.posted)
+		}
+	}
+
+	pending, _ := store.List(context.Background(), outboundQueueNamespace+"misskey")
+	if len(pending) != 0 {
+		t.Errorf("expected empty queue after drain, got %d notes", len(pending))
+	}
+}
+
+func TestRSSFeedService_QueuedNoteCountsAsPosted(t *testing.T) {
+	entries := []*entity.FeedEntry{
+		entity.NewFeedEntry("Article 1", "https://example.tld/1", "Desc 1", time.Now(), "guid-1"),
+	}
+	cacheRepo := newMockCacheRepository()
+	store := newMockStateStore()
+	service := NewRSSFeedService(
+		&mockFeedRepository{entries: entries},
+		NewOutboundQueue(DefaultDestination, blockingNoteRepository{}, store),
+		cacheRepo,
+		nil,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := service.ProcessFeed(ctx, "https://example.tld/rss"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cacheRepo.processedGUIDs["guid-1"] {
+		t.Error("queued entry should be marked as processed")
+	}
+	pending, _ := store.List(context.Background(), outboundQueueNamespace+DefaultDestination)
+	if len(pending) != 1 {
+		t.Errorf("expected 1 pending note, got %d", len(pending))
+	}
+}
@@ -2,9 +2,11 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
+	"sync"
 	"time"
 
 	"misskeyRSSbot/internal/domain/entity"
@@ -16,6 +18,13 @@ type RSSFeedService struct {
 	noteRepo           repository.NoteRepository
 	cacheRepo          repository.CacheRepository
 	summarizerRepo     repository.SummarizerRepository
+	itemStateRepo      repository.ItemStateRepository
+	validatorRepo      repository.FeedValidatorRepository
+	destinations       map[string]repository.NoteRepository
+	metrics            MetricsRecorder
+	healthNotifier     *FeedHealthNotifier
+	failuresMu         sync.Mutex
+	postFailures       map[string]int
 	firstRunLatestOnly bool
 }
 
@@ -27,6 +36,20 @@ func WithFirstRunLatestOnly(enabled bool) RSSFeedServiceOption {
 	}
 }
 
+func WithItemStateRepository(repo repository.ItemStateRepository) RSSFeedServiceOption {
+	return func(s *RSSFeedService) {
+		s.itemStateRepo = repo
+	}
+}
+
+// WithFeedValidatorRepository saves the validators of each fetch once its
+// entries are handled, so a failed post is not hidden behind a 304.
+func WithFeedValidatorRepository(repo repository.FeedValidatorRepository) RSSFeedServiceOption {
+	return func(s *RSSFeedService) {
+		s.validatorRepo = repo
+	}
+}
+
 func NewRSSFeedService(
 	feedRepo repository.FeedRepository,
 	noteRepo repository.NoteRepository,
@@ -39,6 +62,7 @@ func NewRSSFeedService(
 		noteRepo:           noteRepo,
 		cacheRepo:          cacheRepo,
 		summarizerRepo:     summarizerRepo,
+		metrics:            noopMetrics{},
 		firstRunLatestOnly: true,
 	}
 	for _, opt := range opts {
@@ -48,13 +72,28 @@ func NewRSSFeedService(
 }
 
 func (s *RSSFeedService) ProcessFeed(ctx context.Context, rssURL string) error {
-	entries, err := s.feedRepo.Fetch(ctx, rssURL)
+	return s.ProcessFeedWithSettings(ctx, FeedSettings{URL: rssURL})
+}
+
+func (s *RSSFeedService) ProcessFeedWithSettings(ctx context.Context, feed FeedSettings) error {
+	err := s.processFeed(ctx, feed)
+	if s.healthNotifier != nil {
+		s.healthNotifier.Observe(ctx, feed.URL, err)
+	}
+	return err
+}
+
+func (s *RSSFeedService) processFeed(ctx context.Context, feed FeedSettings) error {
+	rssURL := feed.URL
+	entries, validators, err := s.feedRepo.Fetch(ctx, rssURL)
+	s.metrics.FeedFetched(rssURL, err)
 	if err != nil {
 		return fmt.Errorf("failed to fetch RSS feed [%s]: %w", rssURL, err)
 	}
 
 	if len(entries) == 0 {
 		log.Printf("No entries found in RSS URL: %s", rssURL)
+		s.saveValidators(ctx, validators)
 		return nil
 	}
 
@@ -64,14 +103,22 @@ func (s *RSSFeedService) ProcessFeed(ctx context.Context, rssURL string) error {
 	}
 
 	isFirstRun := latestPublished.IsZero()
-	newEntries := s.filterNewEntries(ctx, entries, latestPublished, isFirstRun)
+	sortEntriesByPublishedAsc(entries)
+	newEntries := s.filterNewEntries(ctx, feed, entries, latestPublished, isFirstRun)
+	s.metrics.ItemsDiscovered(rssURL, len(newEntries))
 
 	if len(newEntries) == 0 {
+		if isFirstRun {
+			newest := entries[len(entries)-1].Published
+			if err := s.cacheRepo.SaveLatestPublishedTime(ctx, rssURL, newest); err != nil {
+				return fmt.Errorf("failed to save latest published time: %w", err)
+			}
+		}
+		s.saveValidators(ctx, validators)
 		return nil
 	}
 
-	sortEntriesByPublishedAsc(newEntries)
-	latestTime := s.postEntries(ctx, newEntries)
+	latestTime, complete := s.postEntries(ctx, feed, newEntries)
 
 	if !latestTime.IsZero() {
 		if err := s.cacheRepo.SaveLatestPublishedTime(ctx, rssURL, latestTime); err != nil {
@@ -79,23 +126,41 @@ func (s *RSSFeedService) ProcessFeed(ctx context.Context, rssURL string) error {
 		}
 	}
 
+	if complete {
+		s.saveValidators(ctx, validators)
+	}
+
 	log.Printf("Processed %d new entries from RSS URL [%s]", len(newEntries), rssURL)
 	return nil
 }
 
+// saveValidators is only called once every entry of the fetch was handled,
+// so entries waiting for a retry are fetched again on the next poll.
+func (s *RSSFeedService) saveValidators(ctx context.Context, validators entity.FeedValidators) {
+	if s.validatorRepo == nil || validators.FeedURL == "" {
+		return
+	}
+	if err := s.validatorRepo.SaveFeedValidators(ctx, validators); err != nil {
+		log.Printf("Failed to save feed validators [%s]: %v", validators.FeedURL, err)
+	}
+}
+
+// filterNewEntries expects entries sorted oldest first. On the first run
+// only the newest backfill entries are considered.
 func (s *RSSFeedService) filterNewEntries(
 	ctx context.Context,
+	feed FeedSettings,
 	entries []*entity.FeedEntry,
 	latestPublished time.Time,
 	isFirstRun bool,
 ) []*entity.FeedEntry {
-	if isFirstRun && s.firstRunLatestOnly {
-		return s.findMostRecentEntry(entries)
+	if limit := s.backfillLimit(feed); isFirstRun && limit >= 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
 	}
 
 	var newEntries []*entity.FeedEntry
 	for _, entry := range entries {
-		if s.shouldSkipEntry(ctx, entry, latestPublished, isFirstRun) {
+		if s.shouldSkipEntry(ctx, feed.URL, entry, latestPublished, isFirstRun) {
 			continue
 		}
 		newEntries = append(newEntries, entry)
@@ -103,22 +168,21 @@ func (s *RSSFeedService) filterNewEntries(
 	return newEntries
 }
 
-func (s *RSSFeedService) findMostRecentEntry(entries []*entity.FeedEntry) []*entity.FeedEntry {
-	if len(entries) == 0 {
-		return nil
+// backfillLimit returns how many entries to post on a feed's first sync,
+// or -1 for all of them.
+func (s *RSSFeedService) backfillLimit(feed FeedSettings) int {
+	if feed.Backfill != nil {
+		return *feed.Backfill
 	}
-
-	mostRecent := entries[0]
-	for _, entry := range entries[1:] {
-		if entry.Published.After(mostRecent.Published) {
-			mostRecent = entry
-		}
+	if s.firstRunLatestOnly {
+		return 1
 	}
-	return []*entity.FeedEntry{mostRecent}
+	return -1
 }
 
 func (s *RSSFeedService) shouldSkipEntry(
 	ctx context.Context,
+	rssURL string,
 	entry *entity.FeedEntry,
 	latestPublished time.Time,
 	isFirstRun bool,
@@ -128,7 +192,7 @@ func (s *RSSFeedService) shouldSkipEntry(
 		log.Printf("Failed to check if processed [GUID: %s]: %v", entry.GUID, err)
 		return true
 	}
-	if processed {
+	if processed || s.isPosted(ctx, rssURL, entry) {
 		return true
 	}
 
@@ -139,30 +203,87 @@ func (s *RSSFeedService) shouldSkipEntry(
 	return false
 }
 
-func (s *RSSFeedService) postEntries(ctx context.Context, entries []*entity.FeedEntry) time.Time {
+func (s *RSSFeedService) isPosted(ctx context.Context, rssURL string, entry *entity.FeedEntry) bool {
+	if s.itemStateRepo == nil {
+		return false
+	}
+	posted, err := s.itemStateRepo.HasPosted(ctx, entity.NewPostedItem(rssURL, entry))
+	if err != nil {
+		log.Printf("Failed to check posted item [GUID: %s]: %v", entry.GUID, err)
+		return true
+	}
+	return posted
+}
+
+// postEntries reports false when it stopped early to retry an entry on a
+// later cycle.
+func (s *RSSFeedService) postEntries(ctx context.Context, feed FeedSettings, entries []*entity.FeedEntry) (time.Time, bool) {
 	var latestTime time.Time
+	destinations := s.destinationsFor(feed)
+	limits := make([]int, len(destinations))
+	for i, dest := range destinations {
+		limits[i] = noteTextLimit(ctx, dest.repo)
+	}
 
 	for _, entry := range entries {
+		if !feed.Filter.Allows(entry) {
+			if err := s.cacheRepo.MarkAsProcessed(ctx, entry.GUID); err != nil {
+				log.Printf("Failed to mark as processed [GUID: %s]: %v", entry.GUID, err)
+			}
+			if entry.Published.After(latestTime) {
+				latestTime = entry.Published
+			}
+			continue
+		}
+
 		summary := s.summarizeEntry(ctx, entry)
 
-		note := entity.NewNoteFromFeedWithSummary(entry, summary, entity.VisibilityHome)
-		if err := s.noteRepo.Post(ctx, note); err != nil {
-			log.Printf("Failed to post to Misskey [%s]: %v", entry.Title, err)
+		posted := 0
+		for i, dest := range destinations {
+			note := feed.buildNote(entry, summary, limits[i])
+			err := dest.repo.Post(ctx, note)
+			if errors.Is(err, repository.ErrNoteQueued) {
+				log.Printf("Queued for %s: %s", dest.name, entry.Title)
+				posted++
+				continue
+			}
+			s.metrics.NotePosted(feed.URL, dest.name, err)
+			if err != nil {
+				log.Printf("Failed to post to %s [%s]: %v", dest.name, entry.Title, err)
+				continue
+			}
+			log.Printf("Posted to %s: %s", dest.name, entry.Title)
+			posted++
+		}
+		if posted == 0 {
+			if s.retryLater(feed.URL, entry) {
+				return latestTime, false
+			}
+			if err := s.cacheRepo.MarkAsProcessed(ctx, entry.GUID); err != nil {
+				log.Printf("Failed to mark as processed [GUID: %s]: %v", entry.GUID, err)
+			}
 			continue
 		}
-
-		log.Printf("Posted to Misskey: %s", entry.Title)
+		s.clearFailures(feed.URL, entry)
+		if posted < len(destinations) {
+			log.Printf("Warning: [%s] was posted to %d of %d destinations and will not be retried", entry.Title, posted, len(destinations))
+		}
 
 		if err := s.cacheRepo.MarkAsProcessed(ctx, entry.GUID); err != nil {
 			log.Printf("Failed to mark as processed [GUID: %s]: %v", entry.GUID, err)
 		}
+		if s.itemStateRepo != nil {
+			if err := s.itemStateRepo.RecordPosted(ctx, entity.NewPostedItem(feed.URL, entry)); err != nil {
+				log.Printf("Failed to record posted item [GUID: %s]: %v", entry.GUID, err)
+			}
+		}
 
 		if entry.Published.After(latestTime) {
 			latestTime = entry.Published
 		}
 	}
 
-	return latestTime
+	return latestTime, true
 }
 
 func (s *RSSFeedService) summarizeEntry(ctx context.Context, entry *entity.FeedEntry) string {
@@ -188,8 +309,38 @@ func (s *RSSFeedService) ProcessAllFeeds(ctx context.Context, rssURLs []string)
 	return nil
 }
 
+// maxPostAttempts bounds how many cycles an entry that fails to post can
+// hold back the newer entries of its feed before it is given up on.
+const maxPostAttempts = 3
+
+// retryLater records a failed post and reports whether the rest of the
+// cycle should wait for the entry, keeping posts in publication order.
+func (s *RSSFeedService) retryLater(feedURL string, entry *entity.FeedEntry) bool {
+	s.failuresMu.Lock()
+	defer s.failuresMu.Unlock()
+
+	if s.postFailures == nil {
+		s.postFailures = make(map[string]int)
+	}
+	key := feedURL + "\x00" + entry.GUID
+	s.postFailures[key]++
+	if s.postFailures[key] < maxPostAttempts {
+		return true
+	}
+	delete(s.postFailures, key)
+	log.Printf("Warning: Giving up on [%s] after %d failed attempts", entry.Title, maxPostAttempts)
+	return false
+}
+
+func (s *RSSFeedService) clearFailures(feedURL string, entry *entity.FeedEntry) {
+	s.failuresMu.Lock()
+	defer s.failuresMu.Unlock()
+
+	delete(s.postFailures, feedURL+"\x00"+entry.GUID)
+}
+
 func sortEntriesByPublishedAsc(entries []*entity.FeedEntry) {
-	sort.Slice(entries, func(i, j int) bool {
+	sort.SliceStable(entries, func(i, j int) bool {
 		return entries[i].Published.Before(entries[j].Published)
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
 )
 
 type mockFeedRepository struct {
@@ -15,11 +16,11 @@ type mockFeedRepository struct {
 	err     error
 }
 
-func (m *mockFeedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, error) {
+func (m *mockFeedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, entity.FeedValidators, error) {
 	if m.err != nil {
-		return nil, m.err
+		return nil, entity.FeedValidators{}, m.err
 	}
-	return m.entries, nil
+	return m.entries, entity.FeedValidators{}, nil
 }
 
 type mockNoteRepository struct {
@@ -64,6 +65,28 @@ func (m *mockCacheRepository) MarkAsProcessed(ctx context.Context, guid string)
 	return nil
 }
 
+type mockItemStateRepository struct {
+	posted []entity.PostedItem
+}
+
+func (m *mockItemStateRepository) HasPosted(ctx context.Context, item entity.PostedItem) (bool, error) {
+	for _, p := range m.posted {
+		if p.FeedURL == item.FeedURL && (p.GUID == item.GUID || p.Link == item.Link) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockItemStateRepository) RecordPosted(ctx context.Context, item entity.PostedItem) error {
+	m.posted = append(m.posted, item)
+	return nil
+}
+
+func (m *mockItemStateRepository) PruneItems(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
 type mockSummarizerRepository struct {
 	summary string
 	err     error
@@ -387,3 +410,249 @@ func TestRSSFeedService_ProcessFeed_FirstRunLatestOnlyDisabled_SkipProcessed(t *
 		t.Errorf("expected 2 notes posted (skipping processed guid-1), got %d", len(noteRepo.posted))
 	}
 }
+
+func TestRSSFeedService_ProcessFeed_ItemState(t *testing.T) {
+	now := time.Now()
+	rssURL := "https://example.tld/rss"
+
+	tests := []struct {
+		name          string
+		firstRun      bool
+		recorded      []entity.PostedItem
+		expectedPosts int
+	}{
+		{"nothing recorded", false, nil, 2},
+		{"GUID recorded", false, []entity.PostedItem{{FeedURL: rssURL, GUID: "guid-1"}}, 1},
+		{"link recorded under another GUID", false, []entity.PostedItem{{FeedURL: rssURL, GUID: "old", Link: "https://example.tld/2"}}, 1},
+		{"recorded for another feed", false, []entity.PostedItem{{FeedURL: "https://example.tld/other", GUID: "guid-1"}}, 2},
+		{"first run latest already posted", true, []entity.PostedItem{{FeedURL: rssURL, GUID: "guid-2"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []*entity.FeedEntry{
+				entity.NewFeedEntry("Article 1", "https://example.tld/1", "Desc 1", now.Add(-time.Hour), "guid-1"),
+				entity.NewFeedEntry("Article 2", "https://example.tld/2", "Desc 2", now, "guid-2"),
+			}
+			noteRepo := &mockNoteRepository{}
+			cacheRepo := newMockCacheRepository()
+			if !tt.firstRun {
+				cacheRepo.latestTime = now.Add(-2 * time.Hour)
+			}
+			itemState := &mockItemStateRepository{posted: tt.recorded}
+
+			service := NewRSSFeedService(&mockFeedRepository{entries: entries}, noteRepo, cacheRepo, nil, WithItemStateRepository(itemState))
+			if err := service.ProcessFeed(context.Background(), rssURL); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(noteRepo.posted) != tt.expectedPosts {
+				t.Errorf("expected %d notes posted, got %d", tt.expectedPosts, len(noteRepo.posted))
+			}
+			if recorded := len(itemState.posted) - len(tt.recorded); recorded != tt.expectedPosts {
+				t.Errorf("expected %d items recorded, got %d", tt.expectedPosts, recorded)
+			}
+		})
+	}
+}
+
+func TestRSSFeedService_ProcessFeedWithSettings_Filter(t *testing.T) {
+	now := time.Now()
+	entries := []*entity.FeedEntry{
+		entity.NewFeedEntry("Go release", "https://example.tld/1", "", now.Add(-2*time.Minute), "guid-1"),
+		entity.NewFeedEntry("Sponsored post", "https://example.tld/2", "", now.Add(-time.Minute), "guid-2"),
+	}
+	rule, err := entity.NewFilterRule([]string{"title"}, "go", "")
+	if err != nil {
+		t.Fatalf("failed to build rule: %v", err)
+	}
+	filter, err := entity.NewFeedFilter("", []entity.FilterRule{rule}, nil)
+	if err != nil {
+		t.Fatalf("failed to build filter: %v", err)
+	}
+
+	noteRepo := &mockNoteRepository{}
+	cacheRepo := newMockCacheRepository()
+	cacheRepo.latestTime = now.Add(-time.Hour)
+	service := NewRSSFeedService(&mockFeedRepository{entries: entries}, noteRepo, cacheRepo, nil)
+
+	if err := service.ProcessFeedWithSettings(context.Background(), FeedSettings{URL: "https://example.tld/rss", Filter: filter}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(noteRepo.posted) != 1 || !strings.Contains(noteRepo.posted[0].Text, "Go release") {
+		t.Errorf("expected only the matching entry to be posted, got %+v", noteRepo.posted)
+	}
+	if !cacheRepo.processedGUIDs["guid-2"] {
+		t.Error("expected the filtered entry to be marked as seen")
+	}
+	if !cacheRepo.latestTime.Equal(entries[1].Published) {
+		t.Errorf("expected latest time to include the filtered entry, got %v", cacheRepo.latestTime)
+	}
+}
+
+func TestRSSFeedService_ProcessFeedWithSettings_Backfill(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name           string
+		latestOnly     bool
+		backfill       *int
+		expectedTitles []string
+	}{
+		{"default latest only", true, nil, []string{"Article 3"}},
+		{"default all", false, nil, []string{"Article 1", "Article 2", "Article 3"}},
+		{"zero posts nothing", false, intPtr(0), nil},
+		{"limit keeps newest oldest-first", true, intPtr(2), []string{"Article 2", "Article 3"}},
+		{"limit above feed size", true, intPtr(10), []string{"Article 1", "Article 2", "Article 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			entries := []*entity.FeedEntry{
+				entity.NewFeedEntry("Article 3", "https://example.tld/3", "", now, "guid-3"),
+				entity.NewFeedEntry("Article 1", "https://example.tld/1", "", now.Add(-2*time.Hour), "guid-1"),
+				entity.NewFeedEntry("Article 2", "https://example.tld/2", "", now.Add(-time.Hour), "guid-2"),
+			}
+			noteRepo := &mockNoteRepository{}
+			cacheRepo := newMockCacheRepository()
+			service := NewRSSFeedService(&mockFeedRepository{entries: entries}, noteRepo, cacheRepo, nil, WithFirstRunLatestOnly(tt.latestOnly))
+
+			if err := service.ProcessFeedWithSettings(context.Background(), FeedSettings{URL: "https://example.tld/rss", Backfill: tt.backfill}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(noteRepo.posted) != len(tt.expectedTitles) {
+				t.Fatalf("expected %d posts, got %d", len(tt.expectedTitles), len(noteRepo.posted))
+			}
+			for i, title := range tt.expectedTitles {
+				if !strings.Contains(noteRepo.posted[i].Text, title) {
+					t.Errorf("post %d: expected %s, got %q", i, title, noteRepo.posted[i].Text)
+				}
+			}
+			if !cacheRepo.latestTime.Equal(now) {
+				t.Errorf("expected the first sync to advance to the newest entry, got %v", cacheRepo.latestTime)
+			}
+		})
+	}
+}
+
+type flakyNoteRepository struct {
+	mockNoteRepository
+	failures map[string]int
+}
+
+func (m *flakyNoteRepository) Post(ctx context.Context, note *entity.Note) error {
+	for title, remaining := range m.failures {
+		if strings.Contains(note.Text, title) && remaining > 0 {
+			m.failures[title]--
+			return errors.New("temporary failure")
+		}
+	}
+	return m.mockNoteRepository.Post(ctx, note)
+}
+
+func TestRSSFeedService_ProcessFeed_PreservesOrderOnFailure(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       int
+		cycles         int
+		expectedTitles []string
+	}{
+		{"recovers in order", 1, 2, []string{"Article 1", "Article 2", "Article 3"}},
+		{"waits while failing", 2, 2, nil},
+		{"gives up after max attempts", maxPostAttempts, maxPostAttempts, []string{"Article 2", "Article 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			entries := []*entity.FeedEntry{
+				entity.NewFeedEntry("Article 1", "https://example.tld/1", "", now.Add(-2*time.Hour), "guid-1"),
+				entity.NewFeedEntry("Article 2", "https://example.tld/2", "", now.Add(-time.Hour), "guid-2"),
+				entity.NewFeedEntry("Article 3", "https://example.tld/3", "", now, "guid-3"),
+			}
+			noteRepo := &flakyNoteRepository{failures: map[string]int{"Article 1": tt.failures}}
+			cacheRepo := newMockCacheRepository()
+			cacheRepo.latestTime = now.Add(-3 * time.Hour)
+			service := NewRSSFeedService(&mockFeedRepository{entries: entries}, noteRepo, cacheRepo, nil)
+
+			for i := 0; i < tt.cycles; i++ {
+				if err := service.ProcessFeed(context.Background(), "https://example.tld/rss"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if len(noteRepo.posted) != len(tt.expectedTitles) {
+				t.Fatalf("expected %d posts, got %d", len(tt.expectedTitles), len(noteRepo.posted))
+			}
+			for i, title := range tt.expectedTitles {
+				if !strings.Contains(noteRepo.posted[i].Text, title) {
+					t.Errorf("post %d: expected %s, got %q", i, title, noteRepo.posted[i].Text)
+				}
+			}
+		})
+	}
+}
+
+type conditionalFeedRepository struct {
+	entries []*entity.FeedEntry
+	etag    string
+	saved   map[string]entity.FeedValidators
+}
+
+func (m *conditionalFeedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, entity.FeedValidators, error) {
+	if m.saved[url].ETag == m.etag {
+		return nil, entity.FeedValidators{}, repository.ErrFeedNotModified
+	}
+	return m.entries, entity.FeedValidators{FeedURL: url, ETag: m.etag}, nil
+}
+
+func (m *conditionalFeedRepository) GetFeedValidators(ctx context.Context, feedURL string) (entity.FeedValidators, error) {
+	return m.saved[feedURL], nil
+}
+
+func (m *conditionalFeedRepository) SaveFeedValidators(ctx context.Context, validators entity.FeedValidators) error {
+	m.saved[validators.FeedURL] = validators
+	return nil
+}
+
+func TestRSSFeedService_ProcessFeed_SavesValidatorsAfterPosting(t *testing.T) {
+	const rssURL = "https://example.tld/rss"
+	now := time.Now()
+	feedRepo := &conditionalFeedRepository{
+		entries: []*entity.FeedEntry{entity.NewFeedEntry("Article 1", "https://example.tld/1", "", now, "guid-1")},
+		etag:    `"v1"`,
+		saved:   make(map[string]entity.FeedValidators),
+	}
+	noteRepo := &flakyNoteRepository{failures: map[string]int{"Article 1": 1}}
+	cacheRepo := newMockCacheRepository()
+	cacheRepo.latestTime = now.Add(-time.Hour)
+	service := NewRSSFeedService(feedRepo, noteRepo, cacheRepo, nil, WithFeedValidatorRepository(feedRepo))
+
+	steps := []struct {
+		name          string
+		expectedErr   error
+		expectedPosts int
+		expectSaved   bool
+	}{
+		{"failed post keeps the feed unconditional", nil, 0, false},
+		{"retry posts the entry", nil, 1, true},
+		{"unchanged feed answers 304", repository.ErrFeedNotModified, 1, true},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			err := service.ProcessFeed(context.Background(), rssURL)
+			if !errors.Is(err, step.expectedErr) {
+				t.Fatalf("expected error %v, got %v", step.expectedErr, err)
+			}
+			if len(noteRepo.posted) != step.expectedPosts {
+				t.Errorf("expected %d posts, got %d", step.expectedPosts, len(noteRepo.posted))
+			}
+			if _, saved := feedRepo.saved[rssURL]; saved != step.expectSaved {
+				t.Errorf("expected validators saved=%v, got %+v", step.expectSaved, feedRepo.saved)
+			}
+		})
+	}
+}
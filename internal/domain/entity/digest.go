@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultDigestMaxLength = 3000
+	digestFooterReserve    = 16
+	digestSeparator        = "\n\n"
+)
+
+type DigestItem struct {
+	Title string
+	Link  string
+}
+
+func BuildDigest(items []DigestItem, visibility NoteVisibility, maxLength int) *Note {
+	pages := paginateDigest(items, maxLength)
+	if len(pages) == 0 {
+		return nil
+	}
+
+	text := strings.Join(pages[0], digestSeparator)
+	if remaining := len(items) - len(pages[0]); remaining > 0 {
+		text += fmt.Sprintf("%s…ほか%d件", digestSeparator, remaining)
+	}
+	return &Note{
+		Text:       text,
+		CW:         digestCW(len(items)),
+		Visibility: visibility,
+	}
+}
+
+// BuildDigestThread returns the digest pages without reply links; post them
+// with a thread poster so its depth limit and failure mode apply.
+func BuildDigestThread(items []DigestItem, visibility NoteVisibility, maxLength int) []*Note {
+	pages := paginateDigest(items, maxLength)
+
+	notes := make([]*Note, 0, len(pages))
+	for i, page := range pages {
+		text := strings.Join(page, digestSeparator)
+		if len(pages) > 1 {
+			text += fmt.Sprintf("%s(%d/%d)", digestSeparator, i+1, len(pages))
+		}
+		notes = append(notes, &Note{
+			Text:       text,
+			CW:         digestCW(len(items)),
+			Visibility: visibility,
+		})
+	}
+	return notes
+}
+
+func paginateDigest(items []DigestItem, maxLength int) [][]string {
+	if len(items) == 0 {
+		return nil
+	}
+	if maxLength <= 0 {
+		maxLength = defaultDigestMaxLength
+	}
+	budget := max(maxLength-digestFooterReserve, 1)
+	separatorLen := utf8.RuneCountInString(digestSeparator)
+
+	var pages [][]string
+	var page []string
+	length := 0
+	for _, item := range items {
+		entry := TruncateRunes(fmt.Sprintf("・%s\n%s", item.Title, item.Link), budget)
+		n := utf8.RuneCountInString(entry)
+		if len(page) > 0 && length+separatorLen+n > budget {
+			pages = append(pages, page)
+			page, length = nil, 0
+		}
+		if len(page) > 0 {
+			length += separatorLen
+		}
+		page = append(page, entry)
+		length += n
+	}
+	return append(pages, page)
+}
+
+func digestCW(count int) string {
+	return fmt.Sprintf("📰 まとめ (%d件)", count)
+}
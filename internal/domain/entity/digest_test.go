@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func newDigestItems(n int) []DigestItem {
+	items := make([]DigestItem, n)
+	for i := range items {
+		items[i] = DigestItem{Title: fmt.Sprintf("Article %d", i+1), Link: fmt.Sprintf("https://example.tld/%d", i+1)}
+	}
+	return items
+}
+
+func TestBuildDigest(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []DigestItem
+		maxLength int
+		expected  string
+	}{
+		{"single item", newDigestItems(1), 0, "・Article 1\nhttps://example.tld/1"},
+		{"multiple items", newDigestItems(2), 0, "・Article 1\nhttps://example.tld/1\n\n・Article 2\nhttps://example.tld/2"},
+		{"truncated with footer", newDigestItems(3), 90, "・Article 1\nhttps://example.tld/1\n\n・Article 2\nhttps://example.tld/2\n\n…ほか1件"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := BuildDigest(tt.items, VisibilityHome, tt.maxLength)
+			if note.Text != tt.expected {
+				t.Errorf("expected text %q, got %q", tt.expected, note.Text)
+			}
+			if expectedCW := fmt.Sprintf("📰 まとめ (%d件)", len(tt.items)); note.CW != expectedCW {
+				t.Errorf("expected cw %q, got %q", expectedCW, note.CW)
+			}
+			if note.Visibility != VisibilityHome {
+				t.Errorf("expected visibility %v, got %v", VisibilityHome, note.Visibility)
+			}
+			if tt.maxLength > 0 && utf8.RuneCountInString(note.Text) > tt.maxLength {
+				t.Errorf("expected text within %d runes, got %d", tt.maxLength, utf8.RuneCountInString(note.Text))
+			}
+		})
+	}
+
+	if note := BuildDigest(nil, VisibilityHome, 0); note != nil {
+		t.Errorf("expected nil note for empty digest, got %+v", note)
+	}
+}
+
+func TestBuildDigestThread(t *testing.T) {
+	tests := []struct {
+		name          string
+		items         []DigestItem
+		maxLength     int
+		expectedNotes int
+	}{
+		{"fits in one note", newDigestItems(3), 0, 1},
+		{"overflows into thread", newDigestItems(5), 90, 3},
+		{"oversized item truncated", []DigestItem{{Title: strings.Repeat("a", 200), Link: "https://example.tld"}}, 50, 1},
+		{"empty", nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := BuildDigestThread(tt.items, VisibilityHome, tt.maxLength)
+			if len(notes) != tt.expectedNotes {
+				t.Fatalf("expected %d notes, got %d", tt.expectedNotes, len(notes))
+			}
+			for i, note := range notes {
+				if tt.maxLength > 0 && utf8.RuneCountInString(note.Text) > tt.maxLength {
+					t.Errorf("note %d: expected text within %d runes, got %d", i, tt.maxLength, utf8.RuneCountInString(note.Text))
+				}
+				if len(notes) > 1 && !strings.HasSuffix(note.Text, fmt.Sprintf("(%d/%d)", i+1, len(notes))) {
+					t.Errorf("note %d: expected page footer, got %q", i, note.Text)
+				}
+			}
+		})
+	}
+}
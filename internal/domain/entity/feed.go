@@ -1,13 +1,27 @@
 package entity
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
 
+// FeedEntry is an item of an RSS, Atom or JSON Feed. Description holds the
+// summary, or the content when the feed has no summary. Published falls
+// back to the updated date for entries without a publication date.
 type FeedEntry struct {
 	Title       string
 	Link        string
 	Description string
+	Content     string
 	Published   time.Time
+	Updated     time.Time
 	GUID        string
+	Categories  []string
+	Author      string
+	// AttachmentURLs are media URLs the feed attached to the entry, such as
+	// image enclosures or the item image.
+	AttachmentURLs []string
 }
 
 func NewFeedEntry(title, link, description string, published time.Time, guid string) *FeedEntry {
@@ -23,3 +37,30 @@ func NewFeedEntry(title, link, description string, published time.Time, guid str
 func (f *FeedEntry) IsNewerThan(t time.Time) bool {
 	return f.Published.After(t)
 }
+
+type FeedValidators struct {
+	FeedURL      string
+	ETag         string
+	LastModified string
+}
+
+func (v FeedValidators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+type PostedItem struct {
+	FeedURL     string
+	GUID        string
+	Link        string
+	ContentHash string
+}
+
+func NewPostedItem(feedURL string, entry *FeedEntry) PostedItem {
+	sum := sha256.Sum256([]byte(entry.Title + "\n" + entry.Description))
+	return PostedItem{
+		FeedURL:     feedURL,
+		GUID:        entry.GUID,
+		Link:        entry.Link,
+		ContentHash: hex.EncodeToString(sum[:]),
+	}
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type FilterField string
+
+const (
+	FilterFieldTitle       FilterField = "title"
+	FilterFieldDescription FilterField = "description"
+	FilterFieldCategories  FilterField = "categories"
+	FilterFieldAuthor      FilterField = "author"
+)
+
+func filterFields() []FilterField {
+	return []FilterField{FilterFieldTitle, FilterFieldDescription, FilterFieldCategories, FilterFieldAuthor}
+}
+
+type FilterMatch string
+
+const (
+	FilterMatchAny FilterMatch = "any"
+	FilterMatchAll FilterMatch = "all"
+)
+
+// FilterRule matches an entry when the keyword (case-insensitive) or the
+// pattern is found in any of its fields; no fields means all of them.
+type FilterRule struct {
+	Fields  []FilterField
+	Keyword string
+	Pattern *regexp.Regexp
+}
+
+func NewFilterRule(fields []string, keyword, pattern string) (FilterRule, error) {
+	if (keyword == "") == (pattern == "") {
+		return FilterRule{}, fmt.Errorf("filter rule needs exactly one of keyword or regex")
+	}
+
+	rule := FilterRule{Keyword: strings.ToLower(keyword)}
+	for _, field := range fields {
+		if !slices.Contains(filterFields(), FilterField(field)) {
+			return FilterRule{}, fmt.Errorf("unsupported filter field %q", field)
+		}
+		rule.Fields = append(rule.Fields, FilterField(field))
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return FilterRule{}, fmt.Errorf("invalid filter regex %q: %w", pattern, err)
+		}
+		rule.Pattern = re
+	}
+	return rule, nil
+}
+
+func (r FilterRule) Matches(entry *FeedEntry) bool {
+	fields := r.Fields
+	if len(fields) == 0 {
+		fields = filterFields()
+	}
+	for _, field := range fields {
+		for _, value := range entry.filterValues(field) {
+			if r.matchesValue(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r FilterRule) matchesValue(value string) bool {
+	if r.Pattern != nil {
+		return r.Pattern.MatchString(value)
+	}
+	return strings.Contains(strings.ToLower(value), r.Keyword)
+}
+
+func (f *FeedEntry) filterValues(field FilterField) []string {
+	switch field {
+	case FilterFieldTitle:
+		return []string{f.Title}
+	case FilterFieldDescription:
+		return []string{f.Description}
+	case FilterFieldCategories:
+		return f.Categories
+	case FilterFieldAuthor:
+		return []string{f.Author}
+	}
+	return nil
+}
+
+// FeedFilter lets an entry through when no exclude rule matches and the
+// include rules match: any of them by default, or all of them with
+// FilterMatchAll. A filter without include rules keeps every entry that
+// is not excluded.
+type FeedFilter struct {
+	Match   FilterMatch
+	Include []FilterRule
+	Exclude []FilterRule
+}
+
+func NewFeedFilter(match string, include, exclude []FilterRule) (*FeedFilter, error) {
+	switch FilterMatch(match) {
+	case "", FilterMatchAny, FilterMatchAll:
+	default:
+		return nil, fmt.Errorf("unsupported filter match %q", match)
+	}
+	return &FeedFilter{Match: FilterMatch(match), Include: include, Exclude: exclude}, nil
+}
+
+func (f *FeedFilter) Allows(entry *FeedEntry) bool {
+	if f == nil {
+		return true
+	}
+	for _, rule := range f.Exclude {
+		if rule.Matches(entry) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, rule := range f.Include {
+		if rule.Matches(entry) {
+			matched++
+		}
+	}
+	if f.Match == FilterMatchAll {
+		return matched == len(f.Include)
+	}
+	return matched > 0
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func mustFilterRule(t *testing.T, fields []string, keyword, pattern string) FilterRule {
+	t.Helper()
+	rule, err := NewFilterRule(fields, keyword, pattern)
+	if err != nil {
+		t.Fatalf("failed to build rule: %v", err)
+	}
+	return rule
+}
+
+func TestNewFilterRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    []string
+		keyword   string
+		pattern   string
+		expectErr bool
+	}{
+		{"keyword", nil, "go", "", false},
+		{"regex with fields", []string{"title", "author"}, "", `^Go \d`, false},
+		{"neither", nil, "", "", true},
+		{"both", nil, "go", "go", true},
+		{"invalid regex", nil, "", "(", true},
+		{"unknown field", []string{"link"}, "go", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFilterRule(tt.fields, tt.keyword, tt.pattern)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestFeedFilter_Allows(t *testing.T) {
+	entry := NewFeedEntry("Go 1.24 released", "https://example.tld/1", "Faster builds and <b>generics</b>", time.Now(), "guid-1")
+	entry.Categories = []string{"Programming", "Release"}
+	entry.Author = "Gopher"
+
+	tests := []struct {
+		name     string
+		match    string
+		include  []FilterRule
+		exclude  []FilterRule
+		expected bool
+	}{
+		{"no rules", "", nil, nil, true},
+		{"keyword is case-insensitive", "", []FilterRule{mustFilterRule(t, nil, "GO 1.24", "")}, nil, true},
+		{"keyword in other field only", "", []FilterRule{mustFilterRule(t, []string{"title"}, "generics", "")}, nil, false},
+		{"category match", "", []FilterRule{mustFilterRule(t, []string{"categories"}, "release", "")}, nil, true},
+		{"author regex", "", []FilterRule{mustFilterRule(t, []string{"author"}, "", "^Goph")}, nil, true},
+		{"any of includes", "any", []FilterRule{mustFilterRule(t, nil, "rust", ""), mustFilterRule(t, nil, "generics", "")}, nil, true},
+		{"all of includes fails", "all", []FilterRule{mustFilterRule(t, nil, "rust", ""), mustFilterRule(t, nil, "generics", "")}, nil, false},
+		{"all of includes passes", "all", []FilterRule{mustFilterRule(t, nil, "go", ""), mustFilterRule(t, nil, "generics", "")}, nil, true},
+		{"exclude wins", "", []FilterRule{mustFilterRule(t, nil, "go", "")}, []FilterRule{mustFilterRule(t, []string{"categories"}, "", "(?i)^release$")}, false},
+		{"exclude only", "", nil, []FilterRule{mustFilterRule(t, nil, "sponsored", "")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewFeedFilter(tt.match, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := filter.Allows(entry); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFeedFilter_NilAllows(t *testing.T) {
+	var filter *FeedFilter
+	if !filter.Allows(NewFeedEntry("Title", "https://example.tld/1", "", time.Now(), "guid-1")) {
+		t.Error("expected a nil filter to allow every entry")
+	}
+	if _, err := NewFeedFilter("most", nil, nil); err == nil {
+		t.Error("expected error for unsupported match mode")
+	}
+}
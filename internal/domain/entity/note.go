@@ -1,6 +1,9 @@
 package entity
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type NoteVisibility string
 
@@ -11,16 +14,66 @@ const (
 	VisibilitySpecified NoteVisibility = "specified"
 )
 
+type ReactionAcceptance string
+
+const (
+	ReactionAcceptanceAll                  ReactionAcceptance = ""
+	ReactionAcceptanceLikeOnly             ReactionAcceptance = "likeOnly"
+	ReactionAcceptanceLikeOnlyForRemote    ReactionAcceptance = "likeOnlyForRemote"
+	ReactionAcceptanceNonSensitiveOnly     ReactionAcceptance = "nonSensitiveOnly"
+	ReactionAcceptanceNonSensitiveLikeOnly ReactionAcceptance = "nonSensitiveOnlyForLocalLikeOnlyForRemote"
+)
+
 type Note struct {
-	Text       string
-	Visibility NoteVisibility
+	ID                 string
+	UserID             string
+	Username           string
+	Text               string
+	CW                 string
+	Visibility         NoteVisibility
+	ReplyID            string
+	RenoteID           string
+	ChannelID          string
+	FileIDs            []string
+	AttachmentURLs     []string
+	VisibleUserIDs     []string
+	Mentions           []string
+	Category           string
+	Lang               string
+	ScheduledAt        *time.Time
+	PublishedAt        *time.Time
+	Poll               *Poll
+	ReactionAcceptance ReactionAcceptance
+	// Federate is independent of Visibility: nil keeps the repository
+	// default, true posts with localOnly=false and false with localOnly=true.
+	Federate *bool
+}
+
+type Poll struct {
+	Choices   []string
+	Multiple  bool
+	ExpiresAt *time.Time
+}
+
+func (n *Note) SetFederate(federate bool) {
+	n.Federate = &federate
+}
+
+func publishedAt(entry *FeedEntry) *time.Time {
+	if entry.Published.IsZero() {
+		return nil
+	}
+	published := entry.Published
+	return &published
 }
 
 func NewNoteFromFeed(entry *FeedEntry, visibility NoteVisibility) *Note {
 	text := fmt.Sprintf("📰 %s\n%s", entry.Title, entry.Link)
 	return &Note{
-		Text:       text,
-		Visibility: visibility,
+		Text:           text,
+		Visibility:     visibility,
+		AttachmentURLs: entry.AttachmentURLs,
+		PublishedAt:    publishedAt(entry),
 	}
 }
 
@@ -37,7 +90,9 @@ func NewNoteFromFeedWithSummary(entry *FeedEntry, summary string, visibility Not
 	}
 	text := fmt.Sprintf("📰 %s\n\n【要約】\n%s\n\n%s", entry.Title, summary, entry.Link)
 	return &Note{
-		Text:       text,
-		Visibility: visibility,
+		Text:           text,
+		Visibility:     visibility,
+		AttachmentURLs: entry.AttachmentURLs,
+		PublishedAt:    publishedAt(entry),
 	}
 }
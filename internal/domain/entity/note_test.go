@@ -19,6 +19,18 @@ func TestNewNoteFromFeed(t *testing.T) {
 	if note.Visibility != VisibilityHome {
 		t.Errorf("expected visibility %v, got %v", VisibilityHome, note.Visibility)
 	}
+
+	if note.PublishedAt == nil || !note.PublishedAt.Equal(now) {
+		t.Errorf("expected published time %v, got %v", now, note.PublishedAt)
+	}
+}
+
+func TestNewNoteFromFeed_NoPublishedTime(t *testing.T) {
+	entry := NewFeedEntry("Test Article", "https://example.tld/article", "Description", time.Time{}, "guid-1")
+
+	if note := NewNoteFromFeed(entry, VisibilityHome); note.PublishedAt != nil {
+		t.Errorf("expected no published time, got %v", note.PublishedAt)
+	}
 }
 
 func TestNewNote(t *testing.T) {
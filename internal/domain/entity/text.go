@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+const truncationEllipsis = "…"
+
+// TruncateRunes shortens s to at most limit runes, ending it with an
+// ellipsis when there is room for one. A limit of zero or less yields "".
+func TruncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	if limit <= 0 {
+		return ""
+	}
+	if limit == 1 {
+		return string(runes[:1])
+	}
+	return strings.TrimRightFunc(string(runes[:limit-1]), unicode.IsSpace) + truncationEllipsis
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "testing"
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		limit    int
+		expected string
+	}{
+		{"within limit", "hello", 10, "hello"},
+		{"exact limit", "hello", 5, "hello"},
+		{"truncated with ellipsis", "hello world", 6, "hello…"},
+		{"trailing space trimmed", "hello world", 7, "hello…"},
+		{"multibyte", "こんにちは世界", 4, "こんに…"},
+		{"zero limit", "hello", 0, ""},
+		{"negative limit", "hello", -3, ""},
+		{"limit of one", "hello", 1, "h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateRunes(tt.input, tt.limit); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DedupeStore implementations must be safe for concurrent use. Remember with
+// a ttl of zero or less keeps the entry until it is overwritten; once an
+// entry expires, Seen and Get report it as absent.
+type DedupeStore interface {
+	Seen(ctx context.Context, key string) (bool, error)
+	Remember(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+}
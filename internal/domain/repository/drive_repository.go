@@ -0,0 +1,7 @@
+package repository
+
+import "context"
+
+type DriveRepository interface {
+	UploadFromURL(ctx context.Context, url string) (string, error)
+}
@@ -2,10 +2,23 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"misskeyRSSbot/internal/domain/entity"
 )
 
+var ErrFeedNotModified = errors.New("feed not modified")
+
+// FeedRepository fetches feed entries. Fetch also returns the validators
+// the response carried when they differ from the stored ones, so callers can
+// save them once the entries are handled; otherwise they are zero.
 type FeedRepository interface {
-	Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, error)
+	Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, entity.FeedValidators, error)
+}
+
+// FeedValidatorRepository persists the HTTP validators of each feed so
+// fetches can be made conditional across restarts.
+type FeedValidatorRepository interface {
+	GetFeedValidators(ctx context.Context, feedURL string) (entity.FeedValidators, error)
+	SaveFeedValidators(ctx context.Context, validators entity.FeedValidators) error
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+// ItemStateRepository records posted feed items per feed. HasPosted reports
+// an item whose GUID, link or content hash matches one recorded for the same
+// feed.
+type ItemStateRepository interface {
+	HasPosted(ctx context.Context, item entity.PostedItem) (bool, error)
+	RecordPosted(ctx context.Context, item entity.PostedItem) error
+	PruneItems(ctx context.Context, olderThan time.Duration) (int64, error)
+}
@@ -2,10 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"misskeyRSSbot/internal/domain/entity"
 )
 
+// ErrNoteQueued reports that a note was not posted yet but is stored and
+// will be delivered later.
+var ErrNoteQueued = errors.New("note queued for later delivery")
+
 type NoteRepository interface {
 	Post(ctx context.Context, note *entity.Note) error
 }
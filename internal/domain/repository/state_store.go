@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+type StateStore interface {
+	Get(ctx context.Context, namespace, key string) ([]byte, bool, error)
+	Put(ctx context.Context, namespace, key string, value []byte) error
+	Delete(ctx context.Context, namespace, key string) error
+	List(ctx context.Context, namespace string) (map[string][]byte, error)
+}
@@ -0,0 +1,39 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrHostRequired        = errors.New("mastodon host is required")
+	ErrAccessTokenRequired = errors.New("mastodon access token is required")
+	ErrInvalidRateLimit    = errors.New("invalid rate limit")
+)
+
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("mastodon API returned non-OK status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("mastodon API returned non-OK status: %d (%s)", e.StatusCode, e.Message)
+}
+
+func parseAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errBody); err == nil {
+		apiErr.Message = errBody.Error
+	}
+	return apiErr
+}
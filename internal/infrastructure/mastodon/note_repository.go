@@ -0,0 +1,228 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const defaultMaxCharacters = 500
+
+type Config struct {
+	Host           string
+	AccessToken    string
+	MaxPermits     int
+	RefillInterval time.Duration
+	MaxRetries     int
+	Client         *http.Client
+}
+
+type noteRepository struct {
+	host        string
+	accessToken string
+	client      *http.Client
+	rateLimiter *rateLimiter
+	maxRetries  int
+
+	limitOnce     sync.Once
+	maxCharacters int
+}
+
+func NewNoteRepository(cfg Config) (repository.NoteRepository, error) {
+	if cfg.Host == "" {
+		return nil, ErrHostRequired
+	}
+	if cfg.AccessToken == "" {
+		return nil, ErrAccessTokenRequired
+	}
+
+	host := strings.TrimRight(cfg.Host, "/")
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "https://" + host
+	}
+	maxPermits := cfg.MaxPermits
+	if maxPermits == 0 {
+		maxPermits = 3
+	}
+	refillInterval := cfg.RefillInterval
+	if refillInterval == 0 {
+		refillInterval = 10 * time.Second
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &noteRepository{
+		host:        host,
+		accessToken: cfg.AccessToken,
+		client:      client,
+		rateLimiter: newRateLimiter(maxPermits, refillInterval),
+		maxRetries:  cfg.MaxRetries,
+	}, nil
+}
+
+func (r *noteRepository) Post(ctx context.Context, note *entity.Note) error {
+	payload := statusPayload(note)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+	idempotencyKey := idempotencyKey(body)
+
+	for attempt := 0; ; attempt++ {
+		if err := r.rateLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limiter wait failed: %w", err)
+		}
+
+		err := r.postStatus(ctx, body, idempotencyKey)
+		var apiErr *APIError
+		if err == nil || !errors.As(err, &apiErr) || !retryable(apiErr) || attempt >= r.maxRetries {
+			return err
+		}
+		log.Printf("Retrying Mastodon status in %v (attempt %d/%d): %v", apiErr.RetryAfter, attempt+1, r.maxRetries, err)
+		r.rateLimiter.blockUntil(time.Now().Add(apiErr.RetryAfter))
+	}
+}
+
+func (r *noteRepository) postStatus(ctx context.Context, body []byte, idempotencyKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.host+"/api/v1/statuses", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.accessToken)
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := parseAPIError(resp.StatusCode, respBody)
+		apiErr.RetryAfter = retryAfter(resp.Header, time.Now())
+		return apiErr
+	}
+	return nil
+}
+
+func (r *noteRepository) MaxNoteLength(ctx context.Context) int {
+	r.limitOnce.Do(func() {
+		r.maxCharacters = r.fetchMaxCharacters(ctx)
+	})
+	return r.maxCharacters
+}
+
+func (r *noteRepository) fetchMaxCharacters(ctx context.Context) int {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.host+"/api/v1/instance", nil)
+	if err != nil {
+		return defaultMaxCharacters
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		log.Printf("Warning: Could not fetch Mastodon character limit, assuming %d: %v", defaultMaxCharacters, err)
+		return defaultMaxCharacters
+	}
+	defer resp.Body.Close()
+
+	var instance struct {
+		Configuration struct {
+			Statuses struct {
+				MaxCharacters int `json:"max_characters"`
+			} `json:"statuses"`
+		} `json:"configuration"`
+		MaxTootChars int `json:"max_toot_chars"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&instance) != nil {
+		return defaultMaxCharacters
+	}
+	if limit := instance.Configuration.Statuses.MaxCharacters; limit > 0 {
+		return limit
+	}
+	if instance.MaxTootChars > 0 {
+		return instance.MaxTootChars
+	}
+	return defaultMaxCharacters
+}
+
+func statusPayload(note *entity.Note) map[string]interface{} {
+	payload := map[string]interface{}{
+		"status":     note.Text,
+		"visibility": statusVisibility(note.Visibility),
+	}
+	if note.CW != "" {
+		payload["spoiler_text"] = note.CW
+		payload["sensitive"] = true
+	}
+	if note.ReplyID != "" {
+		payload["in_reply_to_id"] = note.ReplyID
+	}
+	if note.Lang != "" {
+		payload["language"] = note.Lang
+	}
+	if note.ScheduledAt != nil {
+		payload["scheduled_at"] = note.ScheduledAt.UTC().Format(time.RFC3339)
+	}
+	return payload
+}
+
+func statusVisibility(visibility entity.NoteVisibility) string {
+	switch visibility {
+	case entity.VisibilityPublic:
+		return "public"
+	case entity.VisibilityFollowers:
+		return "private"
+	case entity.VisibilitySpecified:
+		return "direct"
+	default:
+		return "unlisted"
+	}
+}
+
+func idempotencyKey(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func retryable(err *APIError) bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= http.StatusInternalServerError
+}
+
+// retryAfter prefers Retry-After and falls back to Mastodon's
+// X-RateLimit-Reset timestamp; it defaults to one second.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if value := h.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(at.Sub(now), 0)
+		}
+	}
+	if value := h.Get("X-RateLimit-Reset"); value != "" {
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			return max(at.Sub(now), 0)
+		}
+	}
+	return time.Second
+}
@@ -0,0 +1,215 @@
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNewNoteRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectedErr error
+	}{
+		{"valid", Config{Host: "mastodon.example.tld", AccessToken: "token"}, nil},
+		{"missing host", Config{AccessToken: "token"}, ErrHostRequired},
+		{"missing token", Config{Host: "mastodon.example.tld"}, ErrAccessTokenRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNoteRepository(tt.cfg)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post(t *testing.T) {
+	var received map[string]interface{}
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		headers = r.Header
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	repo, err := NewNoteRepository(Config{Host: server.URL, AccessToken: "test-token"})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	note := entity.NewNote("Hello", entity.VisibilityHome)
+	note.CW = "Spoilers"
+	note.Lang = "ja"
+	if err := repo.Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers.Get("Authorization") != "Bearer test-token" {
+		t.Errorf("expected bearer auth, got %q", headers.Get("Authorization"))
+	}
+	if headers.Get("Idempotency-Key") == "" {
+		t.Error("expected an idempotency key")
+	}
+	expected := map[string]interface{}{
+		"status":       "Hello",
+		"visibility":   "unlisted",
+		"spoiler_text": "Spoilers",
+		"sensitive":    true,
+		"language":     "ja",
+	}
+	for key, value := range expected {
+		if received[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, received[key])
+		}
+	}
+}
+
+func TestStatusVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility entity.NoteVisibility
+		expected   string
+	}{
+		{"public", entity.VisibilityPublic, "public"},
+		{"home", entity.VisibilityHome, "unlisted"},
+		{"followers", entity.VisibilityFollowers, "private"},
+		{"specified", entity.VisibilitySpecified, "direct"},
+		{"empty", "", "unlisted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusVisibility(tt.visibility); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		maxRetries   int
+		expectErr    bool
+		expectedHits int
+	}{
+		{"429 retried", http.StatusTooManyRequests, 1, false, 2},
+		{"5xx retried", http.StatusBadGateway, 1, false, 2},
+		{"retries exhausted", http.StatusTooManyRequests, 0, true, 1},
+		{"client error not retried", http.StatusUnprocessableEntity, 3, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := 0
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+				if hits == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"error": "nope"}`))
+					return
+				}
+				w.Write([]byte(`{"id": "1"}`))
+			}))
+			defer server.Close()
+
+			repo, err := NewNoteRepository(Config{Host: server.URL, AccessToken: "test-token", MaxRetries: tt.maxRetries})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+
+			err = repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityPublic))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			var apiErr *APIError
+			if tt.expectErr && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != "nope") {
+				t.Errorf("expected APIError with status %d, got %v", tt.status, err)
+			}
+			if hits != tt.expectedHits {
+				t.Errorf("expected %d requests, got %d", tt.expectedHits, hits)
+			}
+			if len(keys) == 2 && keys[0] != keys[1] {
+				t.Error("expected retries to reuse the idempotency key")
+			}
+		})
+	}
+}
+
+func TestNoteRepository_MaxNoteLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected int
+	}{
+		{"v2 configuration", `{"configuration": {"statuses": {"max_characters": 1000}}}`, http.StatusOK, 1000},
+		{"pleroma field", `{"max_toot_chars": 5000}`, http.StatusOK, 5000},
+		{"unavailable", `{}`, http.StatusInternalServerError, defaultMaxCharacters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			created, err := NewNoteRepository(Config{Host: server.URL, AccessToken: "test-token"})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+			repo := created.(*noteRepository)
+			if got := repo.MaxNoteLength(context.Background()); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected time.Duration
+	}{
+		{"seconds", map[string]string{"Retry-After": "5"}, 5 * time.Second},
+		{"rate limit reset", map[string]string{"X-RateLimit-Reset": "2024-01-01T00:00:30Z"}, 30 * time.Second},
+		{"reset in the past", map[string]string{"X-RateLimit-Reset": "2023-12-31T23:59:00Z"}, 0},
+		{"no headers", nil, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			if got := retryAfter(h, now); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
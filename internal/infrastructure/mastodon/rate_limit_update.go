@@ -0,0 +1,29 @@
+package mastodon
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetRateLimit changes the local rate limit at runtime. Permits already
+// spent stay spent.
+func (r *noteRepository) SetRateLimit(maxPermits int, refillInterval time.Duration) error {
+	if maxPermits <= 0 || refillInterval <= 0 {
+		return fmt.Errorf("%w: %d permits every %v", ErrInvalidRateLimit, maxPermits, refillInterval)
+	}
+	r.rateLimiter.setLimit(time.Now(), maxPermits, refillInterval)
+	return nil
+}
+
+func (rl *rateLimiter) setLimit(now time.Time, maxPermits int, refillRate time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if added := int(now.Sub(rl.lastRefill) / rl.refillRate); added > 0 {
+		rl.permits += added
+	}
+	rl.maxPermits = maxPermits
+	rl.refillRate = refillRate
+	rl.permits = min(rl.permits, maxPermits)
+	rl.lastRefill = now
+}
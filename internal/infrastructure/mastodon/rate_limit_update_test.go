@@ -0,0 +1,40 @@
+package mastodon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_SetRateLimit(t *testing.T) {
+	tests := []struct {
+		name            string
+		spent           int
+		maxPermits      int
+		refillInterval  time.Duration
+		expectErr       error
+		expectedPermits int
+		expectedMax     int
+	}{
+		{"raise limit keeps spent permits", 2, 10, time.Second, nil, 1, 10},
+		{"lower limit clamps permits", 0, 2, time.Second, nil, 2, 2},
+		{"invalid permits", 0, 0, time.Second, ErrInvalidRateLimit, 3, 3},
+		{"invalid interval", 0, 5, 0, ErrInvalidRateLimit, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(3, time.Hour)
+			limiter.permits -= tt.spent
+			repo := &noteRepository{rateLimiter: limiter}
+
+			err := repo.SetRateLimit(tt.maxPermits, tt.refillInterval)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if limiter.permits != tt.expectedPermits || limiter.maxPermits != tt.expectedMax {
+				t.Errorf("expected %d/%d permits, got %d/%d", tt.expectedPermits, tt.expectedMax, limiter.permits, limiter.maxPermits)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package mastodon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type rateLimiter struct {
+	mu         sync.Mutex
+	permits    int
+	maxPermits int
+	refillRate time.Duration
+	lastRefill time.Time
+	// blockedUntil is set from the server's rate limit headers after a 429.
+	blockedUntil time.Time
+}
+
+func newRateLimiter(maxPermits int, refillRate time.Duration) *rateLimiter {
+	return &rateLimiter{
+		permits:    maxPermits,
+		maxPermits: maxPermits,
+		refillRate: refillRate,
+		lastRefill: time.Now(),
+	}
+}
+
+func (rl *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := rl.reserve(time.Now())
+		if wait == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (rl *rateLimiter) reserve(now time.Time) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Before(rl.blockedUntil) {
+		return rl.blockedUntil.Sub(now)
+	}
+	if added := int(now.Sub(rl.lastRefill) / rl.refillRate); added > 0 {
+		rl.permits = min(rl.permits+added, rl.maxPermits)
+		rl.lastRefill = rl.lastRefill.Add(time.Duration(added) * rl.refillRate)
+	}
+	if rl.permits > 0 {
+		rl.permits--
+		return 0
+	}
+	return rl.refillRate - now.Sub(rl.lastRefill)
+}
+
+func (rl *rateLimiter) blockUntil(t time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if t.After(rl.blockedUntil) {
+		rl.blockedUntil = t
+	}
+}
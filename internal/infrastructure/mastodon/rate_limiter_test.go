@@ -0,0 +1,56 @@
+package mastodon
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	start := time.Now()
+	rl := newRateLimiter(2, 10*time.Second)
+	rl.lastRefill = start
+
+	steps := []struct {
+		name     string
+		at       time.Duration
+		expected time.Duration
+	}{
+		{"first permit", 0, 0},
+		{"second permit", time.Second, 0},
+		{"exhausted", 2 * time.Second, 8 * time.Second},
+		{"refilled", 10 * time.Second, 0},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if got := rl.reserve(start.Add(step.at)); got != step.expected {
+				t.Errorf("expected wait %v, got %v", step.expected, got)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_BlockUntil(t *testing.T) {
+	now := time.Now()
+	rl := newRateLimiter(5, time.Second)
+	rl.blockUntil(now.Add(30 * time.Second))
+	rl.blockUntil(now.Add(10 * time.Second))
+
+	if got := rl.reserve(now); got != 30*time.Second {
+		t.Errorf("expected the later block to win, got %v", got)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	rl := newRateLimiter(1, time.Hour)
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(ctx); err == nil {
+		t.Error("expected context error while waiting for a permit")
+	}
+}
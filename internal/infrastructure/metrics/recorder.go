@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const namespace = "misskeyrssbot_"
+
+type metricKind string
+
+const (
+	kindCounter metricKind = "counter"
+	kindGauge   metricKind = "gauge"
+)
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+type family struct {
+	name       string
+	help       string
+	kind       metricKind
+	labelNames []string
+	samples    map[string]*sample
+	fn         func() float64
+}
+
+// Recorder keeps the bot's metrics in memory and writes them in the
+// Prometheus text exposition format.
+type Recorder struct {
+	mu       sync.Mutex
+	families map[string]*family
+	order    []string
+	now      func() time.Time
+}
+
+func NewRecorder() *Recorder {
+	r := &Recorder{
+		families: make(map[string]*family),
+		now:      time.Now,
+	}
+	r.register("feed_fetches_total", "Feed fetches attempted.", kindCounter, "feed")
+	r.register("feed_fetch_errors_total", "Feed fetches that failed.", kindCounter, "feed")
+	r.register("feed_not_modified_total", "Feed fetches answered with 304 Not Modified.", kindCounter, "feed")
+	r.register("feed_last_success_timestamp_seconds", "Unix time of the last successful fetch of each feed.", kindGauge, "feed")
+	r.register("items_discovered_total", "New feed items found.", kindCounter, "feed")
+	r.register("notes_posted_total", "Notes posted.", kindCounter, "feed", "destination")
+	r.register("note_post_errors_total", "Notes that failed to post.", kindCounter, "feed", "destination")
+	r.register("misskey_api_errors_total", "Misskey API requests that failed or returned an error status.", kindCounter, "endpoint", "status")
+	return r
+}
+
+func (r *Recorder) register(name, help string, kind metricKind, labelNames ...string) {
+	r.families[name] = &family{
+		name:       namespace + name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		samples:    make(map[string]*sample),
+	}
+	r.order = append(r.order, name)
+}
+
+// RegisterGaugeFunc and RegisterCounterFunc add metrics that are read from
+// fn on every scrape.
+func (r *Recorder) RegisterGaugeFunc(name, help string, fn func() float64) {
+	r.registerFunc(name, help, kindGauge, fn)
+}
+
+func (r *Recorder) RegisterCounterFunc(name, help string, fn func() float64) {
+	r.registerFunc(name, help, kindCounter, fn)
+}
+
+func (r *Recorder) registerFunc(name, help string, kind metricKind, fn func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.register(name, help, kind)
+	r.families[name].fn = fn
+}
+
+func (r *Recorder) add(name string, delta float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sampleLocked(name, labels).value += delta
+}
+
+func (r *Recorder) set(name string, value float64, labels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sampleLocked(name, labels).value = value
+}
+
+func (r *Recorder) sampleLocked(name string, labels []string) *sample {
+	f := r.families[name]
+	key := strings.Join(labels, "\x00")
+	s, ok := f.samples[key]
+	if !ok {
+		s = &sample{labels: labels}
+		f.samples[key] = s
+	}
+	return s
+}
+
+func (r *Recorder) FeedFetched(feedURL string, err error) {
+	r.add("feed_fetches_total", 1, feedURL)
+	switch {
+	case errors.Is(err, repository.ErrFeedNotModified):
+		r.add("feed_not_modified_total", 1, feedURL)
+	case err != nil:
+		r.add("feed_fetch_errors_total", 1, feedURL)
+		return
+	}
+	r.set("feed_last_success_timestamp_seconds", float64(r.now().Unix()), feedURL)
+}
+
+func (r *Recorder) ItemsDiscovered(feedURL string, count int) {
+	r.add("items_discovered_total", float64(count), feedURL)
+}
+
+func (r *Recorder) NotePosted(feedURL, destination string, err error) {
+	if err != nil {
+		r.add("note_post_errors_total", 1, feedURL, destination)
+		return
+	}
+	r.add("notes_posted_total", 1, feedURL, destination)
+}
+
+// APITransport counts failed Misskey API calls. It has the shape of a
+// misskey.Middleware.
+func (r *Recorder) APITransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		endpoint := strings.TrimPrefix(req.URL.Path, "/api/")
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			r.add("misskey_api_errors_total", 1, endpoint, "error")
+			return nil, err
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			r.add("misskey_api_errors_total", 1, endpoint, strconv.Itoa(resp.StatusCode))
+		}
+		return resp, nil
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var b strings.Builder
+	for _, name := range r.order {
+		r.families[name].write(&b)
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+func (f *family) write(b *strings.Builder) {
+	if f.fn == nil && len(f.samples) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+	if f.fn != nil {
+		fmt.Fprintf(b, "%s %s\n", f.name, formatValue(f.fn()))
+		return
+	}
+
+	keys := make([]string, 0, len(f.samples))
+	for key := range f.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := f.samples[key]
+		b.WriteString(f.name)
+		if len(s.labels) > 0 {
+			b.WriteString("{")
+			for i, value := range s.labels {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(b, "%s=\"%s\"", f.labelNames[i], escapeLabelValue(value))
+			}
+			b.WriteString("}")
+		}
+		fmt.Fprintf(b, " %s\n", formatValue(s.value))
+	}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+func TestRecorder_WriteTo(t *testing.T) {
+	r := NewRecorder()
+	r.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	r.FeedFetched("https://example.com/feed", nil)
+	r.FeedFetched("https://example.com/feed", fmt.Errorf("wrapped: %w", repository.ErrFeedNotModified))
+	r.FeedFetched("https://example.com/broken", errors.New("timeout"))
+	r.ItemsDiscovered("https://example.com/feed", 3)
+	r.NotePosted("https://example.com/feed", "misskey", nil)
+	r.NotePosted("https://example.com/feed", "mastodon", errors.New("boom"))
+	r.RegisterGaugeFunc("rate_limiter_permits", "Available permits.", func() float64 { return 2 })
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"fetches", `misskeyrssbot_feed_fetches_total{feed="https://example.com/feed"} 2`},
+		{"fetch errors", `misskeyrssbot_feed_fetch_errors_total{feed="https://example.com/broken"} 1`},
+		{"not modified", `misskeyrssbot_feed_not_modified_total{feed="https://example.com/feed"} 1`},
+		{"last success", `misskeyrssbot_feed_last_success_timestamp_seconds{feed="https://example.com/feed"} 1.7e+09`},
+		{"items", `misskeyrssbot_items_discovered_total{feed="https://example.com/feed"} 3`},
+		{"posted", `misskeyrssbot_notes_posted_total{feed="https://example.com/feed",destination="misskey"} 1`},
+		{"post errors", `misskeyrssbot_note_post_errors_total{feed="https://example.com/feed",destination="mastodon"} 1`},
+		{"type line", "# TYPE misskeyrssbot_items_discovered_total counter"},
+		{"gauge func", "misskeyrssbot_rate_limiter_permits 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(out, tt.expected+"\n") {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, out)
+			}
+		})
+	}
+
+	if strings.Contains(out, `feed_last_success_timestamp_seconds{feed="https://example.com/broken"}`) {
+		t.Error("failed fetch should not update last success timestamp")
+	}
+	if strings.Contains(out, "misskey_api_errors_total") {
+		t.Error("metrics without samples should be omitted")
+	}
+}
+
+func TestRecorder_APITransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/notes/create" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	r := NewRecorder()
+	client := &http.Client{Transport: r.APITransport(http.DefaultTransport)}
+	for _, path := range []string{"/api/notes/create", "/api/i", "/api/notes/create"} {
+		resp, err := client.Post(server.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	var b strings.Builder
+	r.WriteTo(&b)
+	expected := `misskeyrssbot_misskey_api_errors_total{endpoint="notes/create",status="429"} 2` + "\n"
+	if !strings.Contains(b.String(), expected) {
+		t.Errorf("expected output to contain %q, got:\n%s", expected, b.String())
+	}
+	if strings.Contains(b.String(), `endpoint="i"`) {
+		t.Error("successful requests should not be counted")
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "https://example.com", "https://example.com"},
+		{"quote", `a"b`, `a\"b`},
+		{"backslash", `a\b`, `a\\b`},
+		{"newline", "a\nb", `a\nb`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLabelValue(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
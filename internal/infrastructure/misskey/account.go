@@ -0,0 +1,27 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type accountStats struct {
+	FollowersCount int `json:"followersCount"`
+	FollowingCount int `json:"followingCount"`
+	NotesCount     int `json:"notesCount"`
+}
+
+func (r *noteRepository) AccountStats(ctx context.Context) (followers, following, notes int, err error) {
+	if stats, ok := r.cache.accountStats.get(time.Now()); ok {
+		return stats.FollowersCount, stats.FollowingCount, stats.NotesCount, nil
+	}
+
+	var stats accountStats
+	if err := r.call(ctx, "i", nil, &stats); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to fetch account stats: %w", err)
+	}
+
+	r.cache.accountStats.set(stats, time.Now())
+	return stats.FollowersCount, stats.FollowingCount, stats.NotesCount, nil
+}
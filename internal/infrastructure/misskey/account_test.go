@@ -0,0 +1,72 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_AccountStats(t *testing.T) {
+	requests := 0
+	var receivedPayload map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/i" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"user1","followersCount":12,"followingCount":3,"notesCount":42}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+		cache:     responseCache{accountStats: ttlCache[accountStats]{ttl: time.Minute}},
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		followers, following, notes, err := repo.AccountStats(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if followers != 12 || following != 3 || notes != 42 {
+			t.Errorf("unexpected stats: followers=%d following=%d notes=%d", followers, following, notes)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request due to caching, got %d", requests)
+	}
+	if receivedPayload["i"] != "test-token" {
+		t.Errorf("expected auth token 'test-token', got '%v'", receivedPayload["i"])
+	}
+}
+
+func TestNoteRepository_AccountStats_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"AUTHENTICATION_FAILED","message":"Authentication failed."}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "invalid-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+		cache:     responseCache{accountStats: ttlCache[accountStats]{ttl: time.Minute}},
+	}
+
+	if _, _, _, err := repo.AccountStats(context.Background()); err == nil {
+		t.Error("expected error for unauthorized response, got nil")
+	}
+}
@@ -0,0 +1,149 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+type postingAccount struct {
+	authToken   string
+	rateLimiter *rateLimiter
+	hourlyCap   hourlyCap
+	dailyCap    dailyCap
+
+	serverRateLimit serverRateLimit
+	rateLimitTuning rateLimitTuning
+	suspended       atomic.Bool
+
+	mu     sync.Mutex
+	userID string
+}
+
+func (a *postingAccount) getUserID() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.userID
+}
+
+func (a *postingAccount) setUserID(userID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.userID = userID
+}
+
+func newPostingAccount(token string, limiter *rateLimiter, maxPostsPerHour, maxNotesPerDay int) *postingAccount {
+	return &postingAccount{
+		authToken:   token,
+		rateLimiter: limiter,
+		hourlyCap:   hourlyCap{limit: maxPostsPerHour},
+		dailyCap:    dailyCap{limit: maxNotesPerDay},
+	}
+}
+
+func newPostingAccounts(primaryToken string, extraTokens []string, newAccount func(token string) *postingAccount) []*postingAccount {
+	seen := make(map[string]bool)
+	var accounts []*postingAccount
+
+	for _, token := range append([]string{primaryToken}, extraTokens...) {
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		accounts = append(accounts, newAccount(token))
+	}
+
+	return accounts
+}
+
+func (r *noteRepository) postingAccounts() []*postingAccount {
+	if len(r.accounts) > 0 {
+		return r.accounts
+	}
+	r.fallbackOnce.Do(func() {
+		r.fallback = newPostingAccount(r.authToken, r.rateLimiter, r.maxPostsPerHour, r.maxNotesPerDay)
+	})
+	return []*postingAccount{r.fallback}
+}
+
+func (r *noteRepository) allSuspended() bool {
+	for _, account := range r.postingAccounts() {
+		if !account.suspended.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// accountByToken returns the posting account a request was made with,
+// falling back to the primary account for tokens it does not own.
+func (r *noteRepository) accountByToken(token string) *postingAccount {
+	accounts := r.postingAccounts()
+	for _, account := range accounts {
+		if account.authToken == token {
+			return account
+		}
+	}
+	return accounts[0]
+}
+
+// accountByUserID returns the posting account that authored a note, so calls
+// on an existing note are made with the token that owns it.
+func (r *noteRepository) accountByUserID(ctx context.Context, userID string) *postingAccount {
+	accounts := r.postingAccounts()
+	if userID == "" || len(accounts) == 1 {
+		return accounts[0]
+	}
+	r.ensureSelfIDs(ctx)
+	for _, account := range accounts {
+		if account.getUserID() == userID {
+			return account
+		}
+	}
+	return accounts[0]
+}
+
+// peekAccount returns the account the next post would try first, without
+// advancing the rotation.
+func (r *noteRepository) peekAccount() *postingAccount {
+	accounts := r.postingAccounts()
+	start := int(r.nextAccount.Load() % uint64(len(accounts)))
+	for i := range accounts {
+		if account := accounts[(start+i)%len(accounts)]; !account.suspended.Load() {
+			return account
+		}
+	}
+	return accounts[start]
+}
+
+func (r *noteRepository) selectAccount() (int, *postingAccount) {
+	accounts := r.postingAccounts()
+	index := int((r.nextAccount.Add(1) - 1) % uint64(len(accounts)))
+	return index, accounts[index]
+}
+
+func (r *noteRepository) tokenAt(index int) string {
+	accounts := r.postingAccounts()
+	if index < 0 || index >= len(accounts) {
+		return r.authToken
+	}
+	return accounts[index].authToken
+}
+
+func (r *noteRepository) Ping(ctx context.Context) error {
+	accounts := r.postingAccounts()
+	errs := newMultiError("failed to verify auth tokens", len(accounts))
+	for i, account := range accounts {
+		var me struct {
+			ID string `json:"id"`
+		}
+		if err := r.call(ctx, "i", map[string]interface{}{"i": account.authToken}, &me); err != nil {
+			errs.Add(fmt.Sprintf("token #%d", i), err)
+			continue
+		}
+		account.setUserID(me.ID)
+	}
+
+	return errs.ErrOrNil()
+}
@@ -0,0 +1,137 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNewPostingAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  string
+		extra    []string
+		expected []string
+	}{
+		{"primary only", "a", nil, []string{"a"}},
+		{"primary and extras", "a", []string{"b", "c"}, []string{"a", "b", "c"}},
+		{"duplicates removed", "a", []string{"a", "b", "b"}, []string{"a", "b"}},
+		{"empty primary", "", []string{"b"}, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := newPostingAccounts(tt.primary, tt.extra, func(token string) *postingAccount {
+				return newPostingAccount(token, newRateLimiter(1, time.Second), 0, 0)
+			})
+			if len(accounts) != len(tt.expected) {
+				t.Fatalf("expected %d accounts, got %d", len(tt.expected), len(accounts))
+			}
+			for i, account := range accounts {
+				if account.authToken != tt.expected[i] {
+					t.Errorf("account[%d]: expected token '%s', got '%s'", i, tt.expected[i], account.authToken)
+				}
+				if i > 0 && account.rateLimiter == accounts[i-1].rateLimiter {
+					t.Errorf("account[%d]: expected its own rate limiter", i)
+				}
+			}
+		})
+	}
+}
+
+func newMultiTokenServer(t *testing.T, usedTokens *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		token, _ := payload["i"].(string)
+
+		switch r.URL.Path {
+		case "/api/i":
+			if token == "invalid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"code":"AUTHENTICATION_FAILED","message":"Authentication failed."}}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]string{"id": "user-" + token})
+		case "/api/notes/create":
+			*usedTokens = append(*usedTokens, token)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestNoteRepository_PostWithOptions_RoundRobinTokens(t *testing.T) {
+	var usedTokens []string
+	server := newMultiTokenServer(t, &usedTokens)
+	defer server.Close()
+
+	noteRepo, err := NewNoteRepository(Config{
+		Host:           server.URL,
+		AuthToken:      "token-a",
+		AuthTokens:     []string{"token-b"},
+		MaxPermits:     1,
+		RefillInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := noteRepo.(*noteRepository)
+
+	ctx := context.Background()
+	if err := repo.Ping(ctx); err != nil {
+		t.Fatalf("unexpected ping error: %v", err)
+	}
+
+	var results []*PostResult
+	for i := 0; i < 2; i++ {
+		result, err := repo.PostWithOptions(ctx, entity.NewNote("Test", entity.VisibilityHome), PostOptions{})
+		if err != nil {
+			t.Fatalf("post %d: unexpected error: %v", i+1, err)
+		}
+		results = append(results, result)
+	}
+
+	expectedTokens := []string{"token-a", "token-b"}
+	for i, token := range usedTokens {
+		if token != expectedTokens[i] {
+			t.Errorf("post %d: expected token '%s', got '%s'", i+1, expectedTokens[i], token)
+		}
+		if results[i].TokenIndex != i {
+			t.Errorf("post %d: expected token index %d, got %d", i+1, i, results[i].TokenIndex)
+		}
+		if results[i].AccountID != "user-"+expectedTokens[i] {
+			t.Errorf("post %d: expected account 'user-%s', got '%s'", i+1, expectedTokens[i], results[i].AccountID)
+		}
+	}
+}
+
+func TestNoteRepository_Ping_InvalidToken(t *testing.T) {
+	var usedTokens []string
+	server := newMultiTokenServer(t, &usedTokens)
+	defer server.Close()
+
+	noteRepo, err := NewNoteRepository(Config{
+		Host:       server.URL,
+		AuthToken:  "token-a",
+		AuthTokens: []string{"invalid"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := noteRepo.(*noteRepository)
+
+	if err := repo.Ping(context.Background()); err == nil {
+		t.Error("expected ping error for invalid token, got nil")
+	}
+}
@@ -0,0 +1,61 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type announcement struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+func defaultAnnouncementKeywords() []string {
+	return []string{"maintenance", "read-only", "readonly", "メンテナンス", "読み取り専用"}
+}
+
+func (r *noteRepository) maintenanceKeywords() []string {
+	if len(r.announcementKeywords) > 0 {
+		return r.announcementKeywords
+	}
+	return defaultAnnouncementKeywords()
+}
+
+func (r *noteRepository) fetchAnnouncements(ctx context.Context) ([]announcement, error) {
+	if cached, ok := r.cache.announcements.get(time.Now()); ok {
+		return cached, nil
+	}
+
+	var announcements []announcement
+	if err := r.call(ctx, "announcements", map[string]interface{}{"isActive": true, "limit": 10}, &announcements); err != nil {
+		return nil, fmt.Errorf("failed to fetch announcements: %w", err)
+	}
+
+	r.cache.announcements.set(announcements, time.Now())
+	return announcements, nil
+}
+
+func (r *noteRepository) checkMaintenance(ctx context.Context) error {
+	if !r.checkAnnouncements {
+		return nil
+	}
+
+	announcements, err := r.fetchAnnouncements(ctx)
+	if err != nil {
+		r.logf("Warning: Could not check instance announcements, posting anyway: %v", err)
+		return nil
+	}
+
+	for _, a := range announcements {
+		content := strings.ToLower(a.Title + "\n" + a.Text)
+		for _, keyword := range r.maintenanceKeywords() {
+			if strings.Contains(content, strings.ToLower(keyword)) {
+				return fmt.Errorf("%w: %q", ErrInstanceMaintenance, a.Title)
+			}
+		}
+	}
+	return nil
+}
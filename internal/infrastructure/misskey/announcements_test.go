@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_CheckMaintenance(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		status        int
+		keywords      []string
+		expectErr     error
+		expectedPosts int
+	}{
+		{"no announcements", `[]`, http.StatusOK, nil, nil, 1},
+		{"unrelated announcement", `[{"id":"a1","title":"New emoji","text":"Enjoy"}]`, http.StatusOK, nil, nil, 1},
+		{"maintenance in title", `[{"id":"a1","title":"Scheduled Maintenance","text":"Back soon"}]`, http.StatusOK, nil, ErrInstanceMaintenance, 0},
+		{"japanese keyword in text", `[{"id":"a1","title":"お知らせ","text":"本日メンテナンスを行います"}]`, http.StatusOK, nil, ErrInstanceMaintenance, 0},
+		{"custom keywords", `[{"id":"a1","title":"Server migration","text":""}]`, http.StatusOK, []string{"migration"}, ErrInstanceMaintenance, 0},
+		{"fetch failure allows posting", `{"error":{"code":"INTERNAL_ERROR"}}`, http.StatusInternalServerError, nil, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/announcements") {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.response))
+					return
+				}
+				posts++
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:                 server.URL,
+				authToken:            "test-token",
+				client:               &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:          newRateLimiter(10, 10*time.Second),
+				checkAnnouncements:   true,
+				announcementKeywords: tt.keywords,
+			}
+
+			err := repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if posts != tt.expectedPosts {
+				t.Errorf("expected %d posts, got %d", tt.expectedPosts, posts)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_CheckMaintenance_Cached(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write([]byte(`[{"id":"a1","title":"Read-only mode","text":""}]`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:               server.URL,
+		authToken:          "test-token",
+		client:             &http.Client{Timeout: 30 * time.Second},
+		checkAnnouncements: true,
+		cache:              responseCache{announcements: ttlCache[[]announcement]{ttl: time.Minute}},
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := repo.checkMaintenance(context.Background()); !errors.Is(err, ErrInstanceMaintenance) {
+			t.Fatalf("expected ErrInstanceMaintenance, got %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected 1 announcements fetch, got %d", fetches)
+	}
+}
@@ -0,0 +1,200 @@
+package misskey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	maxResponseBytes   = 1 << 20
+	defaultAPIBasePath = "/api"
+	defaultAppName     = "misskeyRSSbot"
+)
+
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	ID         string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("misskey API returned non-OK status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("misskey API returned non-OK status: %d (%s: %s)", e.StatusCode, e.Code, e.Message)
+}
+
+func parseAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var errBody struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			ID      string `json:"id"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		return apiErr
+	}
+
+	apiErr.Code = errBody.Error.Code
+	apiErr.Message = errBody.Error.Message
+	apiErr.ID = errBody.Error.ID
+	return apiErr
+}
+
+func (r *noteRepository) baseURL() string {
+	url := r.host
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "https://" + url
+	}
+	return url
+}
+
+func (r *noteRepository) endpointURL(endpoint string) string {
+	basePath := r.apiBasePath
+	if basePath == "" {
+		basePath = defaultAPIBasePath
+	}
+	return r.baseURL() + strings.TrimSuffix(basePath, "/") + "/" + endpoint
+}
+
+func (r *noteRepository) userAgent() string {
+	if r.appName != "" {
+		return r.appName
+	}
+	return defaultAppName
+}
+
+func normalizeAPIBasePath(path string) (string, error) {
+	if path == "" {
+		return defaultAPIBasePath, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("API base path must start with '/': %s", path)
+	}
+	// "/" puts the API at the root, so it must not collapse to "" and fall
+	// back to the default.
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed, nil
+	}
+	return "/", nil
+}
+
+func (r *noteRepository) call(ctx context.Context, endpoint string, params map[string]interface{}, out interface{}) error {
+	body := map[string]interface{}{"i": r.authToken}
+	for k, v := range params {
+		body[k] = v
+	}
+
+	payload, err := r.bodyEncoder().Encode(body)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	compress := r.shouldCompress(payload)
+	statusCode, header, respBody, err := r.send(ctx, endpoint, payload, compress)
+	if err != nil {
+		return err
+	}
+	if compress && isCompressionRejected(statusCode, respBody) {
+		r.compressionUnsupported.Store(true)
+		r.logf("Misskey API rejected compressed request body (status %d), falling back to uncompressed requests", statusCode)
+		statusCode, header, respBody, err = r.send(ctx, endpoint, payload, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	token, _ := body["i"].(string)
+	account := r.accountByToken(token)
+	r.observeRateLimit(account, endpoint, header)
+
+	if statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+		apiErr := parseAPIError(statusCode, respBody)
+		if isAccountSuspendedCode(apiErr.Code) {
+			r.markSuspended(account, apiErr)
+			return fmt.Errorf("%w: %w", ErrAccountSuspended, apiErr)
+		}
+		if r.isDailyCapCode(apiErr.Code) {
+			wait, known := rateLimitResetAfter(header, respBody)
+			return r.markDailyCapReached(account, apiErr, wait, known)
+		}
+		if statusCode == http.StatusTooManyRequests {
+			wait, ok := rateLimitResetAfter(header, respBody)
+			apiErr.RetryAfter = r.penalizeRateLimit(account, endpoint, wait, ok)
+		}
+		return apiErr
+	}
+	account.clearRateLimitPenalty()
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode Misskey API response: %w", err)
+	}
+
+	return nil
+}
+
+func (r *noteRepository) send(ctx context.Context, endpoint string, payload []byte, compress bool) (int, http.Header, []byte, error) {
+	body := payload
+	if compress {
+		compressed, err := gzipBytes(payload)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("failed to compress request: %w", err)
+		}
+		body = compressed
+	}
+
+	endpointURL := r.endpointURL(endpoint)
+	if err := r.checkHostAllowed(endpointURL); err != nil {
+		return 0, nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", r.bodyEncoder().ContentType())
+	req.Header.Set("User-Agent", r.userAgent())
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	defer r.ops.sending(ctx)()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to send request to Misskey API: %w", classifyNetError(err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to read Misskey API response: %w", err)
+	}
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+func (r *noteRepository) markSuspended(account *postingAccount, apiErr *APIError) {
+	if !account.suspended.CompareAndSwap(false, true) {
+		return
+	}
+	if r.allSuspended() {
+		r.logf("CRITICAL: Misskey account is suspended (%s), all further posts are blocked until restart. Operator intervention required.", apiErr.Code)
+		return
+	}
+	r.logf("CRITICAL: Misskey posting account [%s] is suspended (%s), posting with the remaining accounts until restart. Operator intervention required.", account.getUserID(), apiErr.Code)
+}
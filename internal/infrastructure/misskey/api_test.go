@@ -0,0 +1,148 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAPIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		expectedCode string
+		expectedMsg  string
+	}{
+		{
+			name:         "misskey error body",
+			statusCode:   http.StatusBadRequest,
+			body:         `{"error":{"code":"NO_SUCH_NOTE","message":"No such note.","id":"abc"}}`,
+			expectedCode: "NO_SUCH_NOTE",
+			expectedMsg:  "No such note.",
+		},
+		{
+			name:       "string error body",
+			statusCode: http.StatusInternalServerError,
+			body:       `{"error": "Internal server error"}`,
+		},
+		{
+			name:       "non-json body",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := parseAPIError(tt.statusCode, []byte(tt.body))
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("expected status %d, got %d", tt.statusCode, apiErr.StatusCode)
+			}
+			if apiErr.Code != tt.expectedCode {
+				t.Errorf("expected code '%s', got '%s'", tt.expectedCode, apiErr.Code)
+			}
+			if apiErr.Message != tt.expectedMsg {
+				t.Errorf("expected message '%s', got '%s'", tt.expectedMsg, apiErr.Message)
+			}
+			if apiErr.Error() == "" {
+				t.Error("expected non-empty error message")
+			}
+		})
+	}
+}
+
+func TestNoteRepository_EndpointURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{"hostname only", "example.tld", "https://example.tld/api/notes/create"},
+		{"https scheme", "https://example.tld", "https://example.tld/api/notes/create"},
+		{"http scheme", "http://localhost:3000", "http://localhost:3000/api/notes/create"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{host: tt.host}
+			if got := repo.endpointURL("notes/create"); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_EndpointURL_BasePath(t *testing.T) {
+	tests := []struct {
+		name      string
+		basePath  string
+		expected  string
+		expectErr bool
+	}{
+		{"default", "", "https://example.tld/api/notes/create", false},
+		{"custom prefix", "/misskey/api", "https://example.tld/misskey/api/notes/create", false},
+		{"trailing slash trimmed", "/misskey/api/", "https://example.tld/misskey/api/notes/create", false},
+		{"root", "/", "https://example.tld/notes/create", false},
+		{"root with extra slashes", "//", "https://example.tld/notes/create", false},
+		{"missing leading slash", "misskey/api", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basePath, err := normalizeAPIBasePath(tt.basePath)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			repo := &noteRepository{host: "example.tld", apiBasePath: basePath}
+			if got := repo.endpointURL("notes/create"); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Call_UserAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		appName  string
+		expected string
+	}{
+		{"default", "", defaultAppName},
+		{"custom app name", "NewsBot/2.0", "NewsBot/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.Header.Get("User-Agent")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+				appName:   tt.appName,
+			}
+
+			if err := repo.call(context.Background(), "ping", nil, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if userAgent != tt.expected {
+				t.Errorf("expected User-Agent %q, got %q", tt.expected, userAgent)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package misskey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type auditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Action     string    `json:"action"`
+	NoteID     string    `json:"noteId"`
+	URL        string    `json:"url"`
+	Visibility string    `json:"visibility,omitempty"`
+	TextSHA256 string    `json:"textSha256,omitempty"`
+	Source     string    `json:"source,omitempty"`
+}
+
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &auditLog{file: file}, nil
+}
+
+func (a *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return a.file.Sync()
+}
+
+func (a *auditLog) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *noteRepository) audit(entry auditEntry) {
+	if r.auditLog == nil {
+		return
+	}
+	entry.Timestamp = time.Now().UTC()
+	entry.URL = r.noteURL(entry.NoteID)
+	if err := r.auditLog.write(entry); err != nil {
+		r.logf("Failed to record audit entry for note [%s]: %v", entry.NoteID, err)
+	}
+}
@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_AuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/notes/delete" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := openAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+		auditLog:    auditLog,
+	}
+	ctx := context.Background()
+
+	note := entity.NewNote("Secret body", entity.VisibilityHome)
+	if _, err := repo.PostWithOptions(ctx, note, PostOptions{Source: "tech-feed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.DeleteNote(ctx, "note123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Close(ctx); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "Secret body") {
+			t.Error("audit log must not contain the note text")
+		}
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+
+	created := entries[0]
+	if created.Action != "create" || created.NoteID != "note123" || created.Source != "tech-feed" || created.Visibility != "home" {
+		t.Errorf("unexpected create entry: %+v", created)
+	}
+	if created.TextSHA256 != hashText("Secret body") {
+		t.Errorf("expected text hash %s, got %s", hashText("Secret body"), created.TextSHA256)
+	}
+	if created.URL != server.URL+"/notes/note123" {
+		t.Errorf("unexpected note URL: %s", created.URL)
+	}
+	if entries[1].Action != "delete" || entries[1].NoteID != "note123" {
+		t.Errorf("unexpected delete entry: %+v", entries[1])
+	}
+}
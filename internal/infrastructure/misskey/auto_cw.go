@@ -0,0 +1,104 @@
+package misskey
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultAutoCWThreshold     = 500
+	defaultAutoCWMaxSummaryLen = 80
+)
+
+type AutoCWConfig struct {
+	Enabled       bool
+	Threshold     int
+	MaxSummaryLen int
+}
+
+func (r *noteRepository) resolveCW(cw, text string) string {
+	if cw != "" || !r.autoCW.Enabled {
+		return cw
+	}
+
+	threshold := r.autoCW.Threshold
+	if threshold <= 0 {
+		threshold = defaultAutoCWThreshold
+	}
+	if utf8.RuneCountInString(text) <= threshold {
+		return ""
+	}
+
+	return summarizeForCW(text, r.autoCWMaxSummaryLen())
+}
+
+func (r *noteRepository) requiredCW(cw, text string) (string, error) {
+	if cw != "" || !r.requireCW {
+		return cw, nil
+	}
+	if !r.autoCW.Enabled {
+		return "", fmt.Errorf("%w: note has no CW and AutoCW is disabled", ErrCWRequired)
+	}
+	summary := summarizeForCW(text, r.autoCWMaxSummaryLen())
+	if strings.TrimSpace(summary) == "" {
+		return "", fmt.Errorf("%w: could not derive a CW from the note text", ErrCWRequired)
+	}
+	return summary, nil
+}
+
+func (r *noteRepository) autoCWMaxSummaryLen() int {
+	if r.autoCW.MaxSummaryLen <= 0 {
+		return defaultAutoCWMaxSummaryLen
+	}
+	return r.autoCW.MaxSummaryLen
+}
+
+func summarizeForCW(text string, maxLen int) string {
+	summary := strings.TrimSpace(firstSentence(strings.TrimSpace(text)))
+	if utf8.RuneCountInString(summary) <= maxLen {
+		return summary
+	}
+
+	ellipsisLen := utf8.RuneCountInString(truncationEllipsis)
+	if maxLen <= ellipsisLen {
+		return string([]rune(summary)[:maxLen])
+	}
+
+	runes := []rune(summary)
+	cut := runes[:maxLen-ellipsisLen]
+	if unicode.IsSpace(runes[len(cut)]) {
+		return string(cut) + truncationEllipsis
+	}
+	if i := lastSpaceIndex(cut); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(string(cut), unicode.IsSpace) + truncationEllipsis
+}
+
+func firstSentence(text string) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		switch r {
+		case '\n':
+			return string(runes[:i])
+		case '。', '！', '？', '!', '?':
+			return string(runes[:i+1])
+		case '.':
+			if i+1 == len(runes) || unicode.IsSpace(runes[i+1]) {
+				return string(runes[:i+1])
+			}
+		}
+	}
+	return text
+}
+
+func lastSpaceIndex(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
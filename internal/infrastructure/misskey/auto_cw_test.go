@@ -0,0 +1,142 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestSummarizeForCW(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxLen   int
+		expected string
+	}{
+		{"first line", "Release notes\nlots of details", 80, "Release notes"},
+		{"first sentence", "Version 2 is out. It has many changes.", 80, "Version 2 is out."},
+		{"dot inside url kept", "See example.com for more", 80, "See example.com for more"},
+		{"japanese sentence", "新機能を公開しました。詳細は以下。", 80, "新機能を公開しました。"},
+		{"cut on word boundary", "The quick brown fox jumps over the lazy dog", 20, "The quick brown fox…"},
+		{"no spaces hard cut", "あいうえおかきくけこ", 5, "あいうえ…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeForCW(tt.text, tt.maxLen); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_ResolveCW(t *testing.T) {
+	long := "Headline\n" + strings.Repeat("body ", 20)
+
+	tests := []struct {
+		name     string
+		autoCW   AutoCWConfig
+		cw       string
+		text     string
+		expected string
+	}{
+		{"disabled", AutoCWConfig{Threshold: 10}, "", long, ""},
+		{"explicit cw kept", AutoCWConfig{Enabled: true, Threshold: 10}, "mine", long, "mine"},
+		{"below threshold", AutoCWConfig{Enabled: true, Threshold: 1000}, "", long, ""},
+		{"generated", AutoCWConfig{Enabled: true, Threshold: 10}, "", long, "Headline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{autoCW: tt.autoCW}
+			if got := repo.resolveCW(tt.cw, tt.text); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_AutoCW(t *testing.T) {
+	var receivedPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+		autoCW:      AutoCWConfig{Enabled: true, Threshold: 10},
+	}
+
+	err := repo.Post(context.Background(), entity.NewNote("Long article title\nwith a long body", entity.VisibilityHome))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receivedPayload["cw"] != "Long article title" {
+		t.Errorf("expected cw 'Long article title', got %v", receivedPayload["cw"])
+	}
+}
+
+func TestNoteRepository_Post_RequireCW(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoCW     AutoCWConfig
+		note       *entity.Note
+		expectedCW interface{}
+		expectErr  error
+	}{
+		{"explicit cw kept", AutoCWConfig{}, &entity.Note{Text: "Short", CW: "Given", Visibility: entity.VisibilityHome}, "Given", nil},
+		{"short note gets cw", AutoCWConfig{Enabled: true}, entity.NewNote("Short note. More text", entity.VisibilityHome), "Short note.", nil},
+		{"auto cw disabled", AutoCWConfig{}, entity.NewNote("Short", entity.VisibilityHome), nil, ErrCWRequired},
+		{"nothing to derive", AutoCWConfig{Enabled: true}, entity.NewNote("   ", entity.VisibilityHome), nil, ErrCWRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &receivedPayload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+				autoCW:      tt.autoCW,
+				requireCW:   true,
+			}
+
+			err := repo.Post(context.Background(), tt.note)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr != nil {
+				if receivedPayload != nil {
+					t.Error("expected no request to be sent")
+				}
+				return
+			}
+			if receivedPayload["cw"] != tt.expectedCW {
+				t.Errorf("expected cw %v, got %v", tt.expectedCW, receivedPayload["cw"])
+			}
+		})
+	}
+}
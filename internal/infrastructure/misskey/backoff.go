@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const maxBackoffShift = 30
+
+// Backoff decides how long to wait before the given attempt, which starts at
+// 1. lastDelay is the delay returned for the previous attempt, or zero.
+type Backoff interface {
+	Next(attempt int, lastDelay time.Duration) time.Duration
+}
+
+type constantBackoff struct {
+	delay time.Duration
+}
+
+func NewConstantBackoff(delay time.Duration) Backoff {
+	return constantBackoff{delay: delay}
+}
+
+func (b constantBackoff) Next(int, time.Duration) time.Duration {
+	return b.delay
+}
+
+type linearBackoff struct {
+	step time.Duration
+	max  time.Duration
+}
+
+func NewLinearBackoff(step, limit time.Duration) Backoff {
+	return linearBackoff{step: step, max: limit}
+}
+
+func (b linearBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	return capBackoff(b.step*time.Duration(max(attempt, 1)), b.max)
+}
+
+type exponentialBackoff struct {
+	base   time.Duration
+	max    time.Duration
+	jitter BackoffJitter
+}
+
+func NewExponentialBackoff(base, limit time.Duration, jitter BackoffJitter) Backoff {
+	return exponentialBackoff{base: base, max: limit, jitter: jitter}
+}
+
+func (b exponentialBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	shift := min(max(attempt, 1)-1, maxBackoffShift)
+	if b.base > math.MaxInt64>>shift {
+		if b.max > 0 {
+			return b.jitter.apply(b.max)
+		}
+		return b.jitter.apply(math.MaxInt64)
+	}
+	return b.jitter.apply(capBackoff(b.base<<shift, b.max))
+}
+
+type decorrelatedJitterBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// NewDecorrelatedJitterBackoff returns a backoff whose delays are drawn from
+// [base, 3*lastDelay], never below base and never above limit.
+func NewDecorrelatedJitterBackoff(base, limit time.Duration) Backoff {
+	return decorrelatedJitterBackoff{base: base, max: limit}
+}
+
+func (b decorrelatedJitterBackoff) Next(_ int, lastDelay time.Duration) time.Duration {
+	upper := max(lastDelay, b.base) * 3
+	if b.max > 0 && upper > b.max {
+		upper = b.max
+	}
+	if upper <= b.base {
+		return upper
+	}
+	return b.base + rand.N(upper-b.base+1)
+}
+
+func capBackoff(d, limit time.Duration) time.Duration {
+	if limit > 0 && (d > limit || d < 0) {
+		return limit
+	}
+	return d
+}
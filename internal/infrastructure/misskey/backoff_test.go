@@ -0,0 +1,102 @@
+package misskey
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  Backoff
+		attempt  int
+		expected time.Duration
+	}{
+		{"constant", NewConstantBackoff(2 * time.Second), 5, 2 * time.Second},
+		{"linear", NewLinearBackoff(time.Second, 0), 3, 3 * time.Second},
+		{"linear capped", NewLinearBackoff(time.Second, 2*time.Second), 3, 2 * time.Second},
+		{"exponential", NewExponentialBackoff(time.Second, 0, BackoffJitterNone), 4, 8 * time.Second},
+		{"exponential capped", NewExponentialBackoff(time.Second, 5*time.Second, BackoffJitterNone), 4, 5 * time.Second},
+		{"exponential large attempt stays capped", NewExponentialBackoff(time.Second, time.Minute, BackoffJitterNone), 200, time.Minute},
+		{"exponential overflow without max", NewExponentialBackoff(time.Hour, 0, BackoffJitterNone), 200, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.backoff.Next(tt.attempt, 0); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitterBackoff_Bounds(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		limit     time.Duration
+		lastDelay time.Duration
+		minimum   time.Duration
+		maximum   time.Duration
+	}{
+		{"first attempt", 0, 0, base, 3 * base},
+		{"grows from last delay", 0, time.Second, base, 3 * time.Second},
+		{"capped by limit", 500 * time.Millisecond, time.Second, base, 500 * time.Millisecond},
+		{"last delay below base", 0, base / 2, base, 3 * base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff := NewDecorrelatedJitterBackoff(base, tt.limit)
+			for i := 0; i < 1000; i++ {
+				delay := backoff.Next(i+1, tt.lastDelay)
+				if delay < tt.minimum || delay > tt.maximum {
+					t.Fatalf("delay %v outside [%v, %v]", delay, tt.minimum, tt.maximum)
+				}
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitterBackoff_ChainStaysWithinLimit(t *testing.T) {
+	base, limit := 100*time.Millisecond, 2*time.Second
+	backoff := NewDecorrelatedJitterBackoff(base, limit)
+
+	var last time.Duration
+	for attempt := 1; attempt <= 1000; attempt++ {
+		delay := backoff.Next(attempt, last)
+		if delay < base || delay > limit || delay > 3*max(last, base) {
+			t.Fatalf("attempt %d: delay %v outside bounds (last %v)", attempt, delay, last)
+		}
+		last = delay
+	}
+}
+
+func TestNoteRepository_RetryDelay_ConfiguredBackoff(t *testing.T) {
+	repo := &noteRepository{retryBackoff: time.Second, backoff: NewConstantBackoff(250 * time.Millisecond)}
+	if got := repo.retryDelay(3, time.Second); got != 250*time.Millisecond {
+		t.Errorf("expected configured backoff to be used, got %v", got)
+	}
+}
+
+func TestNoteRepository_PenalizeRateLimit(t *testing.T) {
+	repo := &noteRepository{backoff: NewLinearBackoff(time.Second, 0)}
+	account := repo.postingAccounts()[0]
+
+	if got := repo.penalizeRateLimit(account, "notes/create", 0, false); got != time.Second {
+		t.Errorf("expected first penalty of 1s, got %v", got)
+	}
+	if got := repo.penalizeRateLimit(account, "notes/create", 0, false); got != 2*time.Second {
+		t.Errorf("expected second penalty of 2s, got %v", got)
+	}
+	if got := repo.penalizeRateLimit(account, "notes/create", 30*time.Second, true); got != 30*time.Second {
+		t.Errorf("expected server-reported wait to win, got %v", got)
+	}
+
+	account.clearRateLimitPenalty()
+	if got := repo.penalizeRateLimit(account, "notes/create", 0, false); got != time.Second {
+		t.Errorf("expected penalty to restart after success, got %v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const batchRefPrefix = "batch:"
+
+// BatchRef returns a placeholder for the ReplyID or RenoteID of a note in a
+// PostBatch call that refers to the earlier note at index in the same batch.
+func BatchRef(index int) string {
+	return batchRefPrefix + strconv.Itoa(index)
+}
+
+func parseBatchRef(id string) (int, bool, error) {
+	ref, ok := strings.CutPrefix(id, batchRefPrefix)
+	if !ok {
+		return 0, false, nil
+	}
+	index, err := strconv.Atoi(ref)
+	if err != nil {
+		return 0, true, fmt.Errorf("%w: %q", ErrInvalidBatchReference, id)
+	}
+	return index, true, nil
+}
+
+func validateBatchRefs(notes []*entity.Note) error {
+	for i, note := range notes {
+		for _, id := range []string{note.ReplyID, note.RenoteID} {
+			index, isRef, err := parseBatchRef(id)
+			if err != nil {
+				return err
+			}
+			if isRef && (index < 0 || index >= i) {
+				return fmt.Errorf("%w: note %d refers to %q, which is not an earlier note", ErrInvalidBatchReference, i, id)
+			}
+		}
+	}
+	return nil
+}
+
+func resolveBatchRef(id string, posted []string) (string, error) {
+	index, isRef, _ := parseBatchRef(id)
+	if !isRef {
+		return id, nil
+	}
+	if posted[index] == "" {
+		return "", fmt.Errorf("%w: note %d", ErrBatchReferenceFailed, index+1)
+	}
+	return posted[index], nil
+}
+
+func (r *noteRepository) PostBatch(ctx context.Context, notes []*entity.Note) ([]*PostResult, error) {
+	if err := validateBatchRefs(notes); err != nil {
+		return nil, err
+	}
+
+	// results lines up with notes; a note that was not posted leaves nil and
+	// its failure in the returned MultiError.
+	results := make([]*PostResult, len(notes))
+	posted := make([]string, len(notes))
+	errs := newMultiError("failed to post batch", len(notes))
+	for i, note := range notes {
+		item := fmt.Sprintf("note %d", i+1)
+		next := *note
+		var err error
+		if next.ReplyID, err = resolveBatchRef(note.ReplyID, posted); err == nil {
+			next.RenoteID, err = resolveBatchRef(note.RenoteID, posted)
+		}
+		if err != nil {
+			errs.Add(item, err)
+			continue
+		}
+
+		result, err := r.PostWithOptions(ctx, &next, PostOptions{})
+		if result != nil && result.NoteID != "" {
+			results[i] = result
+			posted[i] = result.NoteID
+		}
+		switch {
+		case err != nil:
+			errs.Add(item, err)
+		case result.NoteID == "":
+			errs.Add(item, fmt.Errorf("not posted: %s", result.Outcome))
+		}
+	}
+	return results, errs.ErrOrNil()
+}
@@ -0,0 +1,183 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestValidateBatchRefs(t *testing.T) {
+	note := func(replyID, renoteID string) *entity.Note {
+		return &entity.Note{Text: "x", ReplyID: replyID, RenoteID: renoteID}
+	}
+
+	tests := []struct {
+		name      string
+		notes     []*entity.Note
+		expectErr bool
+	}{
+		{"no references", []*entity.Note{note("", ""), note("external", "")}, false},
+		{"reply to earlier", []*entity.Note{note("", ""), note(BatchRef(0), ""), note(BatchRef(1), BatchRef(0))}, false},
+		{"self reference", []*entity.Note{note(BatchRef(0), "")}, true},
+		{"forward reference", []*entity.Note{note(BatchRef(1), ""), note("", "")}, true},
+		{"malformed reference", []*entity.Note{note("", ""), note("batch:first", "")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBatchRefs(tt.notes)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidBatchReference) {
+				t.Errorf("expected ErrInvalidBatchReference, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostBatch(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+		fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(payloads))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+	}
+
+	notes := []*entity.Note{
+		{Text: "root", Visibility: entity.VisibilityHome},
+		{Text: "reply to root", Visibility: entity.VisibilityHome, ReplyID: BatchRef(0)},
+		{Text: "reply to reply", Visibility: entity.VisibilityHome, ReplyID: BatchRef(1)},
+		{Text: "quote of root", Visibility: entity.VisibilityHome, RenoteID: BatchRef(0)},
+		{Text: "external reply", Visibility: entity.VisibilityHome, ReplyID: "external1"},
+	}
+
+	results, err := repo.PostBatch(context.Background(), notes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(notes) {
+		t.Fatalf("expected %d results, got %d", len(notes), len(results))
+	}
+
+	expected := []struct {
+		replyID  interface{}
+		renoteID interface{}
+	}{
+		{nil, nil},
+		{"note1", nil},
+		{"note2", nil},
+		{nil, "note1"},
+		{"external1", nil},
+	}
+	for i, want := range expected {
+		if payloads[i]["replyId"] != want.replyID {
+			t.Errorf("note %d: expected replyId %v, got %v", i, want.replyID, payloads[i]["replyId"])
+		}
+		if payloads[i]["renoteId"] != want.renoteID {
+			t.Errorf("note %d: expected renoteId %v, got %v", i, want.renoteID, payloads[i]["renoteId"])
+		}
+		if results[i].NoteID != fmt.Sprintf("note%d", i+1) {
+			t.Errorf("note %d: expected ID note%d, got %s", i, i+1, results[i].NoteID)
+		}
+	}
+}
+
+func TestNoteRepository_PostBatch_InvalidReferencePostsNothing(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.Write([]byte(`{"createdNote": {"id": "note1"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+	}
+
+	notes := []*entity.Note{
+		{Text: "root", Visibility: entity.VisibilityHome},
+		{Text: "forward", Visibility: entity.VisibilityHome, ReplyID: BatchRef(2)},
+		{Text: "last", Visibility: entity.VisibilityHome},
+	}
+	if _, err := repo.PostBatch(context.Background(), notes); !errors.Is(err, ErrInvalidBatchReference) {
+		t.Fatalf("expected ErrInvalidBatchReference, got %v", err)
+	}
+	if posts != 0 {
+		t.Errorf("expected no notes to be posted, got %d", posts)
+	}
+}
+
+func TestNoteRepository_PostBatch_CollectsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["text"] == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"CONTAINS_PROHIBITED_WORDS","message":"Cannot post because it contains prohibited words."}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"createdNote": {"id": "%s"}}`, payload["text"])
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+	}
+
+	notes := []*entity.Note{
+		{Text: "root", Visibility: entity.VisibilityHome},
+		{Text: "bad", Visibility: entity.VisibilityHome, ReplyID: BatchRef(0)},
+		{Text: "reply to bad", Visibility: entity.VisibilityHome, ReplyID: BatchRef(1)},
+		{Text: "independent", Visibility: entity.VisibilityHome},
+	}
+
+	results, err := repo.PostBatch(context.Background(), notes)
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected *MultiError, got %v", err)
+	}
+	if len(multi.Errors) != 2 || multi.Errors[0].Item != "note 2" || multi.Errors[1].Item != "note 3" {
+		t.Errorf("expected failures for notes 2 and 3, got %v", multi)
+	}
+	var apiErr *APIError
+	if !errors.As(multi.Errors[0], &apiErr) || apiErr.Code != "CONTAINS_PROHIBITED_WORDS" {
+		t.Errorf("expected the API error of note 2, got %v", multi.Errors[0])
+	}
+	if !errors.Is(err, ErrBatchReferenceFailed) {
+		t.Errorf("expected ErrBatchReferenceFailed for note 3, got %v", err)
+	}
+
+	if len(results) != len(notes) {
+		t.Fatalf("expected %d results, got %d", len(notes), len(results))
+	}
+	for i, want := range []string{"root", "", "", "independent"} {
+		if got := results[i]; (got == nil) != (want == "") || (got != nil && got.NoteID != want) {
+			t.Errorf("note %d: expected ID %q, got %+v", i+1, want, got)
+		}
+	}
+}
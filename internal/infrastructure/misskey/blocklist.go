@@ -0,0 +1,58 @@
+package misskey
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type blockPattern struct {
+	source string
+	re     *regexp.Regexp
+}
+
+func compileBlocklist(entries []string) ([]blockPattern, error) {
+	patterns := make([]blockPattern, 0, len(entries))
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + blocklistExpr(entry))
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist pattern %q: %w", entry, err)
+		}
+		patterns = append(patterns, blockPattern{source: entry, re: re})
+	}
+	return patterns, nil
+}
+
+func blocklistExpr(entry string) string {
+	if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+		return entry[1 : len(entry)-1]
+	}
+
+	expr := regexp.QuoteMeta(entry)
+	if first, _ := utf8.DecodeRuneInString(entry); isWordRune(first) {
+		expr = `\b` + expr
+	}
+	if last, _ := utf8.DecodeLastRuneInString(entry); isWordRune(last) {
+		expr += `\b`
+	}
+	return expr
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || (r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+}
+
+func (r *noteRepository) checkBlocklist(text string) error {
+	for _, p := range r.blocklist {
+		if p.re.MatchString(text) {
+			r.logf("Blocked outgoing note: matched blocklist pattern %q [text redacted, %d chars]", p.source, utf8.RuneCountInString(text))
+			return fmt.Errorf("%w: matched pattern %q", ErrBlockedContent, p.source)
+		}
+	}
+	return nil
+}
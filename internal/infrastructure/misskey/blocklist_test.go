@@ -0,0 +1,87 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestCompileBlocklist_Matching(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		text    string
+		blocked bool
+	}{
+		{"case insensitive keyword", []string{"spoiler"}, "Big SPOILER ahead", true},
+		{"keyword respects word boundary", []string{"cat"}, "concatenate strings", false},
+		{"keyword at boundary", []string{"cat"}, "my cat, again", true},
+		{"keyword with symbols", []string{"c++"}, "learning C++ today", true},
+		{"regex pattern", []string{`/gamb(le|ling)/`}, "Online Gambling news", true},
+		{"regex no match", []string{`/^breaking:/`}, "not breaking: news", false},
+		{"blank entries ignored", []string{"", "  "}, "anything", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, err := compileBlocklist(tt.entries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			repo := &noteRepository{blocklist: patterns}
+
+			err = repo.checkBlocklist(tt.text)
+			if tt.blocked && !errors.Is(err, ErrBlockedContent) {
+				t.Errorf("expected ErrBlockedContent, got %v", err)
+			}
+			if !tt.blocked && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCompileBlocklist_InvalidRegex(t *testing.T) {
+	if _, err := compileBlocklist([]string{"/(unclosed/"}); err == nil {
+		t.Error("expected error for invalid regex, got nil")
+	}
+}
+
+func TestNoteRepository_Post_Blocked(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	patterns, err := compileBlocklist([]string{"forbidden"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+		blocklist:   patterns,
+	}
+
+	err = repo.Post(context.Background(), entity.NewNote("This is Forbidden content", entity.VisibilityHome))
+	if !errors.Is(err, ErrBlockedContent) {
+		t.Fatalf("expected ErrBlockedContent, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests to be sent, got %d", requests)
+	}
+	if repo.rateLimiter.permits != 3 {
+		t.Errorf("expected blocked note not to consume a permit, got %d remaining", repo.rateLimiter.permits)
+	}
+}
@@ -0,0 +1,105 @@
+package misskey
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+type BodyEncoding string
+
+const (
+	BodyEncodingJSON BodyEncoding = "json"
+	BodyEncodingForm BodyEncoding = "form"
+)
+
+type bodyEncoder interface {
+	ContentType() string
+	Encode(body map[string]interface{}) ([]byte, error)
+}
+
+func newBodyEncoder(encoding BodyEncoding) (bodyEncoder, error) {
+	switch encoding {
+	case "", BodyEncodingJSON:
+		return jsonEncoder{}, nil
+	case BodyEncodingForm:
+		return formEncoder{}, nil
+	}
+	return nil, fmt.Errorf("unknown body encoding: %s", encoding)
+}
+
+func (r *noteRepository) bodyEncoder() bodyEncoder {
+	if r.encoder != nil {
+		return r.encoder
+	}
+	return jsonEncoder{}
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (jsonEncoder) Encode(body map[string]interface{}) ([]byte, error) {
+	return json.Marshal(body)
+}
+
+type formEncoder struct{}
+
+func (formEncoder) ContentType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+func (formEncoder) Encode(body map[string]interface{}) ([]byte, error) {
+	values := url.Values{}
+	for key, value := range body {
+		if err := flattenFormValue(values, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(values.Encode()), nil
+}
+
+func flattenFormValue(values url.Values, key string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		values.Set(key, v)
+	case bool:
+		values.Set(key, strconv.FormatBool(v))
+	case int:
+		values.Set(key, strconv.Itoa(v))
+	case int64:
+		values.Set(key, strconv.FormatInt(v, 10))
+	case float64:
+		values.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
+	case []string:
+		for i, item := range v {
+			values.Set(fmt.Sprintf("%s[%d]", key, i), item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := flattenFormValue(values, fmt.Sprintf("%s[%d]", key, i), item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := flattenFormValue(values, fmt.Sprintf("%s[%s]", key, k), v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported form value for %s: %T", key, value)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestFormEncoder_Flatten(t *testing.T) {
+	body := map[string]interface{}{
+		"text":      "hello world",
+		"localOnly": true,
+		"fileIds":   []string{"f1", "f2"},
+		"poll": map[string]interface{}{
+			"choices":  []interface{}{"yes", "no"},
+			"multiple": false,
+		},
+		"scheduledAt": int64(1700000000000),
+		"replyId":     nil,
+	}
+
+	encoded, err := formEncoder{}.Encode(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values, err := url.ParseQuery(string(encoded))
+	if err != nil {
+		t.Fatalf("failed to parse encoded form: %v", err)
+	}
+
+	expected := map[string]string{
+		"text":             "hello world",
+		"localOnly":        "true",
+		"fileIds[0]":       "f1",
+		"fileIds[1]":       "f2",
+		"poll[choices][0]": "yes",
+		"poll[choices][1]": "no",
+		"poll[multiple]":   "false",
+		"scheduledAt":      "1700000000000",
+	}
+	for key, want := range expected {
+		if got := values.Get(key); got != want {
+			t.Errorf("expected %s=%q, got %q", key, want, got)
+		}
+	}
+	if values.Has("replyId") {
+		t.Error("expected nil values to be omitted")
+	}
+}
+
+func TestNewBodyEncoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		encoding    BodyEncoding
+		contentType string
+		expectErr   bool
+	}{
+		{"default", "", "application/json", false},
+		{"json", BodyEncodingJSON, "application/json", false},
+		{"form", BodyEncodingForm, "application/x-www-form-urlencoded", false},
+		{"unknown", "xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, err := newBodyEncoder(tt.encoding)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if err == nil && encoder.ContentType() != tt.contentType {
+				t.Errorf("expected content type %s, got %s", tt.contentType, encoder.ContentType())
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_BodyEncodings(t *testing.T) {
+	tests := []struct {
+		name              string
+		encoding          BodyEncoding
+		expectContentType string
+	}{
+		{"json", BodyEncodingJSON, "application/json"},
+		{"form", BodyEncodingForm, "application/x-www-form-urlencoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var text, token, fileID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != tt.expectContentType {
+					t.Errorf("expected content-type %s, got %s", tt.expectContentType, ct)
+				}
+
+				if tt.encoding == BodyEncodingForm {
+					r.ParseForm()
+					text, token, fileID = r.PostForm.Get("text"), r.PostForm.Get("i"), r.PostForm.Get("fileIds[0]")
+				} else {
+					var payload struct {
+						I       string   `json:"i"`
+						Text    string   `json:"text"`
+						FileIDs []string `json:"fileIds"`
+					}
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					text, token = payload.Text, payload.I
+					if len(payload.FileIDs) > 0 {
+						fileID = payload.FileIDs[0]
+					}
+				}
+
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			encoder, err := newBodyEncoder(tt.encoding)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+				encoder:     encoder,
+			}
+
+			note := entity.NewNote("Test & note", entity.VisibilityHome)
+			note.FileIDs = []string{"file1"}
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if text != "Test & note" || token != "test-token" || fileID != "file1" {
+				t.Errorf("unexpected decoded payload: text=%q token=%q fileId=%q", text, token, fileID)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r *noteRepository) EnsureBotFlag(ctx context.Context) error {
+	accounts := r.postingAccounts()
+	errs := newMultiError("failed to ensure bot flag", len(accounts))
+	for i, account := range accounts {
+		var me struct {
+			Username string `json:"username"`
+			IsBot    bool   `json:"isBot"`
+		}
+		if err := r.call(ctx, "i", map[string]interface{}{"i": account.authToken}, &me); err != nil {
+			errs.Add(fmt.Sprintf("token #%d", i), err)
+			continue
+		}
+		if me.IsBot {
+			continue
+		}
+
+		if !r.setBotFlag {
+			r.logf("Warning: account @%s is not flagged as a bot; enable SetBotFlag to update it automatically", me.Username)
+			continue
+		}
+		if err := r.call(ctx, "i/update", map[string]interface{}{"i": account.authToken, "isBot": true}, nil); err != nil {
+			errs.Add(fmt.Sprintf("token #%d", i), err)
+			continue
+		}
+		r.logf("Flagged account @%s as a bot account", me.Username)
+	}
+
+	return errs.ErrOrNil()
+}
@@ -0,0 +1,68 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_EnsureBotFlag(t *testing.T) {
+	tests := []struct {
+		name         string
+		isBot        bool
+		setBotFlag   bool
+		updateStatus int
+		expectUpdate bool
+		expectErr    bool
+	}{
+		{"already a bot", true, true, http.StatusNoContent, false, false},
+		{"not a bot, updates disabled", false, false, http.StatusNoContent, false, false},
+		{"not a bot, flag set", false, true, http.StatusNoContent, true, false},
+		{"update rejected", false, true, http.StatusForbidden, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updatePayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/i":
+					fmt.Fprintf(w, `{"username": "rssbot", "isBot": %v}`, tt.isBot)
+				case "/api/i/update":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &updatePayload)
+					w.WriteHeader(tt.updateStatus)
+				default:
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:       server.URL,
+				authToken:  "test-token",
+				client:     &http.Client{Timeout: 30 * time.Second},
+				setBotFlag: tt.setBotFlag,
+			}
+
+			err := repo.EnsureBotFlag(context.Background())
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if (updatePayload != nil) != tt.expectUpdate {
+				t.Fatalf("expected update=%v, got payload %v", tt.expectUpdate, updatePayload)
+			}
+			if tt.expectUpdate && updatePayload["isBot"] != true {
+				t.Errorf("expected isBot=true in update, got %v", updatePayload["isBot"])
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package misskey
+
+import (
+	"context"
+	"sync"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type selfChain struct {
+	mu     sync.Mutex
+	lastID string
+}
+
+func (r *noteRepository) postChained(ctx context.Context, account *postingAccount, note *entity.Note, req noteRequest) (string, error) {
+	r.chain.mu.Lock()
+	defer r.chain.mu.Unlock()
+
+	chained := *note
+	chained.ReplyID = r.chain.lastID
+	noteID, err := r.postNote(ctx, account, &chained, req)
+	if err != nil && chained.ReplyID != "" && isNoSuchReplyTarget(err) {
+		r.logf("Previous chained note [%s] no longer exists, starting a fresh chain", chained.ReplyID)
+		chained.ReplyID = ""
+		noteID, err = r.postNote(ctx, account, &chained, req)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	r.chain.lastID = noteID
+	r.saveState(stateNamespaceCursors, stateKeyChain, noteID)
+	return noteID, nil
+}
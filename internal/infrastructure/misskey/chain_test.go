@@ -0,0 +1,82 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_Post_ChainToSelf(t *testing.T) {
+	created := 0
+	deleted := map[string]bool{}
+	var replyIDs []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+
+		if replyID, ok := payload["replyId"].(string); ok && deleted[replyID] {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "NO_SUCH_REPLY_TARGET"}}`))
+			return
+		}
+		created++
+		replyIDs = append(replyIDs, payload["replyId"])
+		fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, created)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("failed to open state store: %v", err)
+	}
+	newRepo := func() *noteRepository {
+		repo := &noteRepository{
+			host:        server.URL,
+			authToken:   "test-token",
+			client:      &http.Client{Timeout: 30 * time.Second},
+			rateLimiter: newRateLimiter(10, 10*time.Second),
+			chainToSelf: true,
+			state:       store,
+		}
+		if err := repo.restoreState(context.Background()); err != nil {
+			t.Fatalf("unexpected restore error: %v", err)
+		}
+		return repo
+	}
+
+	repo := newRepo()
+	ctx := context.Background()
+	post := func(repo *noteRepository, note *entity.Note) {
+		t.Helper()
+		if err := repo.Post(ctx, note); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	post(repo, entity.NewNote("First", entity.VisibilityHome))
+	post(repo, entity.NewNote("Second", entity.VisibilityHome))
+	deleted["note2"] = true
+	post(repo, entity.NewNote("Third", entity.VisibilityHome))
+	post(repo, &entity.Note{Text: "Explicit reply", Visibility: entity.VisibilityHome, ReplyID: "other"})
+	post(newRepo(), entity.NewNote("After restart", entity.VisibilityHome))
+
+	expected := []interface{}{nil, "note1", nil, "other", "note3"}
+	if len(replyIDs) != len(expected) {
+		t.Fatalf("expected %d notes, got %d", len(expected), len(replyIDs))
+	}
+	for i, replyID := range expected {
+		if replyIDs[i] != replyID {
+			t.Errorf("note %d: expected replyId %v, got %v", i+1, replyID, replyIDs[i])
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package misskey
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+)
+
+const compressThresholdBytes = 1024
+
+func (r *noteRepository) shouldCompress(payload []byte) bool {
+	return r.compressRequests && !r.compressionUnsupported.Load() && len(payload) >= compressThresholdBytes
+}
+
+func isCompressionRejected(statusCode int, respBody []byte) bool {
+	switch statusCode {
+	case http.StatusUnsupportedMediaType:
+		return true
+	case http.StatusBadRequest:
+		return parseAPIError(statusCode, respBody).Code == ""
+	default:
+		return false
+	}
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package misskey
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_CompressRequests(t *testing.T) {
+	longText := strings.Repeat("long note text ", 200)
+
+	tests := []struct {
+		name              string
+		compress          bool
+		text              string
+		acceptGzip        bool
+		expectedEncodings []string
+		expectUnsupported bool
+	}{
+		{"disabled sends plain body", false, longText, true, []string{""}, false},
+		{"small body stays uncompressed", true, "short", true, []string{""}, false},
+		{"large body is compressed", true, longText, true, []string{"gzip"}, false},
+		{"rejected compression falls back", true, longText, false, []string{"gzip", ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var encodings []string
+			var receivedText string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding := r.Header.Get("Content-Encoding")
+				encodings = append(encodings, encoding)
+
+				var reader io.Reader = r.Body
+				if encoding == "gzip" {
+					if !tt.acceptGzip {
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Fatalf("failed to create gzip reader: %v", err)
+					}
+					reader = zr
+				}
+
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(reader)
+				json.Unmarshal(body, &payload)
+				receivedText, _ = payload["text"].(string)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:             server.URL,
+				authToken:        "test-token",
+				client:           &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:      newRateLimiter(3, 10*time.Second),
+				compressRequests: tt.compress,
+			}
+
+			if err := repo.Post(context.Background(), entity.NewNote(tt.text, entity.VisibilityHome)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(encodings) != len(tt.expectedEncodings) {
+				t.Fatalf("expected %d requests, got %d", len(tt.expectedEncodings), len(encodings))
+			}
+			for i, enc := range encodings {
+				if enc != tt.expectedEncodings[i] {
+					t.Errorf("request %d: expected encoding '%s', got '%s'", i+1, tt.expectedEncodings[i], enc)
+				}
+			}
+			if receivedText != tt.text {
+				t.Error("expected server to receive the full note text")
+			}
+			if repo.compressionUnsupported.Load() != tt.expectUnsupported {
+				t.Errorf("expected compressionUnsupported=%v, got %v", tt.expectUnsupported, repo.compressionUnsupported.Load())
+			}
+		})
+	}
+}
+
+func TestIsCompressionRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{"unsupported media type", http.StatusUnsupportedMediaType, "", true},
+		{"non-misskey bad request", http.StatusBadRequest, `{"statusCode":400,"message":"Body is not valid JSON"}`, true},
+		{"misskey validation error", http.StatusBadRequest, `{"error":{"code":"INVALID_PARAM","message":"Invalid param."}}`, false},
+		{"ok", http.StatusOK, `{}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCompressionRejected(tt.statusCode, []byte(tt.body)); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
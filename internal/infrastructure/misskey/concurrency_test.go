@@ -0,0 +1,95 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_ConcurrentUse(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/emojis"):
+			w.Write([]byte(`{"emojis": [{"name": "blobcat"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/announcements"):
+			w.Write([]byte(`[]`))
+		case strings.HasSuffix(r.URL.Path, "/notes/create"):
+			n := posts.Add(1)
+			w.Header().Set("X-RateLimit-Remaining", "100")
+			w.Header().Set("X-RateLimit-Reset", "1")
+			fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, n)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	created, err := NewNoteRepository(Config{
+		Host:               server.URL,
+		AuthToken:          "test-token",
+		MaxPermits:         1000,
+		RefillInterval:     time.Millisecond,
+		ValidateEmojis:     true,
+		CheckAnnouncements: true,
+		DedupeStore:        storage.NewMemoryDedupeStore(),
+		ChainToSelf:        true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := created.(*noteRepository)
+
+	const workers = 8
+	const perWorker = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker)
+	for w := 0; w < workers; w++ {
+		wg.Add(3)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				note := entity.NewNote(fmt.Sprintf("worker %d note %d :blobcat:", w, i), entity.VisibilityHome)
+				if err := repo.Post(context.Background(), note); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				note := entity.NewNote(fmt.Sprintf("changed %d", i%2), entity.VisibilityHome)
+				if _, err := repo.PostIfChanged(context.Background(), fmt.Sprintf("feed-%d", w), note); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				repo.Stats()
+				repo.InvalidateCache()
+				repo.RateLimitStatus(context.Background())
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := repo.Stats().RateLimiter.Waits; got < workers*perWorker {
+		t.Errorf("expected at least %d limiter waits, got %d", workers*perWorker, got)
+	}
+}
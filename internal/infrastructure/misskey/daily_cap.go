@@ -0,0 +1,133 @@
+package misskey
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type dailyCap struct {
+	mu           sync.Mutex
+	limit        int
+	day          time.Time
+	count        int
+	blockedUntil time.Time
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+func (c *dailyCap) reserve(now time.Time, loc *time.Location) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Before(c.blockedUntil) {
+		return fmt.Errorf("%w: posting paused until %s", ErrDailyNoteCapReached, c.blockedUntil.Format(time.RFC3339))
+	}
+	if c.limit <= 0 {
+		return nil
+	}
+
+	if day := startOfDay(now, loc); !day.Equal(c.day) {
+		c.day = day
+		c.count = 0
+	}
+	if c.count >= c.limit {
+		return fmt.Errorf("%w: %d posts today, next slot at %s", ErrDailyNoteCapReached, c.count, c.day.AddDate(0, 0, 1).Format(time.RFC3339))
+	}
+	c.count++
+	return nil
+}
+
+func (c *dailyCap) refund(reservedAt time.Time, loc *time.Location) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.count > 0 && startOfDay(reservedAt, loc).Equal(c.day) {
+		c.count--
+	}
+}
+
+func (c *dailyCap) block(until time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until.After(c.blockedUntil) {
+		c.blockedUntil = until
+	}
+}
+
+func defaultDailyCapErrorCodes() []string {
+	return []string{"DAILY_NOTE_LIMIT_EXCEEDED", "NOTE_DAILY_LIMIT_EXCEEDED", "DAILY_LIMIT_EXCEEDED"}
+}
+
+func (r *noteRepository) isDailyCapCode(code string) bool {
+	if code == "" {
+		return false
+	}
+	if len(r.dailyCapErrorCodes) > 0 {
+		return slices.Contains(r.dailyCapErrorCodes, code)
+	}
+	return slices.Contains(defaultDailyCapErrorCodes(), code)
+}
+
+func (r *noteRepository) markDailyCapReached(account *postingAccount, apiErr *APIError, wait time.Duration, known bool) error {
+	now := time.Now()
+	until := startOfDay(now, r.timeZone).AddDate(0, 0, 1)
+	if known {
+		until = now.Add(wait)
+		r.logf("Warning: Daily note cap reached (%s), instance reports reset at %s", apiErr.Code, until.Format(time.RFC3339))
+	} else {
+		r.logf("Warning: Daily note cap reached (%s), pausing posts until %s", apiErr.Code, until.Format(time.RFC3339))
+	}
+	account.dailyCap.block(until)
+	return fmt.Errorf("%w: %w", ErrDailyNoteCapReached, apiErr)
+}
+
+func (a *postingAccount) reservePost(now time.Time, loc *time.Location) error {
+	if err := a.dailyCap.reserve(now, loc); err != nil {
+		return err
+	}
+	if err := a.hourlyCap.reserve(now); err != nil {
+		a.dailyCap.refund(now, loc)
+		return err
+	}
+	return nil
+}
+
+// refundPost gives back a reservation whose note was never created.
+func (a *postingAccount) refundPost(reservedAt time.Time, loc *time.Location) {
+	a.dailyCap.refund(reservedAt, loc)
+	a.hourlyCap.refund(reservedAt)
+}
+
+// reserveAccount picks the next posting account that is not suspended and
+// has room left under its own caps, so one account does not block the others.
+func (r *noteRepository) reserveAccount(now time.Time) (int, *postingAccount, error) {
+	accounts := r.postingAccounts()
+	start, _ := r.selectAccount()
+	var firstErr error
+	for i := range accounts {
+		index := (start + i) % len(accounts)
+		if accounts[index].suspended.Load() {
+			if firstErr == nil {
+				firstErr = ErrAccountSuspended
+			}
+			continue
+		}
+		err := accounts[index].reservePost(now, r.timeZone)
+		if err == nil {
+			return index, accounts[index], nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return 0, nil, firstErr
+}
@@ -0,0 +1,153 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestDailyCap_Reserve(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	morning := time.Date(2024, 3, 1, 9, 0, 0, 0, jst)
+
+	tests := []struct {
+		name         string
+		limit        int
+		blockedUntil time.Time
+		times        []time.Time
+		expectErrs   []bool
+	}{
+		{"unlimited", 0, time.Time{}, []time.Time{morning, morning, morning}, []bool{false, false, false}},
+		{"cap within a day", 2, time.Time{}, []time.Time{morning, morning.Add(time.Hour), morning.Add(2 * time.Hour)}, []bool{false, false, true}},
+		{"resets at local midnight", 1, time.Time{}, []time.Time{morning, morning.Add(14 * time.Hour), morning.Add(15 * time.Hour)}, []bool{false, true, false}},
+		{"blocked until reset", 0, morning.Add(30 * time.Minute), []time.Time{morning, morning.Add(time.Hour)}, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &dailyCap{limit: tt.limit, blockedUntil: tt.blockedUntil}
+			for i, now := range tt.times {
+				err := c.reserve(now, jst)
+				if (err != nil) != tt.expectErrs[i] {
+					t.Errorf("post %d: expected error=%v, got %v", i, tt.expectErrs[i], err)
+				}
+				if err != nil && !errors.Is(err, ErrDailyNoteCapReached) {
+					t.Errorf("post %d: expected ErrDailyNoteCapReached, got %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_DailyCapFromServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		codes       []string
+		expectPause time.Duration
+	}{
+		{"reset from body", `{"error":{"code":"DAILY_NOTE_LIMIT_EXCEEDED","info":{"resetMs":60000}}}`, nil, time.Minute},
+		{"custom code without reset pauses until midnight", `{"error":{"code":"QUOTA_EXCEEDED"}}`, []string{"QUOTA_EXCEEDED"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			repo := newRetryTestRepository(server.URL, 3, time.Millisecond)
+			repo.dailyCapErrorCodes = tt.codes
+			repo.timeZone = time.UTC
+
+			err := repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome))
+			if !errors.Is(err, ErrDailyNoteCapReached) {
+				t.Fatalf("expected ErrDailyNoteCapReached, got %v", err)
+			}
+			if attempts != 1 {
+				t.Errorf("expected no retries against the daily cap, got %d attempts", attempts)
+			}
+
+			if err := repo.Post(context.Background(), entity.NewNote("Again", entity.VisibilityHome)); !errors.Is(err, ErrDailyNoteCapReached) {
+				t.Errorf("expected second post to be paused, got %v", err)
+			}
+			if attempts != 1 {
+				t.Errorf("expected paused post not to reach the server, got %d attempts", attempts)
+			}
+
+			expected := startOfDay(time.Now(), time.UTC).AddDate(0, 0, 1)
+			if tt.expectPause > 0 {
+				expected = time.Now().Add(tt.expectPause)
+			}
+			blockedUntil := repo.postingAccounts()[0].dailyCap.blockedUntil
+			if diff := blockedUntil.Sub(expected); diff < -5*time.Second || diff > 5*time.Second {
+				t.Errorf("expected pause until ~%v, got %v", expected, blockedUntil)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_CapsPerAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxPerDay      int
+		rejected       map[string]string
+		expectedTokens []string
+		expectedPosted int
+	}{
+		{"capped account falls through to the next", 1, nil, []string{"a", "b"}, 2},
+		{"server cap blocks only that account", 0, map[string]string{"a": "DAILY_NOTE_LIMIT_EXCEEDED"}, []string{"a", "b", "b"}, 2},
+		{"failed post is refunded", 1, map[string]string{"a": "CONTAINS_PROHIBITED_WORDS"}, []string{"a", "b", "a"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+				token, _ := payload["i"].(string)
+				tokens = append(tokens, token)
+				if code, ok := tt.rejected[token]; ok {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, `{"error":{"code":%q}}`, code)
+					return
+				}
+				w.Write([]byte(`{"createdNote":{"id":"n1"}}`))
+			}))
+			defer server.Close()
+
+			repo := newRetryTestRepository(server.URL, 0, time.Millisecond)
+			repo.timeZone = time.UTC
+			repo.accounts = []*postingAccount{
+				newPostingAccount("a", newRateLimiter(10, time.Second), 0, tt.maxPerDay),
+				newPostingAccount("b", newRateLimiter(10, time.Second), 0, tt.maxPerDay),
+			}
+
+			posted := 0
+			for i := 0; i < 3; i++ {
+				if err := repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome)); err == nil {
+					posted++
+				}
+			}
+			if posted != tt.expectedPosted {
+				t.Errorf("expected %d posts, got %d", tt.expectedPosted, posted)
+			}
+			if strings.Join(tokens, ",") != strings.Join(tt.expectedTokens, ",") {
+				t.Errorf("expected requests from %v, got %v", tt.expectedTokens, tokens)
+			}
+		})
+	}
+}
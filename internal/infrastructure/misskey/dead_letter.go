@@ -0,0 +1,100 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+// deadLetterSeenTTL bounds the in-memory fallback used without a dedupe or
+// state store. That fallback is best effort: a dead note reaches the callback
+// again once a day, and again after every restart.
+const deadLetterSeenTTL = 24 * time.Hour
+
+type deadLetters struct {
+	mu   sync.Mutex
+	seen keyedTTLCache[struct{}]
+}
+
+// markDeadLetter reports whether the note with content hash key is being
+// dead-lettered for the first time, recording it in the configured store so
+// it is reported exactly once across restarts.
+func (r *noteRepository) markDeadLetter(ctx context.Context, key string) (bool, error) {
+	r.deadLetters.mu.Lock()
+	defer r.deadLetters.mu.Unlock()
+
+	switch {
+	case r.dedupe != nil:
+		seen, err := r.dedupe.Seen(ctx, stateNamespaceDead+":"+key)
+		if err != nil || seen {
+			return !seen, err
+		}
+		return true, r.dedupe.Remember(ctx, stateNamespaceDead+":"+key, []byte(key), 0)
+	case r.state != nil:
+		_, seen, err := r.state.Get(ctx, stateNamespaceDead, key)
+		if err != nil || seen {
+			return !seen, err
+		}
+		return true, r.state.Put(ctx, stateNamespaceDead, key, []byte("true"))
+	}
+
+	now := time.Now()
+	if _, ok := r.deadLetters.seen.get(key, now); ok {
+		return false, nil
+	}
+	r.deadLetters.seen.set(key, struct{}{}, deadLetterSeenTTL, now)
+	return true, nil
+}
+
+func permanentErrors() []error {
+	return []error{
+		ErrBlockedContent, ErrInvalidEncoding, ErrFileNotFound, ErrUnresolvableNote,
+		ErrCWRequired, ErrTooManyFiles, ErrInvalidLang, ErrFileTooLarge,
+		ErrFederationConflict, ErrInvalidBatchReference, ErrInvalidVisibility,
+		ErrTextTooLong, ErrEmptyNote, ErrNoteTooLargeBytes, ErrInvalidPoll,
+		ErrUnresolvableMention, ErrQuoteTargetNotFound, ErrSchedulingUnsupported,
+	}
+}
+
+func (r *noteRepository) isPermanentFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrAccountSuspended) || errors.Is(err, ErrDailyNoteCapReached) {
+		return false
+	}
+	for _, permanent := range permanentErrors() {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || isRetryable(err, r.retryableCodes()) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return apiErr.StatusCode >= http.StatusBadRequest && apiErr.StatusCode < http.StatusInternalServerError
+}
+
+func (r *noteRepository) reportDeadLetter(ctx context.Context, note *entity.Note, err error) {
+	if r.deadLetter == nil || !r.isPermanentFailure(err) {
+		return
+	}
+
+	key, hashErr := contentHash(note)
+	if hashErr != nil {
+		r.logf("Warning: Could not fingerprint dead note, reporting it anyway: %v", hashErr)
+	} else if first, markErr := r.markDeadLetter(context.WithoutCancel(ctx), key); markErr != nil {
+		r.logf("Warning: Could not record dead note, reporting it anyway: %v", markErr)
+	} else if !first {
+		return
+	}
+
+	r.logf("Warning: Note will never be delivered, handing it to the dead-letter callback: %v", err)
+	r.deadLetter(note, err)
+}
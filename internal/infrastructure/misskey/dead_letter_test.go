@@ -0,0 +1,123 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_DeadLetter(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		maxNoteBytes  int
+		hourlyCap     int
+		expectedCalls int
+	}{
+		{"rejected content", http.StatusBadRequest, `{"error":{"code":"CONTAINS_PROHIBITED_WORDS"}}`, 0, 0, 1},
+		{"client side validation", http.StatusOK, `{"createdNote":{"id":"n1"}}`, 3, 0, 1},
+		{"server error", http.StatusInternalServerError, `{"error":{"code":"INTERNAL_ERROR"}}`, 0, 0, 0},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"code":"RATE_LIMIT_EXCEEDED"}}`, 0, 0, 0},
+		{"bad credentials", http.StatusUnauthorized, `{"error":{"code":"CREDENTIAL_REQUIRED"}}`, 0, 0, 0},
+		{"account suspended", http.StatusForbidden, `{"error":{"code":"YOUR_ACCOUNT_SUSPENDED"}}`, 0, 0, 0},
+		{"hourly cap", http.StatusOK, `{"createdNote":{"id":"n1"}}`, 0, 1, 0},
+		{"success", http.StatusOK, `{"createdNote":{"id":"n1"}}`, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var dead []error
+			repo := newRetryTestRepository(server.URL, 0, time.Millisecond)
+			repo.maxNoteBytes = tt.maxNoteBytes
+			repo.maxPostsPerHour = tt.hourlyCap
+			repo.deadLetter = func(note *entity.Note, err error) {
+				if note.Text != "Hello" {
+					t.Errorf("expected the failed note, got %q", note.Text)
+				}
+				dead = append(dead, err)
+			}
+
+			for i := 0; i < 3; i++ {
+				repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome))
+			}
+			if len(dead) != tt.expectedCalls {
+				t.Errorf("expected %d dead-letter calls, got %d: %v", tt.expectedCalls, len(dead), dead)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_DeadLetter_CancelledContext(t *testing.T) {
+	called := false
+	repo := &noteRepository{
+		rateLimiter: newRateLimiter(0, time.Hour),
+		deadLetter:  func(*entity.Note, error) { called = true },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := repo.Post(ctx, entity.NewNote("Hello", entity.VisibilityHome)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("expected no dead-letter call for a cancelled post")
+	}
+}
+
+func TestNoteRepository_DeadLetter_PersistedAcrossRestarts(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(t *testing.T) func(repo *noteRepository)
+	}{
+		{"dedupe store", func(t *testing.T) func(repo *noteRepository) {
+			store := storage.NewMemoryDedupeStore()
+			return func(repo *noteRepository) { repo.dedupe = store }
+		}},
+		{"state store", func(t *testing.T) func(repo *noteRepository) {
+			store, err := storage.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatalf("failed to open state store: %v", err)
+			}
+			return func(repo *noteRepository) { repo.state = store }
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"code":"CONTAINS_PROHIBITED_WORDS"}}`))
+			}))
+			defer server.Close()
+
+			calls := 0
+			attach := tt.open(t)
+			for restart := 0; restart < 2; restart++ {
+				repo := newRetryTestRepository(server.URL, 0, time.Millisecond)
+				repo.deadLetter = func(*entity.Note, error) { calls++ }
+				attach(repo)
+
+				for i := 0; i < 2; i++ {
+					repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome))
+				}
+			}
+			if calls != 1 {
+				t.Errorf("expected 1 dead-letter call across restarts, got %d", calls)
+			}
+		})
+	}
+}
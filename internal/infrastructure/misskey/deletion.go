@@ -0,0 +1,168 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const deletionTimeout = 30 * time.Second
+
+type PendingDeletion struct {
+	NoteID     string
+	DeleteAt   time.Time
+	TokenIndex int
+}
+
+type scheduledDeletion struct {
+	timer   *time.Timer
+	pending PendingDeletion
+}
+
+type deletionScheduler struct {
+	mu      sync.Mutex
+	pending map[string]scheduledDeletion
+	closed  bool
+}
+
+func (s *deletionScheduler) schedule(d PendingDeletion, fn func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	if s.pending == nil {
+		s.pending = make(map[string]scheduledDeletion)
+	}
+	if existing, ok := s.pending[d.NoteID]; ok {
+		existing.timer.Stop()
+	}
+
+	s.pending[d.NoteID] = scheduledDeletion{
+		timer:   time.AfterFunc(time.Until(d.DeleteAt), fn),
+		pending: d,
+	}
+	return true
+}
+
+func (s *deletionScheduler) done(noteID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, noteID)
+}
+
+func (s *deletionScheduler) list() []PendingDeletion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deletions := make([]PendingDeletion, 0, len(s.pending))
+	for _, d := range s.pending {
+		deletions = append(deletions, d.pending)
+	}
+	sort.Slice(deletions, func(i, j int) bool {
+		return deletions[i].DeleteAt.Before(deletions[j].DeleteAt)
+	})
+	return deletions
+}
+
+func (s *deletionScheduler) stop() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, d := range s.pending {
+		d.timer.Stop()
+	}
+	return len(s.pending)
+}
+
+func (r *noteRepository) DeleteNote(ctx context.Context, noteID string) error {
+	token, err := r.ownerToken(ctx, noteID)
+	if err != nil {
+		return err
+	}
+	return r.deleteNote(ctx, noteID, token)
+}
+
+// ownerToken returns the token of the posting account that wrote noteID,
+// looking the note up only when there is more than one account.
+func (r *noteRepository) ownerToken(ctx context.Context, noteID string) (string, error) {
+	if len(r.postingAccounts()) == 1 {
+		return r.postingAccounts()[0].authToken, nil
+	}
+	note, err := r.GetNote(ctx, noteID)
+	if err != nil {
+		return "", err
+	}
+	return r.accountByUserID(ctx, note.UserID).authToken, nil
+}
+
+func (r *noteRepository) deleteNote(ctx context.Context, noteID, authToken string) error {
+	ctx, done, err := r.ops.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	params := map[string]interface{}{"i": authToken, "noteId": noteID}
+	if err := r.callWithRetry(ctx, "notes/delete", params, nil); err != nil {
+		return fmt.Errorf("failed to delete note [%s]: %w", noteID, err)
+	}
+	r.audit(auditEntry{Action: "delete", NoteID: noteID})
+	return nil
+}
+
+func (r *noteRepository) DeleteMany(ctx context.Context, noteIDs []string) error {
+	errs := newMultiError("failed to delete notes", len(noteIDs))
+	for _, noteID := range noteIDs {
+		if err := r.DeleteNote(ctx, noteID); err != nil {
+			errs.Add(noteID, err)
+		}
+	}
+	return errs.ErrOrNil()
+}
+
+func (r *noteRepository) ScheduleDeletion(d PendingDeletion) {
+	r.scheduleDeletion(d)
+}
+
+func (r *noteRepository) PendingDeletions() []PendingDeletion {
+	return r.deletions.list()
+}
+
+func (r *noteRepository) scheduleDeletion(d PendingDeletion) {
+	scheduled := r.deletions.schedule(d, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), deletionTimeout)
+		defer cancel()
+
+		if err := r.deleteNote(ctx, d.NoteID, r.tokenAt(d.TokenIndex)); err != nil {
+			r.logf("Failed to delete expired note: %v", err)
+		} else {
+			r.logf("Deleted expired note [%s]", d.NoteID)
+		}
+		r.deletions.done(d.NoteID)
+		r.deleteState(stateNamespaceDeletions, d.NoteID)
+	})
+	if !scheduled {
+		r.logf("Repository is closed, deletion of note [%s] at %v was not scheduled", d.NoteID, d.DeleteAt)
+		return
+	}
+	r.saveState(stateNamespaceDeletions, d.NoteID, d)
+}
+
+func (r *noteRepository) Close(ctx context.Context) error {
+	if abandoned := r.deletions.stop(); abandoned > 0 {
+		r.logf("Stopped %d pending note deletions; use PendingDeletions to resume them", abandoned)
+	}
+	err := r.drain(ctx)
+	if r.auditLog != nil {
+		if closeErr := r.auditLog.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
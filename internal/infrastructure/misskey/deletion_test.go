@@ -0,0 +1,165 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type deletionTestServer struct {
+	mu      sync.Mutex
+	deleted []string
+	server  *httptest.Server
+}
+
+func newDeletionTestServer() *deletionTestServer {
+	s := &deletionTestServer{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/notes/create":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+		case "/api/notes/delete":
+			var payload map[string]interface{}
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &payload)
+			s.mu.Lock()
+			s.deleted = append(s.deleted, payload["noteId"].(string))
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	return s
+}
+
+func (s *deletionTestServer) deletedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.deleted...)
+}
+
+func TestNoteRepository_PostWithOptions_DeleteAfter(t *testing.T) {
+	ts := newDeletionTestServer()
+	defer ts.server.Close()
+
+	repo := &noteRepository{
+		host:        ts.server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	result, err := repo.PostWithOptions(context.Background(), entity.NewNote("Ephemeral", entity.VisibilityHome), PostOptions{DeleteAfter: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NoteID != "note123" {
+		t.Errorf("expected note ID 'note123', got '%s'", result.NoteID)
+	}
+
+	pending := repo.PendingDeletions()
+	if len(pending) != 1 || pending[0].NoteID != "note123" {
+		t.Fatalf("expected pending deletion for note123, got %+v", pending)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ts.deletedIDs()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	deleted := ts.deletedIDs()
+	if len(deleted) != 1 || deleted[0] != "note123" {
+		t.Fatalf("expected note123 to be deleted, got %v", deleted)
+	}
+
+	for len(repo.PendingDeletions()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(repo.PendingDeletions()) != 0 {
+		t.Errorf("expected no pending deletions after delete, got %+v", repo.PendingDeletions())
+	}
+}
+
+func TestNoteRepository_Close_StopsPendingDeletions(t *testing.T) {
+	ts := newDeletionTestServer()
+	defer ts.server.Close()
+
+	repo := &noteRepository{
+		host:        ts.server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	deleteAt := time.Now().Add(50 * time.Millisecond)
+	repo.ScheduleDeletion(PendingDeletion{NoteID: "note123", DeleteAt: deleteAt})
+
+	if err := repo.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if deleted := ts.deletedIDs(); len(deleted) != 0 {
+		t.Errorf("expected no deletions after close, got %v", deleted)
+	}
+
+	pending := repo.PendingDeletions()
+	if len(pending) != 1 || !pending[0].DeleteAt.Equal(deleteAt) {
+		t.Errorf("expected pending deletion to be kept for resumption, got %+v", pending)
+	}
+
+	repo.ScheduleDeletion(PendingDeletion{NoteID: "note456", DeleteAt: time.Now()})
+	if len(repo.PendingDeletions()) != 1 {
+		t.Errorf("expected no new deletions to be scheduled after close")
+	}
+}
+
+func TestNoteRepository_DeleteMany_UsesOwnerToken(t *testing.T) {
+	var mu sync.Mutex
+	deletedBy := map[string]interface{}{}
+	authors := map[string]string{"note1": "bot1", "note2": "bot2"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		noteID, _ := payload["noteId"].(string)
+
+		switch r.URL.Path {
+		case "/api/notes/show":
+			w.Write([]byte(`{"id": "` + noteID + `", "userId": "` + authors[noteID] + `"}`))
+		case "/api/notes/delete":
+			mu.Lock()
+			deletedBy[noteID] = payload["i"]
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "token-a",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+		accounts: []*postingAccount{
+			{authToken: "token-a", rateLimiter: newRateLimiter(10, 10*time.Second), userID: "bot1"},
+			{authToken: "token-b", rateLimiter: newRateLimiter(10, 10*time.Second), userID: "bot2"},
+		},
+	}
+
+	if err := repo.DeleteMany(context.Background(), []string{"note1", "note2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deletedBy["note1"] != "token-a" || deletedBy["note2"] != "token-b" {
+		t.Errorf("expected each note deleted by its author, got %v", deletedBy)
+	}
+}
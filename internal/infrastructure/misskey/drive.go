@@ -0,0 +1,153 @@
+package misskey
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const (
+	defaultMaxAttachmentBytes = 10 << 20
+	attachmentDownloadTimeout = 30 * time.Second
+	defaultAttachmentName     = "attachment"
+)
+
+type driveRepository struct {
+	notes *noteRepository
+}
+
+func NewDriveRepository(cfg Config) (repository.DriveRepository, error) {
+	notes, err := newNoteRepository(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &driveRepository{notes: notes}, nil
+}
+
+func (d *driveRepository) UploadFromURL(ctx context.Context, fileURL string) (string, error) {
+	return d.notes.uploadFromURL(ctx, d.notes.postingAccounts()[0], fileURL)
+}
+
+func (r *noteRepository) withAttachments(ctx context.Context, account *postingAccount, note *entity.Note) *entity.Note {
+	if len(note.AttachmentURLs) == 0 {
+		return note
+	}
+
+	attached := *note
+	attached.FileIDs = append([]string(nil), note.FileIDs...)
+	for _, fileURL := range note.AttachmentURLs {
+		if r.onFileOverflow != FileOverflowThread && len(attached.FileIDs) >= r.maxFiles(ctx) {
+			r.logf("Warning: skipping remaining attachments, note already has %d file(s)", len(attached.FileIDs))
+			break
+		}
+		fileID, err := r.uploadFromURL(ctx, account, fileURL)
+		if err != nil {
+			r.logf("Warning: posting without attachment %s: %v", fileURL, err)
+			continue
+		}
+		attached.FileIDs = append(attached.FileIDs, fileID)
+	}
+	return &attached
+}
+
+func (r *noteRepository) uploadFromURL(ctx context.Context, account *postingAccount, fileURL string) (string, error) {
+	cacheKey := hashText(account.authToken) + ":" + fileURL
+	if fileID, ok := r.uploadedFiles.get(cacheKey); ok {
+		return fileID, nil
+	}
+
+	data, contentType, err := r.downloadAttachment(ctx, fileURL)
+	if err != nil {
+		return "", err
+	}
+
+	sum := md5.Sum(data)
+	fileID, err := r.findDriveFileByHash(ctx, account, hex.EncodeToString(sum[:]))
+	if err != nil {
+		r.logf("Warning: drive lookup by hash failed, uploading %s again: %v", fileURL, err)
+	}
+	if fileID == "" {
+		fileID, err = r.uploadFileReader(ctx, account.authToken, attachmentName(fileURL), bytes.NewReader(data), int64(len(data)), contentType)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	r.uploadedFiles.set(cacheKey, fileID)
+	return fileID, nil
+}
+
+func (r *noteRepository) findDriveFileByHash(ctx context.Context, account *postingAccount, md5Hash string) (string, error) {
+	var files []driveFileResponse
+	params := map[string]interface{}{"i": account.authToken, "md5": md5Hash}
+	if err := r.call(ctx, "drive/files/find-by-hash", params, &files); err != nil {
+		return "", fmt.Errorf("failed to find drive file by hash: %w", err)
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	return files[0].ID, nil
+}
+
+func (r *noteRepository) downloadAttachment(ctx context.Context, fileURL string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, attachmentDownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create attachment request: %w", err)
+	}
+	req.Header.Set("User-Agent", r.userAgent())
+
+	resp, err := r.attachmentHTTPClient().Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download attachment %s: %w", fileURL, classifyNetError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download attachment %s: status %d", fileURL, resp.StatusCode)
+	}
+
+	limit := r.maxAttachmentBytes
+	if limit <= 0 {
+		limit = defaultMaxAttachmentBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download attachment %s: %w", fileURL, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("%w: %s exceeds %d bytes", ErrFileTooLarge, fileURL, limit)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+func (r *noteRepository) attachmentHTTPClient() *http.Client {
+	if r.attachmentClient != nil {
+		return r.attachmentClient
+	}
+	return &http.Client{Timeout: attachmentDownloadTimeout}
+}
+
+func attachmentName(fileURL string) string {
+	parsed, err := url.Parse(fileURL)
+	if err != nil {
+		return defaultAttachmentName
+	}
+	name := path.Base(parsed.Path)
+	if name == "." || name == "/" || name == "" {
+		return defaultAttachmentName
+	}
+	return name
+}
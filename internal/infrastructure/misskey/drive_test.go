@@ -0,0 +1,160 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_Attachments(t *testing.T) {
+	tests := []struct {
+		name            string
+		attachmentPath  string
+		existingFileID  string
+		expectedFileIDs []string
+		expectedUploads int
+	}{
+		{"uploads new attachment", "/image.png", "", []string{"uploaded1"}, 1},
+		{"reuses file with same hash", "/image.png", "existing1", []string{"existing1"}, 0},
+		{"posts without failed attachment", "/missing.png", "", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := 0
+			var posted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/image.png":
+					w.Header().Set("Content-Type", "image/png")
+					w.Write([]byte("png-bytes"))
+				case "/api/drive/files/find-by-hash":
+					var payload map[string]interface{}
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					if payload["md5"] == "" {
+						t.Error("expected md5 in find-by-hash request")
+					}
+					if tt.existingFileID == "" {
+						w.Write([]byte(`[]`))
+						return
+					}
+					w.Write([]byte(`[{"id": "` + tt.existingFileID + `"}]`))
+				case "/api/drive/files/create":
+					uploads++
+					w.Write([]byte(`{"id": "uploaded1"}`))
+				case "/api/notes/create":
+					var payload struct {
+						FileIDs []string `json:"fileIds"`
+					}
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					posted = payload.FileIDs
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(10, 10*time.Second),
+			}
+
+			note := entity.NewNote("With image", entity.VisibilityHome)
+			note.AttachmentURLs = []string{server.URL + tt.attachmentPath}
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(posted, tt.expectedFileIDs) {
+				t.Errorf("expected fileIds %v, got %v", tt.expectedFileIDs, posted)
+			}
+			if uploads != tt.expectedUploads {
+				t.Errorf("expected %d uploads, got %d", tt.expectedUploads, uploads)
+			}
+			if len(note.FileIDs) != 0 {
+				t.Errorf("expected caller's note to be left unchanged, got %v", note.FileIDs)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_UploadFromURL_Cached(t *testing.T) {
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png":
+			downloads++
+			w.Write([]byte("png-bytes"))
+		case "/api/drive/files/find-by-hash":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"id": "uploaded1"}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+	}
+	drive := &driveRepository{notes: repo}
+
+	for i := 0; i < 2; i++ {
+		fileID, err := drive.UploadFromURL(context.Background(), server.URL+"/image.png")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fileID != "uploaded1" {
+			t.Errorf("expected uploaded1, got %s", fileID)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("expected 1 download, got %d", downloads)
+	}
+}
+
+func TestNoteRepository_DownloadAttachment_TooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{maxAttachmentBytes: 5}
+	if _, _, err := repo.downloadAttachment(context.Background(), server.URL); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestAttachmentName(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"file name", "https://example.com/images/photo.jpg?size=large", "photo.jpg"},
+		{"no path", "https://example.com", "attachment"},
+		{"trailing slash", "https://example.com/", "attachment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attachmentName(tt.url); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package misskey
+
+import (
+	"context"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (r *noteRepository) postDualVisibility(ctx context.Context, tokenIndex int, account *postingAccount, note *entity.Note, opts PostOptions) (*PostResult, error) {
+	result := &PostResult{TokenIndex: tokenIndex, AccountID: account.getUserID()}
+	errs := newMultiError("dual visibility post", 2)
+
+	local := *note
+	local.Visibility = entity.VisibilityPublic
+	localID, err := r.postNote(ctx, account, &local, noteRequest{priority: opts.Priority, localOnly: true, source: opts.Source})
+	if err != nil {
+		errs.Add("local public note", err)
+	} else {
+		result.NoteID = localID
+		r.scheduleDeletionAfter(localID, tokenIndex, opts.DeleteAfter)
+	}
+
+	federated := *note
+	federated.Visibility = entity.VisibilityHome
+	reservedAt := time.Now()
+	if err := account.reservePost(reservedAt, r.timeZone); err != nil {
+		errs.Add("federated home note", err)
+	} else if federatedID, err := r.postNote(ctx, account, &federated, noteRequest{priority: opts.Priority, source: opts.Source}); err != nil {
+		account.refundPost(reservedAt, r.timeZone)
+		errs.Add("federated home note", err)
+	} else {
+		result.FederatedNoteID = federatedID
+		r.scheduleDeletionAfter(federatedID, tokenIndex, opts.DeleteAfter)
+	}
+
+	if len(errs.Errors) == errs.Total {
+		return nil, errs
+	}
+	if err := errs.ErrOrNil(); err != nil {
+		r.logf("Dual visibility post partially succeeded [local: %q, federated: %q]: %v", result.NoteID, result.FederatedNoteID, err)
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_PostWithOptions_DualVisibility(t *testing.T) {
+	tests := []struct {
+		name            string
+		failLocal       bool
+		failFederated   bool
+		expectErr       bool
+		expectResult    bool
+		expectLocal     string
+		expectFederated string
+	}{
+		{"both succeed", false, false, false, true, "local1", "fed1"},
+		{"only federated succeeds", true, false, true, true, "", "fed1"},
+		{"only local succeeds", false, true, true, true, "local1", ""},
+		{"both fail", true, true, true, false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var payloads []map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				mu.Lock()
+				payloads = append(payloads, payload)
+				mu.Unlock()
+
+				local := payload["localOnly"] == true
+				if (local && tt.failLocal) || (!local && tt.failFederated) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error": {"code": "INVALID_PARAM"}}`))
+					return
+				}
+				if local {
+					w.Write([]byte(`{"createdNote": {"id": "local1"}}`))
+					return
+				}
+				w.Write([]byte(`{"createdNote": {"id": "fed1"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), entity.NewNote("Test", entity.VisibilityPublic), PostOptions{DualVisibility: true})
+			if tt.expectErr {
+				var multi *MultiError
+				if !errors.As(err, &multi) {
+					t.Errorf("expected MultiError, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (result != nil) != tt.expectResult {
+				t.Fatalf("expected result=%v, got %+v", tt.expectResult, result)
+			}
+			if result != nil && (result.NoteID != tt.expectLocal || result.FederatedNoteID != tt.expectFederated) {
+				t.Errorf("expected IDs (%q, %q), got (%q, %q)", tt.expectLocal, tt.expectFederated, result.NoteID, result.FederatedNoteID)
+			}
+
+			if len(payloads) != 2 {
+				t.Fatalf("expected 2 requests, got %d", len(payloads))
+			}
+			if payloads[0]["visibility"] != "public" || payloads[0]["localOnly"] != true {
+				t.Errorf("unexpected local payload: %v", payloads[0])
+			}
+			if payloads[1]["visibility"] != "home" || payloads[1]["localOnly"] != false {
+				t.Errorf("unexpected federated payload: %v", payloads[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const (
+	stateNamespaceEdits = "misskey.edits"
+	editFooterPrefix    = "edited: "
+)
+
+type EditRecord struct {
+	PreviousTextSHA256 string    `json:"previousTextSha256"`
+	EditedAt           time.Time `json:"editedAt"`
+	Reason             string    `json:"reason,omitempty"`
+}
+
+type editHistory struct {
+	mu      sync.Mutex
+	records map[string][]EditRecord
+}
+
+func (r *noteRepository) Update(ctx context.Context, noteID string, note *entity.Note, reason string) error {
+	ctx, done, err := r.ops.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	previous, err := r.GetNote(ctx, noteID)
+	if err != nil {
+		return err
+	}
+
+	edited := *note
+	if edited.Visibility == "" {
+		edited.Visibility = previous.Visibility
+	}
+	if r.editFooter && strings.TrimSpace(reason) != "" {
+		edited.Text = strings.TrimRight(edited.Text, "\n") + "\n\n" + editFooterPrefix + strings.TrimSpace(reason)
+	}
+
+	text, cw, err := r.prepareNote(ctx, &edited)
+	if err != nil {
+		return err
+	}
+
+	account := r.accountByUserID(ctx, previous.UserID)
+	params := map[string]interface{}{"i": account.authToken, "noteId": noteID, "text": text}
+	if cw != "" {
+		params["cw"] = cw
+	}
+	if err := r.callWithRetry(ctx, "notes/update", params, nil); err != nil {
+		return fmt.Errorf("failed to update note [%s]: %w", noteID, err)
+	}
+
+	r.audit(auditEntry{
+		Action:     "update",
+		NoteID:     noteID,
+		Visibility: string(edited.Visibility),
+		TextSHA256: hashText(text),
+	})
+	if r.trackEdits {
+		r.recordEdit(noteID, EditRecord{
+			PreviousTextSHA256: hashText(previous.Text),
+			EditedAt:           time.Now(),
+			Reason:             reason,
+		})
+	}
+	return nil
+}
+
+func (r *noteRepository) EditHistory(noteID string) []EditRecord {
+	r.edits.mu.Lock()
+	defer r.edits.mu.Unlock()
+
+	records := r.loadEditsLocked(noteID)
+	return append([]EditRecord(nil), records...)
+}
+
+func (r *noteRepository) recordEdit(noteID string, record EditRecord) {
+	r.edits.mu.Lock()
+	defer r.edits.mu.Unlock()
+
+	records := append(r.loadEditsLocked(noteID), record)
+	r.edits.records[noteID] = records
+	r.saveState(stateNamespaceEdits, noteID, records)
+}
+
+func (r *noteRepository) loadEditsLocked(noteID string) []EditRecord {
+	if r.edits.records == nil {
+		r.edits.records = make(map[string][]EditRecord)
+	}
+	if records, ok := r.edits.records[noteID]; ok || r.state == nil {
+		return records
+	}
+
+	data, ok, err := r.state.Get(context.Background(), stateNamespaceEdits, noteID)
+	if err != nil {
+		r.logf("Warning: failed to load edit history [%s]: %v", noteID, err)
+		return nil
+	}
+	var records []EditRecord
+	if ok {
+		if err := json.Unmarshal(data, &records); err != nil {
+			r.logf("Warning: skipping unreadable edit history [%s]: %v", noteID, err)
+			return nil
+		}
+	}
+	r.edits.records[noteID] = records
+	return records
+}
@@ -0,0 +1,171 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_Update(t *testing.T) {
+	tests := []struct {
+		name            string
+		editFooter      bool
+		reason          string
+		expectedText    string
+		trackEdits      bool
+		expectedHistory int
+	}{
+		{"plain update", false, "typo", "Fixed text", false, 0},
+		{"footer appended", true, "typo", "Fixed text\n\nedited: typo", true, 1},
+		{"footer skipped without reason", true, "", "Fixed text", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/show":
+					w.Write([]byte(`{"id": "note1", "text": "Fixd text", "visibility": "home"}`))
+				case "/api/notes/update":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &updated)
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(10, 10*time.Second),
+				editFooter:  tt.editFooter,
+				trackEdits:  tt.trackEdits,
+			}
+
+			if err := repo.Update(context.Background(), "note1", &entity.Note{Text: "Fixed text"}, tt.reason); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated["noteId"] != "note1" || updated["text"] != tt.expectedText {
+				t.Errorf("unexpected update payload: %v", updated)
+			}
+
+			history := repo.EditHistory("note1")
+			if len(history) != tt.expectedHistory {
+				t.Fatalf("expected %d edit record(s), got %d", tt.expectedHistory, len(history))
+			}
+			if len(history) > 0 {
+				if history[0].PreviousTextSHA256 != hashText("Fixd text") {
+					t.Errorf("expected hash of previous text, got %s", history[0].PreviousTextSHA256)
+				}
+				if history[0].Reason != tt.reason {
+					t.Errorf("expected reason %q, got %q", tt.reason, history[0].Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Update_UsesAuthorToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		author        string
+		expectedToken string
+	}{
+		{"primary account", "bot1", "token-a"},
+		{"extra account", "bot2", "token-b"},
+		{"unknown author falls back to primary", "someone", "token-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var token interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/show":
+					w.Write([]byte(`{"id": "note1", "text": "Fixd text", "visibility": "home", "userId": "` + tt.author + `"}`))
+				case "/api/notes/update":
+					var body map[string]interface{}
+					json.NewDecoder(r.Body).Decode(&body)
+					token = body["i"]
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			limiter := newRateLimiter(10, 10*time.Second)
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "token-a",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: limiter,
+				accounts: []*postingAccount{
+					{authToken: "token-a", rateLimiter: limiter, userID: "bot1"},
+					{authToken: "token-b", rateLimiter: newRateLimiter(10, 10*time.Second), userID: "bot2"},
+				},
+			}
+
+			if err := repo.Update(context.Background(), "note1", &entity.Note{Text: "Fixed text"}, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if token != tt.expectedToken {
+				t.Errorf("expected update with %s, got %v", tt.expectedToken, token)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_EditHistoryPersists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/notes/show" {
+			w.Write([]byte(`{"id": "note1", "text": "Old", "visibility": "home"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	newRepo := func() *noteRepository {
+		store, err := storage.NewFileStateStore(path)
+		if err != nil {
+			t.Fatalf("failed to open state store: %v", err)
+		}
+		return &noteRepository{
+			host:        server.URL,
+			authToken:   "test-token",
+			client:      &http.Client{Timeout: 30 * time.Second},
+			rateLimiter: newRateLimiter(10, 10*time.Second),
+			state:       store,
+			trackEdits:  true,
+		}
+	}
+
+	first := newRepo()
+	for _, reason := range []string{"first", "second"} {
+		if err := first.Update(context.Background(), "note1", &entity.Note{Text: "New"}, reason); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	history := newRepo().EditHistory("note1")
+	if len(history) != 2 {
+		t.Fatalf("expected 2 persisted edit records, got %d", len(history))
+	}
+	if history[0].Reason != "first" || history[1].Reason != "second" {
+		t.Errorf("unexpected edit order: %+v", history)
+	}
+}
@@ -0,0 +1,126 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type emojiShortcode struct {
+	start int
+	end   int
+	name  string
+}
+
+func (r *noteRepository) fetchEmojiNames(ctx context.Context) (map[string]struct{}, error) {
+	if names, ok := r.cache.emojis.get(time.Now()); ok {
+		return names, nil
+	}
+
+	var resp struct {
+		Emojis []struct {
+			Name string `json:"name"`
+		} `json:"emojis"`
+	}
+	if err := r.call(ctx, "emojis", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch custom emojis: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(resp.Emojis))
+	for _, emoji := range resp.Emojis {
+		names[emoji.Name] = struct{}{}
+	}
+
+	r.cache.emojis.set(names, time.Now())
+	return names, nil
+}
+
+func (r *noteRepository) validateEmojis(ctx context.Context, text string) string {
+	if !r.validateEmojiShortcodes {
+		return text
+	}
+
+	shortcodes := findEmojiShortcodes(text)
+	if len(shortcodes) == 0 {
+		return text
+	}
+
+	names, err := r.fetchEmojiNames(ctx)
+	if err != nil {
+		r.logf("Failed to validate custom emojis, posting text unchanged: %v", err)
+		return text
+	}
+
+	var unknown []emojiShortcode
+	for _, sc := range shortcodes {
+		if _, ok := names[sc.name]; !ok {
+			unknown = append(unknown, sc)
+		}
+	}
+	if len(unknown) == 0 {
+		return text
+	}
+
+	unknownNames := make([]string, 0, len(unknown))
+	for _, sc := range unknown {
+		unknownNames = append(unknownNames, sc.name)
+	}
+
+	if !r.stripUnknownEmojis {
+		r.logf("Warning: custom emojis not found on instance: %s", strings.Join(unknownNames, ", "))
+		return text
+	}
+
+	r.logf("Stripping custom emojis not found on instance: %s", strings.Join(unknownNames, ", "))
+	return stripShortcodes(text, unknown)
+}
+
+func findEmojiShortcodes(text string) []emojiShortcode {
+	var shortcodes []emojiShortcode
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != ':' || (i > 0 && isASCIIAlnum(text[i-1])) {
+			continue
+		}
+
+		j := i + 1
+		for j < len(text) && isShortcodeChar(text[j]) {
+			j++
+		}
+
+		if j == i+1 || j >= len(text) || text[j] != ':' {
+			continue
+		}
+		if j+1 < len(text) && isASCIIAlnum(text[j+1]) {
+			continue
+		}
+
+		shortcodes = append(shortcodes, emojiShortcode{start: i, end: j + 1, name: text[i+1 : j]})
+		i = j
+	}
+
+	return shortcodes
+}
+
+func stripShortcodes(text string, shortcodes []emojiShortcode) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	prev := 0
+	for _, sc := range shortcodes {
+		b.WriteString(text[prev:sc.start])
+		prev = sc.end
+	}
+	b.WriteString(text[prev:])
+
+	return b.String()
+}
+
+func isASCIIAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isShortcodeChar(c byte) bool {
+	return isASCIIAlnum(c) || c == '_' || c == '+' || c == '-'
+}
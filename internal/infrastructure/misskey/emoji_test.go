@@ -0,0 +1,98 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestFindEmojiShortcodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"single shortcode", "hello :wave:", []string{"wave"}},
+		{"multiple shortcodes", ":a: and :b_c:", []string{"a", "b_c"}},
+		{"time is not a shortcode", "at 12:30:45", nil},
+		{"empty colon pair", "::", nil},
+		{"unicode emoji ignored", "👋 hello", nil},
+		{"adjacent to word", "foo:bar:baz", nil},
+		{"url is not a shortcode", "https://example.tld", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortcodes := findEmojiShortcodes(tt.text)
+			if len(shortcodes) != len(tt.expected) {
+				t.Fatalf("expected %d shortcodes, got %d", len(tt.expected), len(shortcodes))
+			}
+			for i, sc := range shortcodes {
+				if sc.name != tt.expected[i] {
+					t.Errorf("shortcode[%d]: expected '%s', got '%s'", i, tt.expected[i], sc.name)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_ValidateEmojis(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate bool
+		strip    bool
+		text     string
+		expected string
+	}{
+		{"disabled keeps text", false, true, "hi :known: :unknown:", "hi :known: :unknown:"},
+		{"warn keeps text", true, false, "hi :known: :unknown:", "hi :known: :unknown:"},
+		{"strip removes unknown", true, true, "hi :known: :unknown:", "hi :known: "},
+		{"unicode emoji kept", true, true, "hi 👋", "hi 👋"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedText string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/emojis":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"emojis":[{"name":"known","aliases":[]}]}`))
+				case "/api/notes/create":
+					var payload map[string]interface{}
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					receivedText, _ = payload["text"].(string)
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:                    server.URL,
+				authToken:               "test-token",
+				client:                  &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:             newRateLimiter(3, 10*time.Second),
+				cache:                   responseCache{emojis: ttlCache[map[string]struct{}]{ttl: time.Hour}},
+				validateEmojiShortcodes: tt.validate,
+				stripUnknownEmojis:      tt.strip,
+			}
+
+			if err := repo.Post(context.Background(), entity.NewNote(tt.text, entity.VisibilityHome)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if receivedText != tt.expected {
+				t.Errorf("expected text '%s', got '%s'", tt.expected, receivedText)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package misskey
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type EmptyNotePolicy string
+
+const (
+	EmptyNoteFail EmptyNotePolicy = "fail"
+	EmptyNoteSkip EmptyNotePolicy = "skip"
+)
+
+func (p EmptyNotePolicy) validate() error {
+	switch p {
+	case "", EmptyNoteFail, EmptyNoteSkip:
+		return nil
+	}
+	return fmt.Errorf("unknown empty note policy: %s", p)
+}
+
+func hasContent(note *entity.Note, text string) bool {
+	return strings.TrimSpace(text) != "" ||
+		len(note.FileIDs) > 0 ||
+		(note.Poll != nil && len(note.Poll.Choices) > 0) ||
+		note.RenoteID != ""
+}
+
+func (r *noteRepository) skipsEmptyNote(err error) bool {
+	if r.onEmptyNote != EmptyNoteSkip || !errors.Is(err, ErrEmptyNote) {
+		return false
+	}
+	r.logf("Warning: Skipping note without text, files, poll or renote")
+	return true
+}
+
+func pollPayload(poll *entity.Poll) map[string]interface{} {
+	payload := map[string]interface{}{
+		"choices":  poll.Choices,
+		"multiple": poll.Multiple,
+	}
+	if poll.ExpiresAt != nil {
+		payload["expiresAt"] = poll.ExpiresAt.UnixMilli()
+	}
+	return payload
+}
@@ -0,0 +1,75 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_EmptyNote(t *testing.T) {
+	expires := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		policy          EmptyNotePolicy
+		note            *entity.Note
+		expectErr       error
+		expectRequest   bool
+		expectedOutcome PostOutcome
+	}{
+		{"text", "", &entity.Note{Text: "hello", Visibility: entity.VisibilityHome}, nil, true, PostOutcomePosted},
+		{"files", "", &entity.Note{Visibility: entity.VisibilityHome, FileIDs: []string{"file1"}}, nil, true, PostOutcomePosted},
+		{"poll", "", &entity.Note{Visibility: entity.VisibilityHome, Poll: &entity.Poll{Choices: []string{"yes", "no"}, ExpiresAt: &expires}}, nil, true, PostOutcomePosted},
+		{"renote", "", &entity.Note{Visibility: entity.VisibilityHome, RenoteID: "note1"}, nil, true, PostOutcomePosted},
+		{"empty fails", "", &entity.Note{Text: "  \n", Visibility: entity.VisibilityHome}, ErrEmptyNote, false, ""},
+		{"poll without choices fails", EmptyNoteFail, &entity.Note{Visibility: entity.VisibilityHome, Poll: &entity.Poll{}}, ErrEmptyNote, false, ""},
+		{"empty skipped", EmptyNoteSkip, &entity.Note{Visibility: entity.VisibilityHome}, nil, false, PostOutcomeSkippedEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+				onEmptyNote: tt.policy,
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), tt.note, PostOptions{})
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if (payload != nil) != tt.expectRequest {
+				t.Fatalf("expected request=%v, got payload %v", tt.expectRequest, payload)
+			}
+			if tt.expectErr == nil && result.Outcome != tt.expectedOutcome {
+				t.Errorf("expected outcome %s, got %s", tt.expectedOutcome, result.Outcome)
+			}
+			if tt.note.Poll != nil && tt.expectRequest {
+				poll, _ := payload["poll"].(map[string]interface{})
+				if choices, _ := poll["choices"].([]interface{}); len(choices) != 2 {
+					t.Errorf("expected poll choices in payload, got %v", payload["poll"])
+				}
+				if poll["expiresAt"] != float64(expires.UnixMilli()) {
+					t.Errorf("expected expiresAt %d, got %v", expires.UnixMilli(), poll["expiresAt"])
+				}
+			}
+		})
+	}
+}
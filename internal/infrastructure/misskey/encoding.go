@@ -0,0 +1,43 @@
+package misskey
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type EncodingPolicy string
+
+const (
+	EncodingSanitize EncodingPolicy = "sanitize"
+	EncodingReject   EncodingPolicy = "reject"
+)
+
+func (p EncodingPolicy) validate() error {
+	switch p {
+	case "", EncodingSanitize, EncodingReject:
+		return nil
+	}
+	return fmt.Errorf("unknown encoding policy: %s", p)
+}
+
+func (r *noteRepository) sanitizeText(field, text string) (string, error) {
+	if !utf8.ValidString(text) {
+		if r.encodingPolicy == EncodingReject {
+			return "", fmt.Errorf("%w: %s", ErrInvalidEncoding, field)
+		}
+		text = strings.ToValidUTF8(text, string(utf8.RuneError))
+	}
+	return strings.Map(dropControlRune, text), nil
+}
+
+func dropControlRune(r rune) rune {
+	if r == '\n' || r == '\t' {
+		return r
+	}
+	if unicode.IsControl(r) {
+		return -1
+	}
+	return r
+}
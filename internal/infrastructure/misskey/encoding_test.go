@@ -0,0 +1,80 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_SanitizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   EncodingPolicy
+		text     string
+		expected string
+		err      error
+	}{
+		{"valid text unchanged", "", "hello\n\tworld", "hello\n\tworld", nil},
+		{"invalid bytes replaced", EncodingSanitize, "bad \xff\xfe byte", "bad � byte", nil},
+		{"invalid bytes rejected", EncodingReject, "bad \xff byte", "", ErrInvalidEncoding},
+		{"control characters stripped", "", "a\x00b\x07c\r\x7fd\u0085e", "abcde", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{encodingPolicy: tt.policy}
+			got, err := repo.sanitizeText("text", tt.text)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_InvalidEncoding(t *testing.T) {
+	var receivedPayload map[string]interface{}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	newRepo := func(policy EncodingPolicy) *noteRepository {
+		return &noteRepository{
+			host:           server.URL,
+			authToken:      "test-token",
+			client:         &http.Client{Timeout: 30 * time.Second},
+			rateLimiter:    newRateLimiter(3, 10*time.Second),
+			encodingPolicy: policy,
+		}
+	}
+	note := &entity.Note{Text: "caf\xe9", CW: "cw\x00", Visibility: entity.VisibilityHome}
+
+	if err := newRepo(EncodingReject).Post(context.Background(), note); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected rejected note not to be sent, got %d requests", requests)
+	}
+
+	if err := newRepo(EncodingSanitize).Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receivedPayload["text"] != "caf�" || receivedPayload["cw"] != "cw" {
+		t.Errorf("unexpected sanitized payload: text=%q cw=%q", receivedPayload["text"], receivedPayload["cw"])
+	}
+}
@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrAccountSuspended      = errors.New("misskey account is suspended")
+	ErrSchedulingUnsupported = errors.New("instance does not support scheduled notes")
+	ErrRetryBudgetExhausted  = errors.New("retry budget exhausted before context deadline")
+	ErrQuoteTargetNotFound   = errors.New("quote target note not found")
+	ErrHostNotAllowed        = errors.New("host is not in the allowed hosts list")
+	ErrBlockedContent        = errors.New("note matches a blocklist pattern")
+	ErrHourlyCapReached      = errors.New("hourly post cap reached")
+	ErrInvalidEncoding       = errors.New("note contains invalid UTF-8")
+	ErrFileNotFound          = errors.New("drive file not found")
+	ErrUnresolvableNote      = errors.New("note URL could not be resolved")
+	ErrStateStoreRequired    = errors.New("a state store is required")
+	ErrCWRequired            = errors.New("instance requires a CW on every note")
+	ErrTooManyFiles          = errors.New("too many files attached to note")
+	ErrUnexpectedRedirect    = errors.New("unexpected redirect from Misskey API")
+	ErrInvalidLang           = errors.New("invalid language tag")
+	ErrRepositoryClosed      = errors.New("repository is closed")
+	ErrOperationsAbandoned   = errors.New("operations abandoned at shutdown")
+	ErrShuttingDown          = errors.New("repository is shutting down")
+	ErrFileTooLarge          = errors.New("file exceeds the instance upload size limit")
+	ErrMissingScopes         = errors.New("token is missing required permissions")
+	ErrFederationConflict    = errors.New("note federation setting conflicts with post options")
+	ErrInvalidBatchReference = errors.New("invalid intra-batch note reference")
+	ErrBatchReferenceFailed  = errors.New("referenced batch note was not posted")
+	ErrInvalidVisibility     = errors.New("invalid note visibility")
+	ErrTextTooLong           = errors.New("note text exceeds the maximum length")
+	ErrInvalidTemplate       = errors.New("invalid note template")
+	ErrDNSFailure            = errors.New("could not resolve Misskey host")
+	ErrTLSFailure            = errors.New("TLS handshake with Misskey host failed")
+	ErrConnRefused           = errors.New("connection to Misskey host refused")
+	ErrSPKIPinMismatch       = errors.New("server certificate does not match any pinned SPKI hash")
+	ErrEmptyNote             = errors.New("note has no text, files, poll or renote")
+	ErrInstanceMaintenance   = errors.New("instance announced maintenance, posting is paused")
+	ErrNoteTooLargeBytes     = errors.New("note text exceeds byte limit")
+	ErrInvalidPoll           = errors.New("invalid poll")
+	ErrDailyNoteCapReached   = errors.New("daily note cap reached")
+	ErrUnresolvableMention   = errors.New("mentioned user could not be resolved")
+	ErrInvalidRateLimit      = errors.New("invalid rate limit")
+)
+
+const (
+	errCodeNoSuchNote        = "NO_SUCH_NOTE"
+	errCodeNoSuchReplyTarget = "NO_SUCH_REPLY_TARGET"
+	errCodeAccountSuspended  = "YOUR_ACCOUNT_SUSPENDED"
+	errCodeAccountFrozen     = "YOUR_ACCOUNT_FROZEN"
+	errCodeUnknownEndpoint   = "UNKNOWN_API_ENDPOINT"
+	errCodePinLimitExceeded  = "PIN_LIMIT_EXCEEDED"
+	errCodeAlreadyPinned     = "ALREADY_PINNED"
+	errCodeAlreadyReacted    = "ALREADY_REACTED"
+	errCodeNoSuchFile        = "NO_SUCH_FILE"
+	errCodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
+	errCodeInternalError     = "INTERNAL_ERROR"
+	errCodeInvalidParam      = "INVALID_PARAM"
+	errCodePermissionDenied  = "PERMISSION_DENIED"
+	errCodeUnavailable       = "UNAVAILABLE"
+)
+
+func isAccountSuspendedCode(code string) bool {
+	return code == errCodeAccountSuspended || code == errCodeAccountFrozen
+}
+
+func isNoSuchNote(err error) bool {
+	return hasErrorCode(err, errCodeNoSuchNote)
+}
+
+// isNoSuchReplyTarget reports whether notes/create failed because replyId
+// points at a deleted note.
+func isNoSuchReplyTarget(err error) bool {
+	return hasErrorCode(err, errCodeNoSuchReplyTarget) || isNoSuchNote(err)
+}
+
+func hasErrorCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func isUnsupportedEndpoint(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound || apiErr.Code == errCodeUnknownEndpoint
+}
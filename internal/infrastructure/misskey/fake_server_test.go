@@ -0,0 +1,58 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/misskey/misskeytest"
+)
+
+func TestNoteRepository_FakeServer(t *testing.T) {
+	tests := []struct {
+		name             string
+		prepare          func(s *misskeytest.Server)
+		maxRetries       int
+		expectErr        bool
+		expectedRequests int
+	}{
+		{"posts note", func(s *misskeytest.Server) {}, 0, false, 1},
+		{"retries after rate limit", func(s *misskeytest.Server) { s.RateLimitNext("notes/create", 0) }, 1, false, 2},
+		{"does not resend after internal error", func(s *misskeytest.Server) {
+			s.FailNext("notes/create", http.StatusBadRequest, "INTERNAL_ERROR")
+		}, 1, true, 1},
+		{"gives up on permanent error", func(s *misskeytest.Server) {
+			s.FailNext("notes/create", http.StatusBadRequest, "NO_SUCH_USER")
+		}, 3, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := misskeytest.NewServer()
+			defer server.Close()
+			tt.prepare(server)
+
+			repo := newRetryTestRepository(server.URL, tt.maxRetries, time.Millisecond)
+			result, err := repo.PostWithOptions(context.Background(), entity.NewNote("Hello", entity.VisibilityHome), PostOptions{})
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := server.Requests("notes/create"); got != tt.expectedRequests {
+				t.Errorf("expected %d notes/create requests, got %d", tt.expectedRequests, got)
+			}
+			if !tt.expectErr {
+				if result.NoteID == "" {
+					t.Error("expected created note ID")
+				}
+				if created := server.Created(); len(created) != 1 || created[0]["text"] != "Hello" {
+					t.Errorf("expected one recorded note, got %+v", created)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package misskey
+
+import (
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (r *noteRepository) resolveLocalOnly(note *entity.Note) bool {
+	if note.Federate != nil {
+		return !*note.Federate
+	}
+	return r.localOnly
+}
+
+func validateFederation(note *entity.Note, opts PostOptions) error {
+	if note.Federate != nil && opts.DualVisibility {
+		return fmt.Errorf("%w: dual visibility posts both a local-only and a federated note", ErrFederationConflict)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_Federate(t *testing.T) {
+	federate := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		name            string
+		repoLocalOnly   bool
+		federate        *bool
+		visibility      entity.NoteVisibility
+		expectLocalOnly bool
+	}{
+		{"default federates", false, nil, entity.VisibilityPublic, false},
+		{"default follows LocalOnly", true, nil, entity.VisibilityPublic, true},
+		{"public not federated", false, federate(false), entity.VisibilityPublic, true},
+		{"home not federated", false, federate(false), entity.VisibilityHome, true},
+		{"federate overrides LocalOnly", true, federate(true), entity.VisibilityHome, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+				localOnly:   tt.repoLocalOnly,
+			}
+
+			note := entity.NewNote("Federation test", tt.visibility)
+			note.Federate = tt.federate
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if payload["localOnly"] != tt.expectLocalOnly {
+				t.Errorf("expected localOnly=%v, got %v", tt.expectLocalOnly, payload["localOnly"])
+			}
+			if payload["visibility"] != string(tt.visibility) {
+				t.Errorf("expected visibility %s, got %v", tt.visibility, payload["visibility"])
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_FederateConflictsWithDualVisibility(t *testing.T) {
+	repo := &noteRepository{rateLimiter: newRateLimiter(3, 10*time.Second)}
+
+	note := entity.NewNote("Federation test", entity.VisibilityPublic)
+	note.SetFederate(false)
+	_, err := repo.PostWithOptions(context.Background(), note, PostOptions{DualVisibility: true})
+	if !errors.Is(err, ErrFederationConflict) {
+		t.Errorf("expected ErrFederationConflict, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package misskey
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+func (r *noteRepository) checkHostAllowed(rawURL string) error {
+	target, err := hostnameOf(rawURL)
+	if err != nil {
+		return err
+	}
+
+	allowed := r.allowedHosts
+	if len(allowed) == 0 {
+		configured, err := hostnameOf(r.baseURL())
+		if err != nil {
+			return err
+		}
+		allowed = []string{configured}
+	}
+
+	if !hostMatches(target, allowed) {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, target)
+	}
+	return nil
+}
+
+func hostnameOf(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
+
+func hostMatches(host string, allowed []string) bool {
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimPrefix(entry, "."))
+		if entry == "" {
+			continue
+		}
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
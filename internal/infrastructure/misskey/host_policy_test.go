@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		allowed  []string
+		expected bool
+	}{
+		{"exact match", "example.tld", []string{"example.tld"}, true},
+		{"case insensitive", "example.tld", []string{"Example.TLD"}, true},
+		{"subdomain suffix", "misskey.example.tld", []string{"example.tld"}, true},
+		{"leading dot suffix", "misskey.example.tld", []string{".example.tld"}, true},
+		{"partial label rejected", "badexample.tld", []string{"example.tld"}, false},
+		{"other host rejected", "evil.tld", []string{"example.tld"}, false},
+		{"empty entry ignored", "example.tld", []string{""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostMatches(tt.host, tt.allowed); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_CheckHostAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		allowed   []string
+		target    string
+		expectErr bool
+	}{
+		{"configured host only by default", "example.tld", nil, "https://example.tld/api/i", false},
+		{"other host rejected by default", "example.tld", nil, "https://evil.tld/api/i", true},
+		{"allow list permits other host", "example.tld", []string{"example.tld", "other.tld"}, "https://other.tld/api/i", false},
+		{"allow list rejects configured host", "evil.tld", []string{"example.tld"}, "https://evil.tld/api/i", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{host: tt.host, allowedHosts: tt.allowed}
+			err := repo.checkHostAllowed(tt.target)
+			if tt.expectErr && !errors.Is(err, ErrHostNotAllowed) {
+				t.Errorf("expected ErrHostNotAllowed, got %v", err)
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_HostNotAllowed(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:         server.URL,
+		authToken:    "test-token",
+		client:       &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:  newRateLimiter(3, 10*time.Second),
+		allowedHosts: []string{"example.tld"},
+	}
+
+	err := repo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityHome))
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("expected ErrHostNotAllowed, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests to disallowed host, got %d", requests)
+	}
+}
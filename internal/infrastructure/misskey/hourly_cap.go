@@ -0,0 +1,49 @@
+package misskey
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type hourlyCap struct {
+	mu    sync.Mutex
+	limit int
+	posts []time.Time
+}
+
+func (c *hourlyCap) reserve(now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.limit <= 0 {
+		return nil
+	}
+
+	cutoff := now.Add(-time.Hour)
+	kept := c.posts[:0]
+	for _, t := range c.posts {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	c.posts = kept
+
+	if len(c.posts) >= c.limit {
+		return fmt.Errorf("%w: %d posts in the last hour, next slot at %s", ErrHourlyCapReached, len(c.posts), c.posts[0].Add(time.Hour).Format(time.RFC3339))
+	}
+	c.posts = append(c.posts, now)
+	return nil
+}
+
+func (c *hourlyCap) refund(reservedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(c.posts) - 1; i >= 0; i-- {
+		if c.posts[i].Equal(reservedAt) {
+			c.posts = append(c.posts[:i], c.posts[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestHourlyCap_Reserve(t *testing.T) {
+	now := time.Now()
+	window := hourlyCap{limit: 2}
+
+	if err := window.reserve(now.Add(-90 * time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := window.reserve(now.Add(-10 * time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := window.reserve(now); err != nil {
+		t.Fatalf("expected expired entry to free a slot, got %v", err)
+	}
+	if err := window.reserve(now); !errors.Is(err, ErrHourlyCapReached) {
+		t.Errorf("expected ErrHourlyCapReached, got %v", err)
+	}
+}
+
+func TestNoteRepository_Post_Priority(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:            server.URL,
+		authToken:       "test-token",
+		client:          &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:     newRateLimiter(1, time.Hour),
+		maxPostsPerHour: 3,
+	}
+	note := entity.NewNote("Breaking news", entity.VisibilityHome)
+
+	if err := repo.Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := repo.PostWithOptions(ctx, note, PostOptions{}); err == nil {
+		t.Fatal("expected routine post to wait on the exhausted rate limiter")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := repo.PostWithOptions(context.Background(), note, PostOptions{Priority: true}); err != nil {
+			t.Fatalf("expected priority post to bypass the rate limiter and reuse the refunded slot, got %v", err)
+		}
+	}
+
+	if _, err := repo.PostWithOptions(context.Background(), note, PostOptions{Priority: true}); !errors.Is(err, ErrHourlyCapReached) {
+		t.Errorf("expected priority post to respect the hourly cap, got %v", err)
+	}
+}
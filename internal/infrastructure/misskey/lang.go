@@ -0,0 +1,33 @@
+package misskey
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func validateLang(lang string) error {
+	if lang == "" {
+		return nil
+	}
+	if _, err := language.Parse(lang); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidLang, lang, err)
+	}
+	return nil
+}
+
+func (r *noteRepository) includeLang(note *entity.Note, req noteRequest) bool {
+	return note.Lang != "" && !req.omitLang && !r.langUnsupported.Load()
+}
+
+func (r *noteRepository) shouldRetryWithoutLang(note *entity.Note, req noteRequest, err error) bool {
+	return r.includeLang(note, req) && hasErrorCode(err, errCodeInvalidParam)
+}
+
+func (r *noteRepository) markLangUnsupported() {
+	if r.langUnsupported.CompareAndSwap(false, true) {
+		r.logf("Instance rejected the lang field on notes/create, omitting it from further posts")
+	}
+}
@@ -0,0 +1,114 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestValidateLang(t *testing.T) {
+	tests := []struct {
+		name      string
+		lang      string
+		expectErr bool
+	}{
+		{"empty", "", false},
+		{"language", "ja", false},
+		{"language and region", "en-US", false},
+		{"script subtag", "zh-Hant-TW", false},
+		{"digits", "123", true},
+		{"spaces", "not a tag", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLang(tt.lang)
+			if tt.expectErr && !errors.Is(err, ErrInvalidLang) {
+				t.Errorf("expected ErrInvalidLang, got %v", err)
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_Lang(t *testing.T) {
+	tests := []struct {
+		name              string
+		supported         bool
+		expectedLang      interface{}
+		expectedAttempts  int
+		expectUnsupported bool
+	}{
+		{"supported instance", true, "ja", 1, false},
+		{"unsupported instance", false, nil, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			var receivedLang interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				if _, ok := payload["lang"]; ok && !tt.supported {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error": {"code": "INVALID_PARAM"}}`))
+					return
+				}
+				receivedLang = payload["lang"]
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(10, 10*time.Second),
+			}
+
+			note := &entity.Note{Text: "こんにちは", Visibility: entity.VisibilityHome, Lang: "ja"}
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if receivedLang != tt.expectedLang {
+				t.Errorf("expected lang %v, got %v", tt.expectedLang, receivedLang)
+			}
+			if attempts != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, attempts)
+			}
+			if repo.langUnsupported.Load() != tt.expectUnsupported {
+				t.Errorf("expected langUnsupported=%v, got %v", tt.expectUnsupported, repo.langUnsupported.Load())
+			}
+
+			attempts = 0
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error on second post: %v", err)
+			}
+			if attempts != 1 {
+				t.Errorf("expected detected support to be reused, got %d attempts", attempts)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_InvalidLang(t *testing.T) {
+	repo := &noteRepository{rateLimiter: newRateLimiter(1, time.Second)}
+
+	err := repo.Post(context.Background(), &entity.Note{Text: "Hello", Visibility: entity.VisibilityHome, Lang: "not a tag"})
+	if !errors.Is(err, ErrInvalidLang) {
+		t.Errorf("expected ErrInvalidLang, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type linkSpan struct {
+	start int
+	end   int
+}
+
+func (r *noteRepository) transformLinks(text string) string {
+	if r.linkTransform == nil {
+		return text
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, span := range findLinks(text) {
+		b.WriteString(text[prev:span.start])
+		b.WriteString(r.linkTransform(text[span.start:span.end]))
+		prev = span.end
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
+
+func findLinks(text string) []linkSpan {
+	var spans []linkSpan
+
+	for i := 0; i < len(text); i++ {
+		if i > 0 && isASCIIAlnum(text[i-1]) {
+			continue
+		}
+		rest := text[i:]
+		if !strings.HasPrefix(rest, "https://") && !strings.HasPrefix(rest, "http://") {
+			continue
+		}
+
+		end := i
+		for end < len(text) {
+			c, size := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsSpace(c) || strings.ContainsRune(`<>"'「」『』（）【】`, c) {
+				break
+			}
+			end += size
+		}
+		end = i + len(trimLinkTrailing(text[i:end]))
+
+		if u, err := url.Parse(text[i:end]); err != nil || u.Host == "" {
+			i = end
+			continue
+		}
+		spans = append(spans, linkSpan{start: i, end: end})
+		i = end - 1
+	}
+
+	return spans
+}
+
+func trimLinkTrailing(link string) string {
+	for link != "" {
+		last := link[len(link)-1]
+		if strings.IndexByte(".,;:!?", last) >= 0 {
+			link = link[:len(link)-1]
+			continue
+		}
+		if last == ')' && strings.Count(link, "(") < strings.Count(link, ")") {
+			link = link[:len(link)-1]
+			continue
+		}
+		break
+	}
+	return link
+}
+
+func (r *noteRepository) checkTransformedLength(ctx context.Context, text string) error {
+	if r.linkTransform == nil && r.timeFormat == "" {
+		return nil
+	}
+	if length, limit := utf8.RuneCountInString(text), r.instanceTextLimit(ctx); length > limit {
+		return fmt.Errorf("%w: %d characters after rendering, limit is %d", ErrTextTooLong, length, limit)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func stripTrackingParams(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	q := u.Query()
+	for key := range q {
+		if strings.HasPrefix(key, "utm_") {
+			q.Del(key)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func TestNoteRepository_TransformLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain url", "read https://example.tld/a?utm_source=rss&id=1 now", "read https://example.tld/a?id=1 now"},
+		{"trailing punctuation kept", "see https://example.tld/a?utm_medium=x.", "see https://example.tld/a."},
+		{"markdown link", "[記事](https://example.tld/a?utm_source=rss)", "[記事](https://example.tld/a)"},
+		{"balanced parens", "https://example.tld/wiki/Go_(language)", "https://example.tld/wiki/Go_(language)"},
+		{"japanese brackets", "「https://example.tld/a?utm_source=rss」", "「https://example.tld/a」"},
+		{"multiple urls", "http://a.tld/?utm_x=1 and https://b.tld/?utm_y=2", "http://a.tld/ and https://b.tld/"},
+		{"non urls untouched", "example.tld/?utm_source=rss https:// xhttps://c.tld/?utm_z=1", "example.tld/?utm_source=rss https:// xhttps://c.tld/?utm_z=1"},
+		{"no urls", "ただのテキスト", "ただのテキスト"},
+	}
+
+	repo := &noteRepository{linkTransform: stripTrackingParams}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repo.transformLinks(tt.input); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_LinkTransform(t *testing.T) {
+	tests := []struct {
+		name          string
+		transform     func(string) string
+		maxTextLength int
+		text          string
+		expectedText  string
+		expectErr     error
+	}{
+		{"no hook", nil, 20, "https://example.tld/a?utm_source=rss", "https://example.tld/a?utm_source=rss", nil},
+		{"shortened fits limit", func(string) string { return "https://s.tld/1" }, 20, "https://example.tld/a?utm_source=rss", "https://s.tld/1", nil},
+		{"expanded exceeds limit", func(string) string { return "https://long.example.tld/redirect" }, 20, "https://s.tld/1", "", ErrTextTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:          server.URL,
+				authToken:     "test-token",
+				client:        &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:   newRateLimiter(3, 10*time.Second),
+				maxTextLength: tt.maxTextLength,
+				linkTransform: tt.transform,
+			}
+
+			err := repo.Post(context.Background(), entity.NewNote(tt.text, entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr != nil {
+				return
+			}
+			if payload["text"] != tt.expectedText {
+				t.Errorf("expected text '%s', got '%v'", tt.expectedText, payload["text"])
+			}
+		})
+	}
+}
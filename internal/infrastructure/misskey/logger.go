@@ -0,0 +1,14 @@
+package misskey
+
+import "log"
+
+func loggerOrDefault(logger *log.Logger) *log.Logger {
+	if logger == nil {
+		return log.Default()
+	}
+	return logger
+}
+
+func (r *noteRepository) logf(format string, args ...interface{}) {
+	loggerOrDefault(r.logger).Printf(format, args...)
+}
@@ -0,0 +1,118 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const defaultMaxFilesPerNote = 16
+
+type FileOverflowPolicy string
+
+const (
+	FileOverflowFail   FileOverflowPolicy = "fail"
+	FileOverflowThread FileOverflowPolicy = "thread"
+)
+
+func (p FileOverflowPolicy) validate() error {
+	switch p {
+	case "", FileOverflowFail, FileOverflowThread:
+		return nil
+	}
+	return fmt.Errorf("unknown file overflow policy: %s", p)
+}
+
+// maxFiles prefers the configured limit, then the limit the instance
+// advertises in meta. Stock Misskey advertises none and caps notes/create at
+// 16 files.
+func (r *noteRepository) maxFiles(ctx context.Context) int {
+	if r.maxFilesPerNote > 0 {
+		return r.maxFilesPerNote
+	}
+	meta, err := r.fetchMeta(ctx)
+	if err != nil {
+		r.logf("Warning: Could not fetch instance file limit, assuming %d: %v", defaultMaxFilesPerNote, err)
+		return defaultMaxFilesPerNote
+	}
+	if meta.MaxNoteFiles > 0 {
+		return meta.MaxNoteFiles
+	}
+	return defaultMaxFilesPerNote
+}
+
+func (r *noteRepository) checkFileCount(ctx context.Context, note *entity.Note) error {
+	if len(note.FileIDs) == 0 {
+		return nil
+	}
+	if limit := r.maxFiles(ctx); len(note.FileIDs) > limit {
+		return fmt.Errorf("%w: %d files attached, limit is %d", ErrTooManyFiles, len(note.FileIDs), limit)
+	}
+	return nil
+}
+
+func (r *noteRepository) splitFiles(ctx context.Context, note *entity.Note) (*entity.Note, [][]string) {
+	if r.onFileOverflow != FileOverflowThread || len(note.FileIDs) == 0 {
+		return note, nil
+	}
+	limit := r.maxFiles(ctx)
+	if len(note.FileIDs) <= limit {
+		return note, nil
+	}
+
+	first := *note
+	first.FileIDs = note.FileIDs[:limit]
+
+	var overflow [][]string
+	for rest := note.FileIDs[limit:]; len(rest) > 0; {
+		n := min(limit, len(rest))
+		overflow = append(overflow, rest[:n])
+		rest = rest[n:]
+	}
+	return &first, overflow
+}
+
+// postFileOverflow replies to the first note with the remaining attachments,
+// following the same depth limit and failure mode as PostThread. It reports
+// whether the first note was rolled back.
+func (r *noteRepository) postFileOverflow(ctx context.Context, account *postingAccount, parentID string, note *entity.Note, overflow [][]string, req noteRequest) (bool, error) {
+	total := len(overflow) + 1
+	thread := &ThreadResult{ChainBrokenAt: -1}
+	thread.add(parentID, account.authToken)
+	errs := newMultiError("failed to post overflow files", len(overflow))
+
+	var chain threadChain
+	chain.posted(parentID)
+	for i, fileIDs := range overflow {
+		reply, broken := r.chainReply(&chain, entity.Note{
+			Text:       fmt.Sprintf("(%d/%d)", i+2, total),
+			Visibility: note.Visibility,
+			FileIDs:    fileIDs,
+		})
+		if broken && thread.ChainBrokenAt < 0 {
+			thread.ChainBrokenAt = i + 1
+			r.logf("Thread reached maximum depth %d, posting remaining %d attachment note(s) as top-level notes", r.maxThreadDepth, len(overflow)-i)
+		}
+
+		noteID, err := r.postNote(ctx, account, &reply, req)
+		if err != nil {
+			err = fmt.Errorf("failed to post overflow files %d/%d: %w", i+2, total, err)
+			switch r.threadFailureMode {
+			case ThreadContinueNewChain:
+				r.logf("Warning: %v, continuing the remaining parts as a new chain", err)
+				errs.Add(fmt.Sprintf("part %d", i+2), err)
+				chain = threadChain{}
+				continue
+			case ThreadRollback:
+				r.rollbackThread(ctx, thread)
+				return len(thread.DeletedIDs) == len(thread.NoteIDs), err
+			}
+			return false, err
+		}
+		thread.add(noteID, account.authToken)
+		chain.posted(noteID)
+	}
+	r.logf("Split %d attachments across %d notes", len(note.FileIDs), total)
+	return false, errs.ErrOrNil()
+}
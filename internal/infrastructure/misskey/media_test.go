@@ -0,0 +1,223 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_FileLimit(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           int
+		maxFiles        int
+		instanceFiles   int
+		policy          FileOverflowPolicy
+		expectErr       error
+		expectedUploads []int
+	}{
+		{"within default limit", 16, 0, 0, "", nil, []int{16}},
+		{"over default limit", 17, 0, 0, "", ErrTooManyFiles, nil},
+		{"within instance limit", 20, 0, 20, "", nil, []int{20}},
+		{"over instance limit", 5, 0, 4, FileOverflowFail, ErrTooManyFiles, nil},
+		{"configured limit overrides instance", 3, 2, 20, FileOverflowFail, ErrTooManyFiles, nil},
+		{"over configured limit", 3, 2, 0, FileOverflowFail, ErrTooManyFiles, nil},
+		{"split into thread", 5, 2, 0, FileOverflowThread, nil, []int{2, 2, 1}},
+		{"split at instance limit", 5, 0, 4, FileOverflowThread, nil, []int{4, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploads []int
+			var replyIDs []interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/meta") {
+					fmt.Fprintf(w, `{"maxNoteFiles": %d}`, tt.instanceFiles)
+					return
+				}
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				files, _ := payload["fileIds"].([]interface{})
+				uploads = append(uploads, len(files))
+				replyIDs = append(replyIDs, payload["replyId"])
+				fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(uploads))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:            server.URL,
+				authToken:       "test-token",
+				client:          &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:     newRateLimiter(10, 10*time.Second),
+				maxFilesPerNote: tt.maxFiles,
+				onFileOverflow:  tt.policy,
+			}
+
+			note := entity.NewNote("Gallery", entity.VisibilityHome)
+			for i := 0; i < tt.files; i++ {
+				note.FileIDs = append(note.FileIDs, fmt.Sprintf("file%d", i))
+			}
+
+			err := repo.Post(context.Background(), note)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if len(uploads) != len(tt.expectedUploads) {
+				t.Fatalf("expected %d notes, got %d", len(tt.expectedUploads), len(uploads))
+			}
+			for i, n := range tt.expectedUploads {
+				if uploads[i] != n {
+					t.Errorf("note %d: expected %d files, got %d", i, n, uploads[i])
+				}
+				if i > 0 && replyIDs[i] != fmt.Sprintf("note%d", i) {
+					t.Errorf("note %d: expected reply to note%d, got %v", i, i, replyIDs[i])
+				}
+			}
+			if len(note.FileIDs) != tt.files {
+				t.Errorf("expected caller's note to keep %d files, got %d", tt.files, len(note.FileIDs))
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_FileOverflowThread(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           int
+		maxDepth        int
+		failAt          int
+		mode            ThreadFailureMode
+		expectedReplies []interface{}
+		expectErr       bool
+		expectedDeletes int
+	}{
+		{"depth limit", 5, 2, 0, "", []interface{}{nil, "note1", "note2", nil, nil}, false, 0},
+		{"stop on failure", 4, 0, 3, ThreadStopPartial, []interface{}{nil, "note1", "note2"}, true, 0},
+		{"continue as new chain", 4, 0, 3, ThreadContinueNewChain, []interface{}{nil, "note1", "note2", nil}, true, 0},
+		{"rollback", 4, 0, 3, ThreadRollback, []interface{}{nil, "note1", "note2"}, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replies []interface{}
+			deletes := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+				if r.URL.Path == "/api/notes/delete" {
+					deletes++
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				replies = append(replies, payload["replyId"])
+				if len(replies) == tt.failAt {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":{"code":"CONTAINS_PROHIBITED_WORDS"}}`))
+					return
+				}
+				fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(replies))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:              server.URL,
+				authToken:         "test-token",
+				client:            &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:       newRateLimiter(10, 10*time.Second),
+				maxFilesPerNote:   1,
+				onFileOverflow:    FileOverflowThread,
+				maxThreadDepth:    tt.maxDepth,
+				threadFailureMode: tt.mode,
+			}
+
+			note := entity.NewNote("Gallery", entity.VisibilityHome)
+			for i := 0; i < tt.files; i++ {
+				note.FileIDs = append(note.FileIDs, fmt.Sprintf("file%d", i))
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), note, PostOptions{})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if tt.mode == ThreadRollback && result != nil {
+				t.Errorf("expected no result after rollback, got %+v", result)
+			}
+			if len(replies) != len(tt.expectedReplies) {
+				t.Fatalf("expected %d notes, got replies %v", len(tt.expectedReplies), replies)
+			}
+			for i, reply := range tt.expectedReplies {
+				if replies[i] != reply {
+					t.Errorf("note %d: expected replyId %v, got %v", i+1, reply, replies[i])
+				}
+			}
+			if deletes != tt.expectedDeletes {
+				t.Errorf("expected %d deletions, got %d", tt.expectedDeletes, deletes)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_MediaOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		note      *entity.Note
+		expectErr error
+	}{
+		{"image only", &entity.Note{Visibility: entity.VisibilityHome, FileIDs: []string{"file1"}}, nil},
+		{"image only reply", &entity.Note{Visibility: entity.VisibilityHome, FileIDs: []string{"file1"}, ReplyID: "parent1"}, nil},
+		{"whitespace text with image", &entity.Note{Text: " \n ", Visibility: entity.VisibilityHome, FileIDs: []string{"file1"}}, nil},
+		{"nothing to post", &entity.Note{Text: " ", Visibility: entity.VisibilityHome}, ErrEmptyNote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			err := repo.Post(context.Background(), tt.note)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr != nil {
+				if payload != nil {
+					t.Error("expected no request for an empty note")
+				}
+				return
+			}
+
+			if _, ok := payload["text"]; ok {
+				t.Errorf("expected text to be omitted, got %q", payload["text"])
+			}
+			files, _ := payload["fileIds"].([]interface{})
+			if len(files) != 1 || files[0] != "file1" {
+				t.Errorf("expected fileIds [file1], got %v", payload["fileIds"])
+			}
+			if tt.note.ReplyID != "" && payload["replyId"] != tt.note.ReplyID {
+				t.Errorf("expected replyId %s, got %v", tt.note.ReplyID, payload["replyId"])
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type MentionPosition string
+
+const (
+	MentionPrefix MentionPosition = "prefix"
+	MentionSuffix MentionPosition = "suffix"
+)
+
+func (p MentionPosition) validate() error {
+	switch p {
+	case "", MentionPrefix, MentionSuffix:
+		return nil
+	}
+	return fmt.Errorf("unknown mention position: %s", p)
+}
+
+type userShowResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Host     string `json:"host"`
+}
+
+func splitAcct(handle string) (string, string) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	username, host, _ := strings.Cut(handle, "@")
+	return username, host
+}
+
+func (r *noteRepository) resolveMention(ctx context.Context, handle string) (string, error) {
+	username, host := splitAcct(handle)
+	if username == "" {
+		return "", fmt.Errorf("%w: %q", ErrUnresolvableMention, handle)
+	}
+	key := strings.ToLower(username + "@" + host)
+	if acct, ok := r.resolvedUsers.get(key); ok {
+		return acct, nil
+	}
+
+	params := map[string]interface{}{"username": username}
+	if host != "" {
+		params["host"] = host
+	}
+	var user userShowResponse
+	if err := r.call(ctx, "users/show", params, &user); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+			return "", fmt.Errorf("%w: %q: %w", ErrUnresolvableMention, handle, err)
+		}
+		return "", fmt.Errorf("failed to resolve mention %q: %w", handle, err)
+	}
+
+	acct := "@" + user.Username
+	if user.Host != "" {
+		acct += "@" + user.Host
+	}
+	r.resolvedUsers.set(key, acct)
+	return acct, nil
+}
+
+func (r *noteRepository) withMentions(ctx context.Context, note *entity.Note, text string) (string, error) {
+	if len(note.Mentions) == 0 {
+		return text, nil
+	}
+
+	var mentions []string
+	seen := make(map[string]bool, len(note.Mentions))
+	for _, handle := range note.Mentions {
+		acct, err := r.resolveMention(ctx, handle)
+		if err != nil {
+			return "", err
+		}
+		if !seen[strings.ToLower(acct)] {
+			seen[strings.ToLower(acct)] = true
+			mentions = append(mentions, acct)
+		}
+	}
+
+	if r.noExtractMentions {
+		text = plainTextMentions(text)
+	}
+	line := strings.Join(mentions, " ")
+	if strings.TrimSpace(text) == "" {
+		return line, nil
+	}
+	if r.mentionPosition == MentionSuffix {
+		return text + "\n" + line, nil
+	}
+	return line + " " + text, nil
+}
+
+func isMentionChar(c byte) bool {
+	return isASCIIAlnum(c) || c == '_' || c == '-'
+}
+
+func findMentions(text string) []linkSpan {
+	var spans []linkSpan
+	links := findLinks(text)
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' || (i > 0 && (isMentionChar(text[i-1]) || text[i-1] == '@')) {
+			continue
+		}
+		if inSpans(links, i) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(text) && isMentionChar(text[end]) {
+			end++
+		}
+		if end == i+1 {
+			continue
+		}
+		if end+1 < len(text) && text[end] == '@' && isMentionChar(text[end+1]) {
+			end++
+			for end < len(text) && (isMentionChar(text[end]) || text[end] == '.') {
+				end++
+			}
+			end = i + len(strings.TrimRight(text[i:end], "."))
+		}
+		spans = append(spans, linkSpan{start: i, end: end})
+		i = end - 1
+	}
+	return spans
+}
+
+func inSpans(spans []linkSpan, i int) bool {
+	for _, span := range spans {
+		if i >= span.start && i < span.end {
+			return true
+		}
+	}
+	return false
+}
+
+func plainTextMentions(text string) string {
+	var b strings.Builder
+	prev := 0
+	for _, span := range findMentions(text) {
+		b.WriteString(text[prev:span.start])
+		b.WriteString("<plain>" + text[span.start:span.end] + "</plain>")
+		prev = span.end
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestPlainTextMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"local handle", "thanks @alice!", "thanks <plain>@alice</plain>!"},
+		{"remote handle", "via @bob@remote.tld.", "via <plain>@bob@remote.tld</plain>."},
+		{"email untouched", "mail me at me@example.tld", "mail me at me@example.tld"},
+		{"handle inside url untouched", "see https://example.tld/@alice", "see https://example.tld/@alice"},
+		{"bare at sign", "meet @ noon", "meet @ noon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainTextMentions(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_Mentions(t *testing.T) {
+	tests := []struct {
+		name              string
+		text              string
+		mentions          []string
+		position          MentionPosition
+		noExtract         bool
+		expectedText      string
+		expectNoExtract   bool
+		expectErr         error
+		expectedUserShows int
+	}{
+		{"no mentions", "Hello @carol", nil, "", false, "Hello @carol", false, nil, 0},
+		{"no extraction without explicit mentions", "Hello @carol", nil, "", true, "Hello @carol", true, nil, 0},
+		{"prefixed mentions", "New post", []string{"alice", "@Bob@Remote.tld"}, "", false, "@alice @bob@remote.tld New post", false, nil, 2},
+		{"suffixed and deduplicated", "New post", []string{"@alice", "alice"}, MentionSuffix, false, "New post\n@alice", false, nil, 1},
+		{"incidental handles neutralized", "thanks @carol", []string{"alice"}, "", true, "@alice thanks <plain>@carol</plain>", false, nil, 1},
+		{"unknown user", "New post", []string{"ghost"}, "", false, "", false, ErrUnresolvableMention, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			userShows := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				if strings.HasSuffix(r.URL.Path, "/users/show") {
+					userShows++
+					if body["username"] == "ghost" {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error":{"code":"NO_SUCH_USER"}}`))
+						return
+					}
+					host, _ := body["host"].(string)
+					json.NewEncoder(w).Encode(map[string]interface{}{"id": "u1", "username": strings.ToLower(body["username"].(string)), "host": strings.ToLower(host)})
+					return
+				}
+				payload = body
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:              server.URL,
+				authToken:         "test-token",
+				client:            &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:       newRateLimiter(10, 10*time.Second),
+				noExtractMentions: tt.noExtract,
+				mentionPosition:   tt.position,
+			}
+
+			note := entity.NewNote(tt.text, entity.VisibilityHome)
+			note.Mentions = tt.mentions
+			for i := 0; i < 2; i++ {
+				err := repo.Post(context.Background(), note)
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+				}
+			}
+			if userShows != tt.expectedUserShows {
+				t.Errorf("expected %d user lookups across two posts, got %d", tt.expectedUserShows, userShows)
+			}
+			if tt.expectErr != nil {
+				return
+			}
+			if payload["text"] != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, payload["text"])
+			}
+			if _, ok := payload["noExtractMentions"]; ok != tt.expectNoExtract {
+				t.Errorf("expected noExtractMentions=%v, got payload %v", tt.expectNoExtract, payload)
+			}
+		})
+	}
+}
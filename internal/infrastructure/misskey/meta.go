@@ -0,0 +1,52 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type instanceMeta struct {
+	Federation        string `json:"federation"`
+	MaxNoteTextLength int    `json:"maxNoteTextLength"`
+	MaxNoteFiles      int    `json:"maxNoteFiles"`
+}
+
+func (m instanceMeta) isFederationRestricted() bool {
+	return m.Federation != "" && m.Federation != "all"
+}
+
+func (r *noteRepository) fetchMeta(ctx context.Context) (instanceMeta, error) {
+	if meta, ok := r.cache.meta.get(time.Now()); ok {
+		return meta, nil
+	}
+
+	var meta instanceMeta
+	if err := r.call(ctx, "meta", map[string]interface{}{"detail": true}, &meta); err != nil {
+		return instanceMeta{}, fmt.Errorf("failed to fetch instance meta: %w", err)
+	}
+
+	r.cache.meta.set(meta, time.Now())
+	return meta, nil
+}
+
+func (r *noteRepository) resolveVisibility(ctx context.Context, visibility entity.NoteVisibility) entity.NoteVisibility {
+	if !r.autoDowngradeVisibility || visibility != entity.VisibilityPublic {
+		return visibility
+	}
+
+	meta, err := r.fetchMeta(ctx)
+	if err != nil {
+		r.logf("Failed to check federation policy, keeping visibility %s: %v", visibility, err)
+		return visibility
+	}
+
+	if !meta.isFederationRestricted() {
+		return visibility
+	}
+
+	r.logf("Instance federation is restricted (%s), downgrading visibility from %s to %s", meta.Federation, visibility, entity.VisibilityHome)
+	return entity.VisibilityHome
+}
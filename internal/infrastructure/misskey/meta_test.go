@@ -0,0 +1,114 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_AutoDowngradeVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		federation string
+		visibility entity.NoteVisibility
+		expected   string
+	}{
+		{"disabled keeps public", false, "none", entity.VisibilityPublic, "public"},
+		{"federation none downgrades", true, "none", entity.VisibilityPublic, "home"},
+		{"federation specified downgrades", true, "specified", entity.VisibilityPublic, "home"},
+		{"federation all keeps public", true, "all", entity.VisibilityPublic, "public"},
+		{"legacy meta keeps public", true, "", entity.VisibilityPublic, "public"},
+		{"followers untouched", true, "none", entity.VisibilityFollowers, "followers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedVis string
+			metaRequests := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/meta":
+					metaRequests++
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(map[string]string{"federation": tt.federation})
+				case "/api/notes/create":
+					var payload map[string]interface{}
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					receivedVis, _ = payload["visibility"].(string)
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				default:
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:                    server.URL,
+				authToken:               "test-token",
+				client:                  &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:             newRateLimiter(3, 10*time.Second),
+				cache:                   responseCache{meta: ttlCache[instanceMeta]{ttl: time.Hour}},
+				autoDowngradeVisibility: tt.enabled,
+			}
+
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				if err := repo.Post(ctx, entity.NewNote("Test", tt.visibility)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if receivedVis != tt.expected {
+				t.Errorf("expected visibility '%s', got '%s'", tt.expected, receivedVis)
+			}
+			if metaRequests > 1 {
+				t.Errorf("expected meta to be cached, got %d requests", metaRequests)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_AutoDowngradeVisibility_MetaError(t *testing.T) {
+	var receivedVis string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/meta" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var payload map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		receivedVis, _ = payload["visibility"].(string)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:                    server.URL,
+		authToken:               "test-token",
+		client:                  &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:             newRateLimiter(3, 10*time.Second),
+		cache:                   responseCache{meta: ttlCache[instanceMeta]{ttl: time.Hour}},
+		autoDowngradeVisibility: true,
+	}
+
+	if err := repo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityPublic)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedVis != "public" {
+		t.Errorf("expected visibility 'public' when meta is unavailable, got '%s'", receivedVis)
+	}
+}
@@ -0,0 +1,151 @@
+package misskey
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+type middlewareTransport struct {
+	base    http.RoundTripper
+	handler http.RoundTripper
+}
+
+func (t *middlewareTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.handler.RoundTrip(req)
+}
+
+// Chain wraps base with middlewares so that the first middleware sees each
+// request first and each response last.
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	handler := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return &middlewareTransport{base: base, handler: handler}
+}
+
+func withMiddlewares(client *http.Client, middlewares []Middleware) *http.Client {
+	if len(middlewares) == 0 {
+		return client
+	}
+	wrapped := *client
+	wrapped.Transport = Chain(client.Transport, middlewares...)
+	return &wrapped
+}
+
+func baseTransport(rt http.RoundTripper) http.RoundTripper {
+	if chain, ok := rt.(*middlewareTransport); ok {
+		return chain.base
+	}
+	return rt
+}
+
+// LoggingMiddleware logs every API request; it is opt-in via Config.Middlewares.
+func LoggingMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				log.Printf("%s %s failed after %v: %v", req.Method, req.URL.Path, time.Since(start), err)
+				return nil, err
+			}
+			log.Printf("%s %s returned %d in %v", req.Method, req.URL.Path, resp.StatusCode, time.Since(start))
+			return resp, nil
+		})
+	}
+}
+
+// RateLimitMiddleware limits every request through the client, on top of
+// the repository's per-account posting limiter.
+func RateLimitMiddleware(maxPermits int, refillInterval time.Duration) Middleware {
+	limiter := newRateLimiter(maxPermits, refillInterval)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, fmt.Errorf("rate limiter error: %w", err)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// RetryMiddleware retries 429s, 5xx responses and network errors of GET
+// requests and of the idempotent API endpoints listed, such as
+// "notes/show". Other requests, notes/create included, are only retried
+// when the server cannot have acted on them: a 429 or a failure before the
+// request was sent. The retries multiply with the repository's own, so do
+// not combine it with Config.MaxRetries.
+func RetryMiddleware(maxRetries int, backoff time.Duration, idempotentEndpoints ...string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			idempotent := isIdempotentRequest(req, idempotentEndpoints)
+			for attempt := 0; ; attempt++ {
+				resp, err := next.RoundTrip(req)
+				if attempt >= maxRetries || !shouldRetryTransport(resp, err, idempotent) || (req.Body != nil && req.GetBody == nil) {
+					return resp, err
+				}
+
+				delay := backoff << attempt
+				if resp != nil {
+					if wait, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+						delay = wait
+					}
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				if err := sleepContext(req.Context(), delay); err != nil {
+					return nil, err
+				}
+
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, fmt.Errorf("failed to replay request body: %w", err)
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+			}
+		})
+	}
+}
+
+func isIdempotentRequest(req *http.Request, endpoints []string) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	return slices.Contains(endpoints, strings.TrimPrefix(req.URL.Path, "/api/"))
+}
+
+func shouldRetryTransport(resp *http.Response, err error, idempotent bool) bool {
+	if err != nil {
+		if !idempotent {
+			return isNotSent(err)
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	if !idempotent {
+		return resp.StatusCode == http.StatusTooManyRequests
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,159 @@
+package misskey
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next.RoundTrip(req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		})
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "base")
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	rt := Chain(base, recordingMiddleware("outer", &calls), recordingMiddleware("inner", &calls))
+	req, _ := http.NewRequest("POST", "http://example.tld/api/i", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"outer before", "inner before", "base", "inner after", "outer after"}
+	if strings.Join(calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		endpoint         string
+		failures         int32
+		status           int
+		maxRetries       int
+		expectedStatus   int
+		expectedAttempts int32
+	}{
+		{"succeeds after server errors", "notes/show", 2, http.StatusServiceUnavailable, 3, http.StatusOK, 3},
+		{"honours retry limit", "notes/create", 5, http.StatusTooManyRequests, 2, http.StatusTooManyRequests, 3},
+		{"client errors are not retried", "notes/show", 5, http.StatusBadRequest, 3, http.StatusBadRequest, 1},
+		{"server errors are not replayed for notes/create", "notes/create", 2, http.StatusBadGateway, 3, http.StatusBadGateway, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"i":"token"}` {
+					t.Errorf("expected body to be replayed, got %q", body)
+				}
+				if attempts.Add(1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: Chain(nil, RetryMiddleware(tt.maxRetries, time.Millisecond, "notes/show"))}
+			req, _ := http.NewRequest("POST", server.URL+"/api/"+tt.endpoint, strings.NewReader(`{"i":"token"}`))
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if attempts.Load() != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	rt := Chain(base, RateLimitMiddleware(1, time.Hour))
+
+	req, _ := http.NewRequest("POST", "http://example.tld/api/i", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := rt.RoundTrip(req.WithContext(ctx)); err == nil {
+		t.Error("expected second request to wait on the limiter and time out")
+	}
+}
+
+func TestNoteRepository_Middlewares(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace-ID") != "trace-1" {
+			t.Errorf("expected middleware header, got %q", r.Header.Get("X-Trace-ID"))
+		}
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	tracing := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Trace-ID", "trace-1")
+			return next.RoundTrip(req)
+		})
+	}
+
+	custom := &http.Client{Timeout: 30 * time.Second}
+	repo, err := NewNoteRepository(Config{
+		Host:        server.URL,
+		AuthToken:   "test-token",
+		HTTPClient:  custom,
+		Middlewares: []Middleware{tracing, LoggingMiddleware()},
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	if err := repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if custom.Transport != nil {
+		t.Error("expected the caller's HTTP client to be left unmodified")
+	}
+}
+
+func TestNoteRepository_ClientTLSConfig_WithMiddlewares(t *testing.T) {
+	client, err := newHTTPClient(TLSConfig{}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := &noteRepository{client: withMiddlewares(client, []Middleware{LoggingMiddleware()})}
+
+	if repo.clientTLSConfig() == nil {
+		t.Error("expected TLS config to be found beneath the middleware chain")
+	}
+}
@@ -0,0 +1,60 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type MissingFilePolicy string
+
+const (
+	MissingFileFail MissingFilePolicy = "fail"
+	MissingFileDrop MissingFilePolicy = "drop"
+)
+
+func (p MissingFilePolicy) validate() error {
+	switch p {
+	case "", MissingFileFail, MissingFileDrop:
+		return nil
+	}
+	return fmt.Errorf("unknown missing file policy: %s", p)
+}
+
+func (r *noteRepository) handleMissingFiles(ctx context.Context, account *postingAccount, note *entity.Note, req noteRequest, postErr error) (string, error) {
+	missing, err := r.findMissingFiles(ctx, account, note.FileIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to identify missing drive files: %w: %w", err, postErr)
+	}
+	if len(missing) == 0 {
+		return "", postErr
+	}
+
+	if r.onMissingFile != MissingFileDrop {
+		return "", fmt.Errorf("%w: %s: %w", ErrFileNotFound, strings.Join(missing, ", "), postErr)
+	}
+
+	retry := *note
+	retry.FileIDs = slices.DeleteFunc(slices.Clone(note.FileIDs), func(id string) bool {
+		return slices.Contains(missing, id)
+	})
+	r.logf("Dropping missing drive files [%s] and retrying post with %d file(s)", strings.Join(missing, ", "), len(retry.FileIDs))
+	return r.createNote(ctx, account, &retry, req)
+}
+
+func (r *noteRepository) findMissingFiles(ctx context.Context, account *postingAccount, fileIDs []string) ([]string, error) {
+	var missing []string
+	for _, id := range fileIDs {
+		params := map[string]interface{}{"i": account.authToken, "fileId": id}
+		err := r.call(ctx, "drive/files/show", params, nil)
+		if hasErrorCode(err, errCodeNoSuchFile) {
+			missing = append(missing, id)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
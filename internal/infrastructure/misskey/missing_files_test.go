@@ -0,0 +1,80 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_MissingFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      MissingFilePolicy
+		expectErr   error
+		expectFiles []interface{}
+	}{
+		{"fail by default", "", ErrFileNotFound, nil},
+		{"drop missing files", MissingFileDrop, nil, []interface{}{"file1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted []interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				switch r.URL.Path {
+				case "/api/notes/create":
+					files, _ := payload["fileIds"].([]interface{})
+					if slices.Contains(files, interface{}("gone")) {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error": {"code": "NO_SUCH_FILE"}}`))
+						return
+					}
+					posted = files
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				case "/api/drive/files/show":
+					if payload["fileId"] == "gone" {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error": {"code": "NO_SUCH_FILE"}}`))
+						return
+					}
+					w.Write([]byte(`{"id": "file1"}`))
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:          server.URL,
+				authToken:     "test-token",
+				client:        &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:   newRateLimiter(3, 10*time.Second),
+				onMissingFile: tt.policy,
+			}
+
+			note := entity.NewNote("With media", entity.VisibilityHome)
+			note.FileIDs = []string{"file1", "gone"}
+
+			err := repo.Post(context.Background(), note)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if !slices.Equal(posted, tt.expectFiles) {
+				t.Errorf("expected posted files %v, got %v", tt.expectFiles, posted)
+			}
+			if !slices.Equal(note.FileIDs, []string{"file1", "gone"}) {
+				t.Errorf("expected original note to be unchanged, got %v", note.FileIDs)
+			}
+		})
+	}
+}
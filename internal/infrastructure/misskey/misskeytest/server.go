@@ -0,0 +1,144 @@
+package misskeytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type failure struct {
+	status     int
+	code       string
+	retryAfter time.Duration
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	meta     map[string]interface{}
+	nextID   int
+	created  []map[string]interface{}
+	deleted  []string
+	requests map[string]int
+	failures map[string][]failure
+}
+
+func NewServer() *Server {
+	s := &Server{
+		meta:     map[string]interface{}{"federation": "all"},
+		requests: make(map[string]int),
+		failures: make(map[string][]failure),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *Server) SetMeta(meta map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.meta = meta
+}
+
+func (s *Server) FailNext(endpoint string, status int, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[endpoint] = append(s.failures[endpoint], failure{status: status, code: code})
+}
+
+func (s *Server) RateLimitNext(endpoint string, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[endpoint] = append(s.failures[endpoint], failure{
+		status:     http.StatusTooManyRequests,
+		code:       "RATE_LIMIT_EXCEEDED",
+		retryAfter: retryAfter,
+	})
+}
+
+func (s *Server) Created() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]map[string]interface{}(nil), s.created...)
+}
+
+func (s *Server) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.deleted...)
+}
+
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpoint]
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[endpoint]++
+	if queued := s.failures[endpoint]; len(queued) > 0 {
+		f := queued[0]
+		s.failures[endpoint] = queued[1:]
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Seconds())))
+		}
+		writeError(w, f.status, f.code)
+		return
+	}
+
+	switch endpoint {
+	case "notes/create":
+		s.nextID++
+		s.created = append(s.created, body)
+		writeJSON(w, map[string]interface{}{
+			"createdNote": map[string]interface{}{
+				"id":         fmt.Sprintf("note%d", s.nextID),
+				"text":       body["text"],
+				"visibility": body["visibility"],
+			},
+		})
+	case "notes/delete":
+		noteID, _ := body["noteId"].(string)
+		s.deleted = append(s.deleted, noteID)
+		w.WriteHeader(http.StatusNoContent)
+	case "meta":
+		writeJSON(w, s.meta)
+	default:
+		writeError(w, http.StatusNotFound, "UNKNOWN_API_ENDPOINT")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": http.StatusText(status)},
+	})
+}
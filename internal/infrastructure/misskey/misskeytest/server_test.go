@@ -0,0 +1,74 @@
+package misskeytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func post(t *testing.T, s *Server, endpoint string, body map[string]interface{}) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	payload, _ := json.Marshal(body)
+	resp, err := http.Post(s.URL+"/api/"+endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.RateLimitNext("notes/create", 2*time.Second)
+	s.FailNext("notes/create", http.StatusInternalServerError, "INTERNAL_ERROR")
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"rate limited", "notes/create", http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED"},
+		{"server error", "notes/create", http.StatusInternalServerError, "INTERNAL_ERROR"},
+		{"created", "notes/create", http.StatusOK, ""},
+		{"deleted", "notes/delete", http.StatusNoContent, ""},
+		{"meta", "meta", http.StatusOK, ""},
+		{"unknown endpoint", "i/unknown", http.StatusNotFound, "UNKNOWN_API_ENDPOINT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := post(t, s, tt.endpoint, map[string]interface{}{"i": "token", "text": "hello", "noteId": "note1"})
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedCode != "" {
+				errBody, _ := body["error"].(map[string]interface{})
+				if errBody["code"] != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, errBody["code"])
+				}
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests && resp.Header.Get("Retry-After") != "2" {
+				t.Errorf("expected Retry-After 2, got %q", resp.Header.Get("Retry-After"))
+			}
+		})
+	}
+
+	if created := s.Created(); len(created) != 1 || created[0]["text"] != "hello" {
+		t.Errorf("expected one recorded note, got %+v", created)
+	}
+	if deleted := s.Deleted(); len(deleted) != 1 || deleted[0] != "note1" {
+		t.Errorf("expected note1 to be recorded as deleted, got %v", deleted)
+	}
+	if got := s.Requests("notes/create"); got != 3 {
+		t.Errorf("expected 3 notes/create requests, got %d", got)
+	}
+}
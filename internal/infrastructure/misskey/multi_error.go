@@ -0,0 +1,56 @@
+package misskey
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ItemError struct {
+	Item string
+	Err  error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Item, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+type MultiError struct {
+	Op     string
+	Total  int
+	Errors []*ItemError
+}
+
+func newMultiError(op string, total int) *MultiError {
+	return &MultiError{Op: op, Total: total}
+}
+
+func (e *MultiError) Add(item string, err error) {
+	e.Errors = append(e.Errors, &ItemError{Item: item, Err: err})
+}
+
+func (e *MultiError) ErrOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *MultiError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		parts = append(parts, item.Error())
+	}
+	return fmt.Sprintf("%s: %d of %d failed: %s", e.Op, len(e.Errors), e.Total, strings.Join(parts, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		errs = append(errs, item)
+	}
+	return errs
+}
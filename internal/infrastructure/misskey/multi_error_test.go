@@ -0,0 +1,96 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiError_Unwrapping(t *testing.T) {
+	apiErr := &APIError{StatusCode: http.StatusBadRequest, Code: errCodeNoSuchNote}
+
+	errs := newMultiError("batch", 3)
+	errs.Add("first", ErrAccountSuspended)
+	errs.Add("second", apiErr)
+	err := errs.ErrOrNil()
+
+	tests := []struct {
+		name     string
+		target   error
+		expected bool
+	}{
+		{"sentinel", ErrAccountSuspended, true},
+		{"other sentinel", ErrHostNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(err, tt.target); got != tt.expected {
+				t.Errorf("expected errors.Is=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	var gotAPIErr *APIError
+	if !errors.As(err, &gotAPIErr) || gotAPIErr != apiErr {
+		t.Errorf("expected errors.As to find the API error, got %v", gotAPIErr)
+	}
+
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("expected errors.As to find the MultiError, got %v", err)
+	}
+	if multi.Errors[1].Item != "second" {
+		t.Errorf("expected second item error, got %s", multi.Errors[1].Item)
+	}
+
+	if msg := err.Error(); !strings.HasPrefix(msg, "batch: 2 of 3 failed: first: ") {
+		t.Errorf("unexpected error message: %s", msg)
+	}
+}
+
+func TestMultiError_ErrOrNil(t *testing.T) {
+	if err := newMultiError("batch", 2).ErrOrNil(); err != nil {
+		t.Errorf("expected nil error for empty MultiError, got %v", err)
+	}
+}
+
+func TestNoteRepository_DeleteMany(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+
+		if payload["noteId"] == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "NO_SUCH_NOTE"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	err := repo.DeleteMany(context.Background(), []string{"a", "missing", "b"})
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected MultiError, got %v", err)
+	}
+	if multi.Total != 3 || len(multi.Errors) != 1 || multi.Errors[0].Item != "missing" {
+		t.Errorf("unexpected MultiError: %+v", multi)
+	}
+	if !isNoSuchNote(err) {
+		t.Errorf("expected NO_SUCH_NOTE to be reachable through MultiError, got %v", err)
+	}
+}
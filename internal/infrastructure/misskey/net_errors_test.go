@@ -0,0 +1,80 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_NetworkErrorClassification(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer tlsServer.Close()
+
+	failingDialer := func(dialErr error) *http.Client {
+		return &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					return nil, dialErr
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		host      string
+		client    *http.Client
+		expectErr error
+	}{
+		{
+			"dns failure",
+			"https://misskey.invalid",
+			failingDialer(&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "misskey.invalid", IsNotFound: true}}),
+			ErrDNSFailure,
+		},
+		{
+			"connection refused",
+			"https://misskey.example",
+			failingDialer(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}),
+			ErrConnRefused,
+		},
+		{
+			"untrusted certificate",
+			tlsServer.URL,
+			&http.Client{Timeout: 5 * time.Second},
+			ErrTLSFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{host: tt.host, authToken: "test-token", client: tt.client}
+
+			err := repo.call(context.Background(), "i", nil, nil)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			for _, other := range []error{ErrDNSFailure, ErrTLSFailure, ErrConnRefused} {
+				if other != tt.expectErr && errors.Is(err, other) {
+					t.Errorf("expected only %v, also matched %v", tt.expectErr, other)
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyNetError_Unclassified(t *testing.T) {
+	err := errors.New("something else")
+	if got := classifyNetError(err); got != err {
+		t.Errorf("expected unclassified error to be returned unchanged, got %v", got)
+	}
+}
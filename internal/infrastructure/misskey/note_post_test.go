@@ -3,9 +3,11 @@ package misskey
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -213,3 +215,269 @@ func TestNoteRepository_Post_LocalOnlyTrue(t *testing.T) {
 		t.Errorf("expected localOnly to be true, got '%v'", receivedPayload["localOnly"])
 	}
 }
+
+func TestNoteRepository_Post_Channel(t *testing.T) {
+	tests := []struct {
+		name      string
+		channelID string
+		expected  interface{}
+	}{
+		{"channel note", "channel1", "channel1"},
+		{"no channel", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &receivedPayload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			note := entity.NewNote("Test note", entity.VisibilityPublic)
+			note.ChannelID = tt.channelID
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if receivedPayload["channelId"] != tt.expected {
+				t.Errorf("expected channelId %v, got %v", tt.expected, receivedPayload["channelId"])
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_ReactionAcceptance(t *testing.T) {
+	tests := []struct {
+		name       string
+		acceptance entity.ReactionAcceptance
+		expected   interface{}
+	}{
+		{"like only", entity.ReactionAcceptanceLikeOnly, "likeOnly"},
+		{"all reactions", entity.ReactionAcceptanceAll, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &receivedPayload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			note := entity.NewNote("Test note", entity.VisibilityPublic)
+			note.ReactionAcceptance = tt.acceptance
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if receivedPayload["reactionAcceptance"] != tt.expected {
+				t.Errorf("expected reactionAcceptance %v, got %v", tt.expected, receivedPayload["reactionAcceptance"])
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_ReplyFallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		fallback      ReplyFallback
+		expectErr     bool
+		expectedPosts int
+	}{
+		{"fail keeps error", ReplyFallbackFail, true, 1},
+		{"standalone retries without reply", ReplyFallbackStandalone, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payloads []map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				payloads = append(payloads, payload)
+
+				if _, ok := payload["replyId"]; ok {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":{"code":"NO_SUCH_REPLY_TARGET","message":"No such reply target.","id":"id"}}`))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:          server.URL,
+				authToken:     "test-token",
+				client:        &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:   newRateLimiter(3, 10*time.Second),
+				replyFallback: tt.fallback,
+			}
+
+			note := entity.NewNote("Reply", entity.VisibilityHome)
+			note.ReplyID = "deleted-note"
+
+			err := repo.Post(context.Background(), note)
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(payloads) != tt.expectedPosts {
+				t.Fatalf("expected %d requests, got %d", tt.expectedPosts, len(payloads))
+			}
+			if payloads[0]["replyId"] != "deleted-note" {
+				t.Errorf("expected first request to carry replyId, got '%v'", payloads[0]["replyId"])
+			}
+			if tt.expectedPosts > 1 {
+				if _, ok := payloads[1]["replyId"]; ok {
+					t.Error("expected fallback request without replyId")
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_AccountSuspended(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":"YOUR_ACCOUNT_SUSPENDED","message":"Your account has been suspended.","id":"a8c724b3-6e9c-4b46-b1a8-bc3ed6258370"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome))
+		if !errors.Is(err, ErrAccountSuspended) {
+			t.Fatalf("attempt %d: expected ErrAccountSuspended, got %v", i+1, err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected posting to stop after suspension, got %d requests", requests)
+	}
+}
+
+func TestNoteRepository_Post_AccountSuspendedPerAccount(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if r.URL.Path != "/api/notes/create" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		token, _ := payload["i"].(string)
+		tokens = append(tokens, token)
+		if token == "a" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":"YOUR_ACCOUNT_SUSPENDED","message":"Your account has been suspended."}}`))
+			return
+		}
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:   server.URL,
+		client: &http.Client{Timeout: 30 * time.Second},
+		accounts: []*postingAccount{
+			newPostingAccount("a", newRateLimiter(3, 10*time.Second), 0, 0),
+			newPostingAccount("b", newRateLimiter(3, 10*time.Second), 0, 0),
+		},
+	}
+
+	ctx := context.Background()
+	if err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome)); !errors.Is(err, ErrAccountSuspended) {
+		t.Fatalf("expected ErrAccountSuspended, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome)); err != nil {
+			t.Fatalf("attempt %d: expected the other account to keep posting, got %v", i+1, err)
+		}
+	}
+	if err := repo.Validate(ctx, entity.NewNote("Test", entity.VisibilityHome)); errors.Is(err, ErrAccountSuspended) {
+		t.Errorf("expected validation to pass while an account can post, got %v", err)
+	}
+
+	if strings.Join(tokens, ",") != "a,b,b" {
+		t.Errorf("expected requests from a,b,b, got %v", tokens)
+	}
+}
+
+func TestNoteRepository_Post_DefaultDeadline(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultDeadline time.Duration
+		callerTimeout   time.Duration
+	}{
+		{"default applied without caller deadline", 50 * time.Millisecond, 0},
+		{"caller deadline takes precedence", time.Hour, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer server.Close()
+			defer close(release)
+
+			repo := &noteRepository{
+				host:            server.URL,
+				authToken:       "test-token",
+				client:          &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:     newRateLimiter(3, 10*time.Second),
+				defaultDeadline: tt.defaultDeadline,
+			}
+
+			ctx := context.Background()
+			if tt.callerTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := repo.Post(ctx, entity.NewNote("Test note", entity.VisibilityPublic))
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected deadline exceeded, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("expected Post to return promptly, took %v", elapsed)
+			}
+		})
+	}
+}
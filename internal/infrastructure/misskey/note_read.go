@@ -0,0 +1,56 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type noteResponse struct {
+	ID             string   `json:"id"`
+	Text           string   `json:"text"`
+	CW             string   `json:"cw"`
+	Visibility     string   `json:"visibility"`
+	ReplyID        string   `json:"replyId"`
+	RenoteID       string   `json:"renoteId"`
+	UserID         string   `json:"userId"`
+	VisibleUserIDs []string `json:"visibleUserIds"`
+	User           struct {
+		Username string `json:"username"`
+		Host     string `json:"host"`
+	} `json:"user"`
+}
+
+func (n noteResponse) acct() string {
+	if n.User.Host == "" {
+		return n.User.Username
+	}
+	return n.User.Username + "@" + n.User.Host
+}
+
+func (n noteResponse) toEntity() *entity.Note {
+	return &entity.Note{
+		ID:             n.ID,
+		UserID:         n.UserID,
+		Username:       n.acct(),
+		Text:           n.Text,
+		CW:             n.CW,
+		Visibility:     entity.NoteVisibility(n.Visibility),
+		ReplyID:        n.ReplyID,
+		RenoteID:       n.RenoteID,
+		VisibleUserIDs: n.VisibleUserIDs,
+	}
+}
+
+func (r *noteRepository) GetNote(ctx context.Context, noteID string) (*entity.Note, error) {
+	var resp noteResponse
+	if err := r.call(ctx, "notes/show", map[string]interface{}{"noteId": noteID}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch note [%s]: %w", noteID, err)
+	}
+	return resp.toEntity(), nil
+}
+
+func (r *noteRepository) noteURL(noteID string) string {
+	return r.baseURL() + "/notes/" + noteID
+}
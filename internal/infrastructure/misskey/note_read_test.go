@@ -0,0 +1,59 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_GetNote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/notes/show" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"note1","text":"hello","visibility":"home","replyId":"parent1"}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	note, err := repo.GetNote(context.Background(), "note1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if note.ID != "note1" || note.Text != "hello" || note.Visibility != entity.VisibilityHome || note.ReplyID != "parent1" {
+		t.Errorf("unexpected note: %+v", note)
+	}
+}
+
+func TestNoteRepository_GetNote_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"NO_SUCH_NOTE","message":"No such note."}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	_, err := repo.GetNote(context.Background(), "missing")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "NO_SUCH_NOTE" {
+		t.Errorf("expected NO_SUCH_NOTE API error, got %v", err)
+	}
+}
@@ -1,13 +1,13 @@
 package misskey
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"misskeyRSSbot/internal/domain/entity"
@@ -20,6 +20,15 @@ type rateLimiter struct {
 	maxPermits int
 	refillRate time.Duration
 	lastRefill time.Time
+	now        func() time.Time
+	adaptive   *adaptiveRate
+	logger     *log.Logger
+	shutdown   chan struct{}
+	released   bool
+
+	waits        atomic.Uint64
+	blockedWaits atomic.Uint64
+	blockedNanos atomic.Int64
 }
 
 func newRateLimiter(maxPermits int, refillRate time.Duration) *rateLimiter {
@@ -31,11 +40,46 @@ func newRateLimiter(maxPermits int, refillRate time.Duration) *rateLimiter {
 	}
 }
 
+type RateLimitWaitError struct {
+	Remaining time.Duration
+	Err       error
+}
+
+func (e *RateLimitWaitError) Error() string {
+	return fmt.Sprintf("rate limit wait aborted with %v remaining: %v", e.Remaining, e.Err)
+}
+
+func (e *RateLimitWaitError) Unwrap() error {
+	return e.Err
+}
+
 func (rl *rateLimiter) Wait(ctx context.Context) error {
+	_, err := rl.WaitRemaining(ctx)
+	return err
+}
+
+func (rl *rateLimiter) clock() time.Time {
+	if rl.now != nil {
+		return rl.now()
+	}
+	return time.Now()
+}
+
+func (rl *rateLimiter) WaitRemaining(ctx context.Context) (time.Duration, error) {
 	rl.mu.Lock()
+	rl.waits.Add(1)
+	if rl.released {
+		rl.mu.Unlock()
+		return 0, ErrShuttingDown
+	}
+	shutdown := rl.shutdownLocked()
 
-	now := time.Now()
+	now := rl.clock()
 	elapsed := now.Sub(rl.lastRefill)
+	if elapsed < 0 {
+		rl.lastRefill = now
+		elapsed = 0
+	}
 	permitsToAdd := int(elapsed / rl.refillRate)
 	if permitsToAdd > 0 {
 		rl.permits = min(rl.permits+permitsToAdd, rl.maxPermits)
@@ -46,25 +90,57 @@ func (rl *rateLimiter) Wait(ctx context.Context) error {
 		waitTime := rl.refillRate - (now.Sub(rl.lastRefill) % rl.refillRate)
 		rl.mu.Unlock()
 
+		readyAt := now.Add(waitTime)
 		timer := time.NewTimer(waitTime)
 		defer timer.Stop()
+		rl.blockedWaits.Add(1)
+		defer rl.recordBlocked(time.Now())
 
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return max(readyAt.Sub(rl.clock()), 0), ctx.Err()
+		case <-shutdown:
+			return max(readyAt.Sub(rl.clock()), 0), ErrShuttingDown
 		case <-timer.C:
 			rl.mu.Lock()
 			rl.permits = 1
-			rl.lastRefill = time.Now()
+			rl.lastRefill = rl.clock()
 			rl.permits--
 			rl.mu.Unlock()
-			return nil
+			return 0, nil
 		}
 	}
 
 	rl.permits--
 	rl.mu.Unlock()
-	return nil
+	return 0, nil
+}
+
+func (rl *rateLimiter) shutdownLocked() chan struct{} {
+	if rl.shutdown == nil {
+		rl.shutdown = make(chan struct{})
+	}
+	return rl.shutdown
+}
+
+func (rl *rateLimiter) release() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if !rl.released {
+		rl.released = true
+		close(rl.shutdownLocked())
+	}
+}
+
+func (rl *rateLimiter) reconfigure(maxPermits int, refillRate time.Duration, permits int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.maxPermits = maxPermits
+	rl.refillRate = refillRate
+	rl.permits = min(permits, maxPermits)
+	rl.lastRefill = rl.clock()
 }
 
 func min(a, b int) int {
@@ -74,23 +150,210 @@ func min(a, b int) int {
 	return b
 }
 
+type ReplyFallback string
+
+const (
+	ReplyFallbackFail       ReplyFallback = "fail"
+	ReplyFallbackStandalone ReplyFallback = "standalone"
+)
+
 type noteRepository struct {
-	host        string
-	authToken   string
-	client      *http.Client
-	rateLimiter *rateLimiter
-	localOnly   bool
+	host          string
+	apiBasePath   string
+	authToken     string
+	client        *http.Client
+	rateLimiter   *rateLimiter
+	pollLimiter   *rateLimiter
+	searchLimiter *rateLimiter
+	localOnly     bool
+	replyFallback ReplyFallback
+	cache         responseCache
+
+	autoDowngradeVisibility bool
+	validateEmojiShortcodes bool
+	stripUnknownEmojis      bool
+	compressRequests        bool
+	maxRetries              int
+	retryableErrorCodes     []string
+	retryBackoff            time.Duration
+	backoffJitter           BackoffJitter
+	backoff                 Backoff
+	maxTextLength           int
+	allowedHosts            []string
+	blocklist               []blockPattern
+	streamChannels          []string
+	streamPingInterval      time.Duration
+	autoCW                  AutoCWConfig
+	requireCW               bool
+	maxFilesPerNote         int
+	onFileOverflow          FileOverflowPolicy
+	encodingPolicy          EncodingPolicy
+	onMissingFile           MissingFilePolicy
+	onEmptyNote             EmptyNotePolicy
+	autoConfigureRateLimit  bool
+	adaptive                bool
+	adaptiveMinInterval     time.Duration
+	adaptiveMaxInterval     time.Duration
+	encoder                 bodyEncoder
+	auditLog                *auditLog
+	appName                 string
+	state                   repository.StateStore
+	dedupe                  repository.DedupeStore
+	defaultDeadline         time.Duration
+	shutdownGrace           time.Duration
+	waitLimitersOnShutdown  bool
+	setBotFlag              bool
+	payloadAllowlist        []string
+	chainToSelf             bool
+	maxThreadDepth          int
+	threadFailureMode       ThreadFailureMode
+	trackEdits              bool
+	editFooter              bool
+	maxAttachmentBytes      int64
+	attachmentClient        *http.Client
+	serverDedupe            bool
+	serverDedupeWindow      time.Duration
+	checkAnnouncements      bool
+	announcementKeywords    []string
+	trimWhitespace          bool
+	quietHours              QuietHours
+	extraVisibilities       []entity.NoteVisibility
+	linkTransform           func(url string) string
+	inheritReplyRecipients  bool
+	timeFormat              string
+	timePosition            TimePosition
+	timeZone                *time.Location
+	maxNoteBytes            int
+	dailyCapErrorCodes      []string
+	noExtractMentions       bool
+	mentionPosition         MentionPosition
+	deadLetter              func(note *entity.Note, err error)
+	visibilityRules         map[string]entity.NoteVisibility
+	defaultVisibility       entity.NoteVisibility
+	onNoteTooLarge          NoteSizePolicy
+	maxPostsPerHour         int
+	maxNotesPerDay          int
+	logger                  *log.Logger
+
+	deletions     deletionScheduler
+	ops           opTracker
+	pins          pinState
+	mentions      mentionCursor
+	chain         selfChain
+	contentLocks  contentLocks
+	resolvedNotes noteResolutionCache
+	resolvedUsers noteResolutionCache
+	uploadedFiles noteResolutionCache
+	deadLetters   deadLetters
+	edits         editHistory
+	accounts      []*postingAccount
+	nextAccount   atomic.Uint64
+	fallbackOnce  sync.Once
+	fallback      *postingAccount
+
+	compressionUnsupported atomic.Bool
+	langUnsupported        atomic.Bool
+	searchUnsupported      atomic.Bool
 }
 
 type Config struct {
 	Host           string
+	APIBasePath    string
 	AuthToken      string
+	AuthTokens     []string
 	MaxPermits     int
 	RefillInterval time.Duration
+	ColdStart      bool
 	LocalOnly      bool
+	ReplyFallback  ReplyFallback
+	CacheTTL       CacheTTLs
+	Logger         *log.Logger
+
+	AutoDowngradeVisibility  bool
+	ValidateEmojis           bool
+	StripUnknownEmojis       bool
+	CompressRequests         bool
+	MaxRetries               int
+	RetryableErrorCodes      []string
+	RetryBackoff             time.Duration
+	BackoffJitter            BackoffJitter
+	Backoff                  Backoff
+	MaxTextLength            int
+	AllowedHosts             []string
+	TLS                      TLSConfig
+	HTTPClient               *http.Client
+	Redirects                RedirectPolicy
+	Blocklist                []string
+	NotificationPollInterval time.Duration
+	StreamChannels           []string
+	StreamPingInterval       time.Duration
+	AutoCW                   AutoCWConfig
+	RequireCW                bool
+	MaxFilesPerNote          int
+	OnFileOverflow           FileOverflowPolicy
+	MaxPostsPerHour          int
+	InvalidEncoding          EncodingPolicy
+	OnMissingFile            MissingFilePolicy
+	OnEmptyNote              EmptyNotePolicy
+	AutoConfigureRateLimit   bool
+	Encoding                 BodyEncoding
+	AuditLogPath             string
+	AppName                  string
+	StateStore               repository.StateStore
+	DedupeStore              repository.DedupeStore
+	DefaultDeadline          time.Duration
+	ShutdownGrace            time.Duration
+	WaitLimitersOnShutdown   bool
+	SetBotFlag               bool
+	PayloadAllowlist         []string
+	ChainToSelf              bool
+	MaxThreadDepth           int
+	ThreadFailureMode        ThreadFailureMode
+	TrackEdits               bool
+	EditFooter               bool
+	MaxAttachmentBytes       int64
+	AttachmentClient         *http.Client
+	ServerDedupe             bool
+	ServerDedupeWindow       time.Duration
+	CheckAnnouncements       bool
+	AnnouncementKeywords     []string
+	TrimWhitespace           bool
+	QuietHours               QuietHours
+	ExtraVisibilities        []entity.NoteVisibility
+	LinkTransform            func(url string) string
+	InheritReplyRecipients   bool
+	TimeFormat               string
+	TimePosition             TimePosition
+	TimeZone                 *time.Location
+	MaxNoteBytes             int
+	OnNoteTooLarge           NoteSizePolicy
+	Adaptive                 bool
+	AdaptiveMinInterval      time.Duration
+	AdaptiveMaxInterval      time.Duration
+	Middlewares              []Middleware
+	MaxNotesPerDay           int
+	DailyCapErrorCodes       []string
+	NoExtractMentions        bool
+	MentionPosition          MentionPosition
+	DeadLetter               func(note *entity.Note, err error)
+	VisibilityRules          map[string]entity.NoteVisibility
+	DefaultVisibility        entity.NoteVisibility
+}
+
+// NewNoteRepository returns a repository that is safe for concurrent use by
+// multiple goroutines. All caches, limiters, cursors and stats are guarded
+// internally, and notes passed to Post are never modified. Callers must not
+// modify a note while a post using it is in flight, and hooks such as
+// LinkTransform may be invoked concurrently.
+func NewNoteRepository(cfg Config) (repository.NoteRepository, error) {
+	r, err := newNoteRepository(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
-func NewNoteRepository(cfg Config) repository.NoteRepository {
+func newNoteRepository(cfg Config) (*noteRepository, error) {
 	maxPermits := cfg.MaxPermits
 	if maxPermits == 0 {
 		maxPermits = 3
@@ -99,55 +362,559 @@ func NewNoteRepository(cfg Config) repository.NoteRepository {
 	if refillInterval == 0 {
 		refillInterval = 10 * time.Second
 	}
+	replyFallback := cfg.ReplyFallback
+	if replyFallback == "" {
+		replyFallback = ReplyFallbackFail
+	}
+	retryBackoff := cfg.RetryBackoff
+	if retryBackoff == 0 {
+		retryBackoff = time.Second
+	}
+	pollInterval := cfg.NotificationPollInterval
+	if pollInterval == 0 {
+		pollInterval = 10 * time.Second
+	}
+	cacheTTL := cfg.CacheTTL.withDefaults()
+
+	if err := cfg.BackoffJitter.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.InvalidEncoding.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.OnMissingFile.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.OnEmptyNote.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ThreadFailureMode.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.TimePosition.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.OnNoteTooLarge.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.MentionPosition.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.OnFileOverflow.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.QuietHours.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateExtraVisibilities(cfg.ExtraVisibilities); err != nil {
+		return nil, err
+	}
+	encoder, err := newBodyEncoder(cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	apiBasePath, err := normalizeAPIBasePath(cfg.APIBasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Redirects.validate(); err != nil {
+		return nil, err
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client, err = newHTTPClient(cfg.TLS, cfg.Redirects)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+	} else if !cfg.TLS.isZero() || cfg.Redirects != "" {
+		return nil, fmt.Errorf("TLS and redirect settings cannot be combined with a custom HTTP client")
+	}
+	client = withMiddlewares(client, cfg.Middlewares)
 
-	return &noteRepository{
-		host:        cfg.Host,
-		authToken:   cfg.AuthToken,
-		client:      &http.Client{Timeout: 30 * time.Second},
-		rateLimiter: newRateLimiter(maxPermits, refillInterval),
-		localOnly:   cfg.LocalOnly,
+	blocklist, err := compileBlocklist(cfg.Blocklist)
+	if err != nil {
+		return nil, err
 	}
+
+	var adaptive *adaptiveRate
+	if cfg.Adaptive {
+		if adaptive, err = newAdaptiveRate(refillInterval, cfg.AdaptiveMinInterval, cfg.AdaptiveMaxInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	newAccount := func(token string) *postingAccount {
+		rl := newRateLimiter(maxPermits, refillInterval)
+		if cfg.ColdStart {
+			rl.permits = min(1, maxPermits)
+		}
+		rl.adaptive = adaptive
+		rl.logger = cfg.Logger
+		return newPostingAccount(token, rl, cfg.MaxPostsPerHour, cfg.MaxNotesPerDay)
+	}
+	accounts := newPostingAccounts(cfg.AuthToken, cfg.AuthTokens, newAccount)
+	primary := newAccount(cfg.AuthToken)
+	if len(accounts) > 0 {
+		primary = accounts[0]
+	}
+
+	r := &noteRepository{
+		host:          cfg.Host,
+		apiBasePath:   apiBasePath,
+		authToken:     primary.authToken,
+		client:        client,
+		rateLimiter:   primary.rateLimiter,
+		pollLimiter:   newRateLimiter(1, pollInterval),
+		searchLimiter: newRateLimiter(serverDedupeSearchBurst, serverDedupeSearchInterval),
+		accounts:      accounts,
+		localOnly:     cfg.LocalOnly,
+		replyFallback: replyFallback,
+		cache: responseCache{
+			meta:          ttlCache[instanceMeta]{ttl: cacheTTL.Meta},
+			emojis:        ttlCache[map[string]struct{}]{ttl: cacheTTL.Emojis},
+			accountStats:  ttlCache[accountStats]{ttl: cacheTTL.AccountStats},
+			announcements: ttlCache[[]announcement]{ttl: cacheTTL.Announcements},
+		},
+
+		autoDowngradeVisibility: cfg.AutoDowngradeVisibility,
+		validateEmojiShortcodes: cfg.ValidateEmojis,
+		stripUnknownEmojis:      cfg.StripUnknownEmojis,
+		compressRequests:        cfg.CompressRequests,
+		maxRetries:              cfg.MaxRetries,
+		retryableErrorCodes:     cfg.RetryableErrorCodes,
+		retryBackoff:            retryBackoff,
+		backoffJitter:           cfg.BackoffJitter,
+		backoff:                 cfg.Backoff,
+		maxTextLength:           cfg.MaxTextLength,
+		allowedHosts:            cfg.AllowedHosts,
+		blocklist:               blocklist,
+		streamChannels:          cfg.StreamChannels,
+		streamPingInterval:      cfg.StreamPingInterval,
+		autoCW:                  cfg.AutoCW,
+		requireCW:               cfg.RequireCW,
+		maxFilesPerNote:         cfg.MaxFilesPerNote,
+		onFileOverflow:          cfg.OnFileOverflow,
+		encodingPolicy:          cfg.InvalidEncoding,
+		onMissingFile:           cfg.OnMissingFile,
+		onEmptyNote:             cfg.OnEmptyNote,
+		autoConfigureRateLimit:  cfg.AutoConfigureRateLimit,
+		adaptive:                cfg.Adaptive,
+		adaptiveMinInterval:     cfg.AdaptiveMinInterval,
+		adaptiveMaxInterval:     cfg.AdaptiveMaxInterval,
+		encoder:                 encoder,
+		appName:                 cfg.AppName,
+		state:                   cfg.StateStore,
+		dedupe:                  cfg.DedupeStore,
+		defaultDeadline:         cfg.DefaultDeadline,
+		shutdownGrace:           cfg.ShutdownGrace,
+		waitLimitersOnShutdown:  cfg.WaitLimitersOnShutdown,
+		setBotFlag:              cfg.SetBotFlag,
+		payloadAllowlist:        cfg.PayloadAllowlist,
+		chainToSelf:             cfg.ChainToSelf,
+		maxThreadDepth:          cfg.MaxThreadDepth,
+		threadFailureMode:       cfg.ThreadFailureMode,
+		trackEdits:              cfg.TrackEdits,
+		editFooter:              cfg.EditFooter,
+		maxAttachmentBytes:      cfg.MaxAttachmentBytes,
+		attachmentClient:        cfg.AttachmentClient,
+		serverDedupe:            cfg.ServerDedupe,
+		serverDedupeWindow:      cfg.ServerDedupeWindow,
+		checkAnnouncements:      cfg.CheckAnnouncements,
+		announcementKeywords:    cfg.AnnouncementKeywords,
+		trimWhitespace:          cfg.TrimWhitespace,
+		quietHours:              cfg.QuietHours,
+		extraVisibilities:       cfg.ExtraVisibilities,
+		linkTransform:           cfg.LinkTransform,
+		inheritReplyRecipients:  cfg.InheritReplyRecipients,
+		timeFormat:              cfg.TimeFormat,
+		timePosition:            cfg.TimePosition,
+		timeZone:                cfg.TimeZone,
+		maxNoteBytes:            cfg.MaxNoteBytes,
+		dailyCapErrorCodes:      cfg.DailyCapErrorCodes,
+		noExtractMentions:       cfg.NoExtractMentions,
+		mentionPosition:         cfg.MentionPosition,
+		deadLetter:              cfg.DeadLetter,
+		visibilityRules:         normalizeVisibilityRules(cfg.VisibilityRules),
+		defaultVisibility:       cfg.DefaultVisibility,
+		onNoteTooLarge:          cfg.OnNoteTooLarge,
+		maxPostsPerHour:         cfg.MaxPostsPerHour,
+		maxNotesPerDay:          cfg.MaxNotesPerDay,
+		logger:                  cfg.Logger,
+	}
+	if err := r.checkHostAllowed(r.baseURL()); err != nil {
+		return nil, fmt.Errorf("invalid Misskey host: %w", err)
+	}
+	r.client = r.guardRedirects(r.client)
+	if cfg.TLS.InsecureSkipVerify {
+		r.logf("WARNING: TLS certificate verification is disabled for the Misskey API (InsecureSkipVerify)")
+	}
+	if err := r.validateVisibilityRules(); err != nil {
+		return nil, err
+	}
+	if cfg.AuditLogPath != "" {
+		auditLog, err := openAuditLog(cfg.AuditLogPath)
+		if err != nil {
+			return nil, err
+		}
+		r.auditLog = auditLog
+	}
+	if err := r.restoreState(context.Background()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type PostOptions struct {
+	DeleteAfter    time.Duration
+	Priority       bool
+	DualVisibility bool
+	FetchRendered  bool
+	Source         string
+	ReplyToURL     string
+	SeedReaction   string
+	RequireSeed    bool
+}
+
+type PostOutcome string
+
+const (
+	PostOutcomePosted            PostOutcome = "posted"
+	PostOutcomeSkippedUnchanged  PostOutcome = "skipped_unchanged"
+	PostOutcomeDeferred          PostOutcome = "deferred"
+	PostOutcomeSkippedQuietHours PostOutcome = "skipped_quiet_hours"
+	PostOutcomeSkippedEmpty      PostOutcome = "skipped_empty"
+	PostOutcomeSkippedDuplicate  PostOutcome = "skipped_duplicate"
+)
+
+type PostResult struct {
+	NoteID          string
+	FederatedNoteID string
+	TokenIndex      int
+	AccountID       string
+	Rendered        *RenderedNote
+	Outcome         PostOutcome
+	Seeded          bool
+}
+
+type noteRequest struct {
+	replyID   string
+	priority  bool
+	localOnly bool
+	source    string
+	omitLang  bool
+}
+
+type createNoteResponse struct {
+	CreatedNote struct {
+		ID string `json:"id"`
+	} `json:"createdNote"`
+}
+
+func (r *noteRepository) withDefaultDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || r.defaultDeadline <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.defaultDeadline)
 }
 
 func (r *noteRepository) Post(ctx context.Context, note *entity.Note) error {
-	if err := r.rateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limiter error: %w", err)
+	_, err := r.PostWithOptions(ctx, note, PostOptions{})
+	return err
+}
+
+func (r *noteRepository) PostWithOptions(ctx context.Context, note *entity.Note, opts PostOptions) (*PostResult, error) {
+	result, err := r.postWithOptions(ctx, note, opts)
+	if err != nil {
+		r.reportDeadLetter(ctx, note, err)
 	}
+	return result, err
+}
 
-	notePayload := map[string]interface{}{
-		"i":          r.authToken,
-		"text":       note.Text,
-		"visibility": string(note.Visibility),
-		"localOnly":  r.localOnly,
+func (r *noteRepository) postWithOptions(ctx context.Context, note *entity.Note, opts PostOptions) (*PostResult, error) {
+	ctx, done, err := r.ops.begin(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer done()
 
-	payload, err := json.Marshal(notePayload)
+	note = r.withCategoryVisibility(note)
+
+	ctx, cancel := r.withDefaultDeadline(ctx)
+	defer cancel()
+
+	if opts.ReplyToURL != "" {
+		replyID, err := r.ResolveNoteURL(ctx, opts.ReplyToURL)
+		if err != nil {
+			return nil, err
+		}
+		reply := *note
+		reply.ReplyID = replyID
+		note = &reply
+	}
+	outcome := PostOutcomePosted
+	if resumeAt, quiet := r.quietHours.until(time.Now()); quiet && note.ScheduledAt == nil {
+		if r.quietHours.Policy == QuietHoursDrop {
+			r.logf("Skipping post during quiet hours (active again at %v)", resumeAt)
+			return &PostResult{Outcome: PostOutcomeSkippedQuietHours}, nil
+		}
+		deferred := *note
+		deferred.ScheduledAt = &resumeAt
+		note = &deferred
+		outcome = PostOutcomeDeferred
+	}
+
+	if err := r.checkMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	if existingID, duplicate := r.findPostedDuplicate(ctx, note); duplicate {
+		return &PostResult{NoteID: existingID, Outcome: PostOutcomeSkippedDuplicate}, nil
+	}
+
+	if err := validateFederation(note, opts); err != nil {
+		return nil, err
+	}
+
+	reservedAt := time.Now()
+	tokenIndex, account, err := r.reserveAccount(reservedAt)
 	if err != nil {
-		return fmt.Errorf("failed to serialize note: %w", err)
+		return nil, err
 	}
+	posted := false
+	defer func() {
+		if !posted {
+			account.refundPost(reservedAt, r.timeZone)
+		}
+	}()
 
-	url := r.host
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "https://" + url
+	note = r.withReplyRecipients(ctx, account, note)
+	note = r.withAttachments(ctx, account, note)
+	if opts.DualVisibility {
+		result, err := r.postDualVisibility(ctx, tokenIndex, account, note, opts)
+		posted = result != nil && result.NoteID != ""
+		if result == nil && r.skipsEmptyNote(err) {
+			return &PostResult{Outcome: PostOutcomeSkippedEmpty}, nil
+		}
+		if result != nil {
+			result.Outcome = outcome
+		}
+		return result, err
 	}
-	url = url + "/api/notes/create"
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
+	first, overflow := r.splitFiles(ctx, note)
+	req := noteRequest{priority: opts.Priority, localOnly: r.resolveLocalOnly(note), source: opts.Source}
+	post := r.postNote
+	if r.chainToSelf && first.ReplyID == "" {
+		post = r.postChained
+	}
+	noteID, err := post(ctx, account, first, req)
+	if r.skipsEmptyNote(err) {
+		return &PostResult{Outcome: PostOutcomeSkippedEmpty}, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, err
 	}
+	posted = true
+	r.scheduleDeletionAfter(noteID, tokenIndex, opts.DeleteAfter)
+	r.rememberPosted(note)
 
-	req.Header.Set("Content-Type", "application/json")
+	result := &PostResult{NoteID: noteID, TokenIndex: tokenIndex, AccountID: account.getUserID(), Outcome: outcome}
+	if err := r.seedReaction(ctx, account, result, opts); err != nil {
+		return result, err
+	}
+	if len(overflow) > 0 {
+		rolledBack, err := r.postFileOverflow(ctx, account, noteID, note, overflow, req)
+		if rolledBack {
+			return nil, fmt.Errorf("note [%s] rolled back: %w", noteID, err)
+		}
+		if err != nil {
+			return result, fmt.Errorf("note [%s] posted but %w", noteID, err)
+		}
+	}
+	if opts.FetchRendered {
+		rendered, err := r.fetchRendered(ctx, account, noteID, opts.Priority)
+		if err != nil {
+			return result, fmt.Errorf("note [%s] posted but rendering could not be fetched: %w", noteID, err)
+		}
+		result.Rendered = rendered
+	}
+	return result, nil
+}
 
-	resp, err := r.client.Do(req)
+func (r *noteRepository) postNote(ctx context.Context, account *postingAccount, note *entity.Note, req noteRequest) (string, error) {
+	req.replyID = note.ReplyID
+	noteID, err := r.createNote(ctx, account, note, req)
+	if err != nil && r.shouldFallbackToStandalone(note, err) {
+		r.logf("Reply target not found [replyId: %s], posting as standalone note", note.ReplyID)
+		req.replyID = ""
+		noteID, err = r.createNote(ctx, account, note, req)
+	}
+	if err != nil && r.shouldRetryWithoutLang(note, req, err) {
+		req.omitLang = true
+		if noteID, err = r.createNote(ctx, account, note, req); err == nil {
+			r.markLangUnsupported()
+		}
+	}
+	if err != nil && hasErrorCode(err, errCodeNoSuchFile) && len(note.FileIDs) > 0 {
+		noteID, err = r.handleMissingFiles(ctx, account, note, req, err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to send request to Misskey API: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("misskey API returned non-OK status: %d", resp.StatusCode)
+	r.audit(auditEntry{
+		Action:     "create",
+		NoteID:     noteID,
+		Visibility: string(note.Visibility),
+		TextSHA256: hashText(note.Text),
+		Source:     req.source,
+	})
+	return noteID, nil
+}
+
+func (r *noteRepository) scheduleDeletionAfter(noteID string, tokenIndex int, after time.Duration) {
+	if after <= 0 {
+		return
 	}
+	r.scheduleDeletion(PendingDeletion{
+		NoteID:     noteID,
+		DeleteAt:   time.Now().Add(after),
+		TokenIndex: tokenIndex,
+	})
+}
 
-	return nil
+func (r *noteRepository) shouldFallbackToStandalone(note *entity.Note, err error) bool {
+	if note.ReplyID == "" || r.replyFallback != ReplyFallbackStandalone {
+		return false
+	}
+	return isNoSuchReplyTarget(err)
+}
+
+func (r *noteRepository) createNote(ctx context.Context, account *postingAccount, note *entity.Note, req noteRequest) (string, error) {
+	if account.suspended.Load() {
+		return "", ErrAccountSuspended
+	}
+	text, cw, err := r.prepareNote(ctx, note)
+	if err != nil {
+		return "", err
+	}
+
+	if req.priority {
+		r.logf("Priority post bypassing local rate limiter")
+	} else if remaining, err := postingLimiter(ctx, account).WaitRemaining(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", &RateLimitWaitError{Remaining: remaining, Err: err})
+	} else if err := r.waitServerRateLimit(ctx, account); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	notePayload := r.filterPayload(r.buildNotePayload(ctx, account, note, req, text, cw))
+
+	var resp createNoteResponse
+	if err := r.callWithRetryObserved(ctx, "notes/create", notePayload, &resp, postingLimiter(ctx, account).observe); err != nil {
+		return "", err
+	}
+	return resp.CreatedNote.ID, nil
+}
+
+func (r *noteRepository) prepareNote(ctx context.Context, note *entity.Note) (string, string, error) {
+	if err := r.checkFileCount(ctx, note); err != nil {
+		return "", "", err
+	}
+	if err := validateLang(note.Lang); err != nil {
+		return "", "", err
+	}
+	if err := r.checkVisibility(note.Visibility); err != nil {
+		return "", "", err
+	}
+
+	return r.composeText(note, func(text string) (string, error) {
+		text, err := r.withMentions(ctx, note, text)
+		if err != nil {
+			return "", err
+		}
+		if err := r.checkTransformedLength(ctx, text); err != nil {
+			return "", err
+		}
+		return r.validateEmojis(ctx, text), nil
+	})
+}
+
+// composeText runs the text transforms that need no network access. resolve,
+// when set, runs after sanitising for the steps that look things up on the
+// instance, so Post and PreviewText share one order of transforms.
+func (r *noteRepository) composeText(note *entity.Note, resolve func(text string) (string, error)) (string, string, error) {
+	text, err := r.sanitizeText("text", r.renderText(note))
+	if err != nil {
+		return "", "", err
+	}
+	cw, err := r.sanitizeText("cw", note.CW)
+	if err != nil {
+		return "", "", err
+	}
+	if resolve != nil {
+		if text, err = resolve(text); err != nil {
+			return "", "", err
+		}
+	}
+	if text, err = r.enforceByteLimit(text); err != nil {
+		return "", "", err
+	}
+	cw, err = r.requiredCW(r.resolveCW(cw, text), text)
+	if err != nil {
+		return "", "", err
+	}
+	if !hasContent(note, text) {
+		return "", "", ErrEmptyNote
+	}
+	if err := r.checkBlocklist(cw + "\n" + text); err != nil {
+		return "", "", err
+	}
+	return text, cw, nil
+}
+
+func (r *noteRepository) buildNotePayload(ctx context.Context, account *postingAccount, note *entity.Note, req noteRequest, text, cw string) map[string]interface{} {
+	notePayload := map[string]interface{}{
+		"i":          account.authToken,
+		"text":       text,
+		"visibility": string(r.resolveVisibility(ctx, note.Visibility)),
+		"localOnly":  req.localOnly,
+	}
+	if cw != "" {
+		notePayload["cw"] = cw
+	}
+	if req.replyID != "" {
+		notePayload["replyId"] = req.replyID
+	}
+	if note.RenoteID != "" {
+		notePayload["renoteId"] = note.RenoteID
+	}
+	if note.ChannelID != "" {
+		notePayload["channelId"] = note.ChannelID
+	}
+	if note.ReactionAcceptance != entity.ReactionAcceptanceAll {
+		notePayload["reactionAcceptance"] = string(note.ReactionAcceptance)
+	}
+	if len(note.FileIDs) > 0 {
+		notePayload["fileIds"] = note.FileIDs
+	}
+	if strings.TrimSpace(text) == "" {
+		delete(notePayload, "text")
+	}
+	if r.includeLang(note, req) {
+		notePayload["lang"] = note.Lang
+	}
+	if len(note.VisibleUserIDs) > 0 {
+		notePayload["visibleUserIds"] = note.VisibleUserIDs
+	}
+	if note.Poll != nil {
+		notePayload["poll"] = pollPayload(note.Poll)
+	}
+	if note.ScheduledAt != nil {
+		notePayload["scheduledAt"] = note.ScheduledAt.UnixMilli()
+	}
+	if r.noExtractMentions && len(note.Mentions) == 0 {
+		notePayload["noExtractMentions"] = true
+	}
+	return notePayload
 }
@@ -2,9 +2,13 @@ package misskey
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
 )
 
 func TestRateLimiter_ImmediateExecution(t *testing.T) {
@@ -184,3 +188,120 @@ func TestMin(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimiter_WaitRemaining(t *testing.T) {
+	refillInterval := 10 * time.Second
+	limiter := newRateLimiter(1, refillInterval)
+
+	remaining, err := limiter.WaitRemaining(context.Background())
+	if err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("expected no remaining wait on success, got %v", remaining)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	remaining, err = limiter.WaitRemaining(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if remaining <= refillInterval-time.Second || remaining > refillInterval {
+		t.Errorf("expected remaining wait close to %v, got %v", refillInterval, remaining)
+	}
+}
+
+func TestNoteRepository_Post_RateLimitWaitError(t *testing.T) {
+	repo := &noteRepository{
+		host:        "http://127.0.0.1:0",
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(1, 10*time.Second),
+	}
+	repo.rateLimiter.permits = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome))
+
+	var waitErr *RateLimitWaitError
+	if !errors.As(err, &waitErr) {
+		t.Fatalf("expected RateLimitWaitError, got %v", err)
+	}
+	if waitErr.Remaining <= 0 {
+		t.Errorf("expected positive remaining wait, got %v", waitErr.Remaining)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error chain to contain context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimiter_BackwardClockJump(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	current := base
+	rl := newRateLimiter(1, time.Minute)
+	rl.now = func() time.Time { return current }
+	rl.lastRefill = base
+
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current = base.Add(-time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	remaining, err := rl.WaitRemaining(ctx)
+	if err == nil {
+		t.Fatal("expected wait right after the clock jump to block")
+	}
+	if remaining > time.Minute {
+		t.Errorf("expected remaining wait to be at most one refill interval, got %v", remaining)
+	}
+
+	current = current.Add(time.Minute)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(ctx); err != nil {
+		t.Errorf("expected limiter to recover one interval after the clock jump, got %v", err)
+	}
+}
+
+func TestNewNoteRepository_ColdStart(t *testing.T) {
+	tests := []struct {
+		name            string
+		coldStart       bool
+		maxPermits      int
+		expectedPermits int
+	}{
+		{"full bucket by default", false, 5, 5},
+		{"cold start keeps one permit", true, 5, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noteRepo, err := NewNoteRepository(Config{
+				Host:       "misskey.example",
+				AuthToken:  "token-a",
+				AuthTokens: []string{"token-b"},
+				MaxPermits: tt.maxPermits,
+				ColdStart:  tt.coldStart,
+			})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+			repo := noteRepo.(*noteRepository)
+
+			for i, account := range repo.accounts {
+				if account.rateLimiter.permits != tt.expectedPermits {
+					t.Errorf("account %d: expected %d initial permits, got %d", i, tt.expectedPermits, account.rateLimiter.permits)
+				}
+			}
+			if repo.rateLimiter.permits != tt.expectedPermits {
+				t.Errorf("expected %d initial permits, got %d", tt.expectedPermits, repo.rateLimiter.permits)
+			}
+		})
+	}
+}
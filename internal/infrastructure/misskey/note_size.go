@@ -0,0 +1,34 @@
+package misskey
+
+import (
+	"fmt"
+)
+
+type NoteSizePolicy string
+
+const (
+	NoteSizeFail     NoteSizePolicy = "fail"
+	NoteSizeTruncate NoteSizePolicy = "truncate"
+)
+
+func (p NoteSizePolicy) validate() error {
+	switch p {
+	case "", NoteSizeFail, NoteSizeTruncate:
+		return nil
+	}
+	return fmt.Errorf("unknown note size policy: %s", p)
+}
+
+func (r *noteRepository) enforceByteLimit(text string) (string, error) {
+	if r.maxNoteBytes <= 0 || len(text) <= r.maxNoteBytes {
+		return text, nil
+	}
+
+	if r.onNoteTooLarge != NoteSizeTruncate {
+		return "", fmt.Errorf("%w: %d bytes, limit is %d", ErrNoteTooLargeBytes, len(text), r.maxNoteBytes)
+	}
+
+	truncated := TruncateText(text, r.maxNoteBytes, TruncateOptions{PreserveLink: true, CountBytes: true})
+	r.logf("Warning: Note text is %d bytes, truncated to %d to fit the %d byte limit", len(text), len(truncated), r.maxNoteBytes)
+	return truncated, nil
+}
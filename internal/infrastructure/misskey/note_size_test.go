@@ -0,0 +1,78 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_MaxNoteBytes(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		maxBytes     int
+		policy       NoteSizePolicy
+		expectErr    error
+		expectedText string
+	}{
+		{"unlimited", strings.Repeat("あ", 200), 0, "", nil, strings.Repeat("あ", 200)},
+		{"cjk exactly at limit", strings.Repeat("あ", 10), 30, "", nil, strings.Repeat("あ", 10)},
+		{"cjk one char over limit fails", strings.Repeat("あ", 11), 30, NoteSizeFail, ErrNoteTooLargeBytes, ""},
+		{"ascii under byte limit", strings.Repeat("a", 30), 30, NoteSizeFail, nil, strings.Repeat("a", 30)},
+		{"cjk over limit truncated", strings.Repeat("あ", 11), 30, NoteSizeTruncate, nil, strings.Repeat("あ", 9) + "…"},
+		{"truncation keeps link", strings.Repeat("あ", 20) + " https://example.tld/a", 50, NoteSizeTruncate, nil, strings.Repeat("あ", 8) + "… https://example.tld/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				posted, _ = body["text"].(string)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:           server.URL,
+				authToken:      "test-token",
+				client:         &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:    newRateLimiter(10, 10*time.Second),
+				maxNoteBytes:   tt.maxBytes,
+				onNoteTooLarge: tt.policy,
+			}
+
+			err := repo.Post(context.Background(), entity.NewNote(tt.text, entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if posted != tt.expectedText {
+				t.Errorf("expected text %q, got %q", tt.expectedText, posted)
+			}
+			if tt.maxBytes > 0 && (len(posted) > tt.maxBytes || !utf8.ValidString(posted)) {
+				t.Errorf("posted text is %d bytes or invalid UTF-8, limit %d", len(posted), tt.maxBytes)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_MaxNoteBytesErrorNamesSizes(t *testing.T) {
+	repo := &noteRepository{rateLimiter: newRateLimiter(1, time.Second), maxNoteBytes: 30}
+
+	err := repo.Post(context.Background(), entity.NewNote(strings.Repeat("漢", 11), entity.VisibilityHome))
+	if !errors.Is(err, ErrNoteTooLargeBytes) {
+		t.Fatalf("expected ErrNoteTooLargeBytes, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "33 bytes") || !strings.Contains(err.Error(), "limit is 30") {
+		t.Errorf("expected error to name actual and allowed size, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationMention  NotificationType = "mention"
+	NotificationReply    NotificationType = "reply"
+	NotificationReaction NotificationType = "reaction"
+)
+
+const defaultNotificationLimit = 10
+
+type Notification struct {
+	ID        string
+	Type      NotificationType
+	CreatedAt time.Time
+	NoteID    string
+	Reaction  string
+	UserID    string
+	Username  string
+	UserHost  string
+}
+
+type NotificationQuery struct {
+	SinceID string
+	UntilID string
+	Limit   int
+}
+
+type notificationResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Reaction  string    `json:"reaction"`
+	User      struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Host     string `json:"host"`
+	} `json:"user"`
+	Note struct {
+		ID string `json:"id"`
+	} `json:"note"`
+}
+
+func (r *noteRepository) Notifications(ctx context.Context, sinceID string) ([]Notification, error) {
+	return r.ListNotifications(ctx, NotificationQuery{SinceID: sinceID})
+}
+
+func (r *noteRepository) ListNotifications(ctx context.Context, query NotificationQuery) ([]Notification, error) {
+	if r.pollLimiter != nil {
+		if remaining, err := r.pollLimiter.WaitRemaining(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", &RateLimitWaitError{Remaining: remaining, Err: err})
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	params := map[string]interface{}{
+		"limit":        limit,
+		"includeTypes": []NotificationType{NotificationMention, NotificationReply, NotificationReaction},
+	}
+	if query.SinceID != "" {
+		params["sinceId"] = query.SinceID
+	}
+	if query.UntilID != "" {
+		params["untilId"] = query.UntilID
+	}
+
+	r.ensureSelfIDs(ctx)
+
+	var resp []notificationResponse
+	if err := r.call(ctx, "i/notifications", params, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+	}
+
+	notifications := make([]Notification, 0, len(resp))
+	for _, item := range resp {
+		notifications = append(notifications, Notification{
+			ID:        item.ID,
+			Type:      NotificationType(item.Type),
+			CreatedAt: item.CreatedAt,
+			NoteID:    item.Note.ID,
+			Reaction:  item.Reaction,
+			UserID:    item.User.ID,
+			Username:  item.User.Username,
+			UserHost:  item.User.Host,
+		})
+	}
+	return r.withoutSelf(notifications), nil
+}
@@ -0,0 +1,108 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_ListNotifications(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         NotificationQuery
+		expectSinceID interface{}
+		expectUntilID interface{}
+		expectLimit   float64
+	}{
+		{"since", NotificationQuery{SinceID: "n1"}, "n1", nil, defaultNotificationLimit},
+		{"until with limit", NotificationQuery{UntilID: "n9", Limit: 50}, nil, "n9", 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/i" {
+					w.Write([]byte(`{"id": "bot"}`))
+					return
+				}
+				if r.URL.Path != "/api/i/notifications" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`[
+					{"id": "n2", "type": "mention", "createdAt": "2024-01-02T03:04:05Z", "user": {"id": "u1", "username": "alice", "host": null}, "note": {"id": "note1"}},
+					{"id": "n3", "type": "reaction", "createdAt": "2024-01-02T03:05:05Z", "reaction": ":like:", "user": {"id": "u2", "username": "bob", "host": "remote.tld"}, "note": {"id": "note2"}}
+				]`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			notifications, err := repo.ListNotifications(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if payload["sinceId"] != tt.expectSinceID {
+				t.Errorf("expected sinceId %v, got %v", tt.expectSinceID, payload["sinceId"])
+			}
+			if payload["untilId"] != tt.expectUntilID {
+				t.Errorf("expected untilId %v, got %v", tt.expectUntilID, payload["untilId"])
+			}
+			if payload["limit"] != tt.expectLimit {
+				t.Errorf("expected limit %v, got %v", tt.expectLimit, payload["limit"])
+			}
+
+			if len(notifications) != 2 {
+				t.Fatalf("expected 2 notifications, got %d", len(notifications))
+			}
+			if notifications[0].Type != NotificationMention || notifications[0].NoteID != "note1" || notifications[0].Username != "alice" {
+				t.Errorf("unexpected first notification: %+v", notifications[0])
+			}
+			if notifications[1].Type != NotificationReaction || notifications[1].Reaction != ":like:" || notifications[1].UserHost != "remote.tld" {
+				t.Errorf("unexpected second notification: %+v", notifications[1])
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Notifications_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		pollLimiter: newRateLimiter(1, time.Hour),
+	}
+
+	if _, err := repo.Notifications(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := repo.Notifications(ctx, "")
+	var waitErr *RateLimitWaitError
+	if !errors.As(err, &waitErr) {
+		t.Fatalf("expected RateLimitWaitError, got %v", err)
+	}
+}
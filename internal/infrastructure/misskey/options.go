@@ -0,0 +1,114 @@
+package misskey
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+// Option configures NewNoteRepositoryWithOptions. Values set through an
+// option are validated as given instead of falling back to the defaults
+// NewNoteRepository applies to zero values.
+type Option func(*options)
+
+type options struct {
+	cfg          Config
+	rateLimitSet bool
+	retrySet     bool
+}
+
+func WithAPIBasePath(path string) Option {
+	return func(o *options) {
+		o.cfg.APIBasePath = path
+	}
+}
+
+func WithAdditionalTokens(tokens ...string) Option {
+	return func(o *options) {
+		o.cfg.AuthTokens = append(o.cfg.AuthTokens, tokens...)
+	}
+}
+
+func WithRateLimit(maxPermits int, refillInterval time.Duration) Option {
+	return func(o *options) {
+		o.cfg.MaxPermits = maxPermits
+		o.cfg.RefillInterval = refillInterval
+		o.rateLimitSet = true
+	}
+}
+
+func WithRetry(maxRetries int, backoff time.Duration, jitter BackoffJitter) Option {
+	return func(o *options) {
+		o.cfg.MaxRetries = maxRetries
+		o.cfg.RetryBackoff = backoff
+		o.cfg.BackoffJitter = jitter
+		o.retrySet = true
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.cfg.HTTPClient = client
+	}
+}
+
+func WithLocalOnly(localOnly bool) Option {
+	return func(o *options) {
+		o.cfg.LocalOnly = localOnly
+	}
+}
+
+func WithAllowedHosts(hosts ...string) Option {
+	return func(o *options) {
+		o.cfg.AllowedHosts = append(o.cfg.AllowedHosts, hosts...)
+	}
+}
+
+func WithStateStore(store repository.StateStore) Option {
+	return func(o *options) {
+		o.cfg.StateStore = store
+	}
+}
+
+func WithAppName(name string) Option {
+	return func(o *options) {
+		o.cfg.AppName = name
+	}
+}
+
+func WithExtraVisibilities(visibilities ...entity.NoteVisibility) Option {
+	return func(o *options) {
+		o.cfg.ExtraVisibilities = append(o.cfg.ExtraVisibilities, visibilities...)
+	}
+}
+
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.cfg.Logger = logger
+	}
+}
+
+func (o *options) validate() error {
+	if o.rateLimitSet && (o.cfg.MaxPermits <= 0 || o.cfg.RefillInterval <= 0) {
+		return fmt.Errorf("%w: %d permits every %v", ErrInvalidRateLimit, o.cfg.MaxPermits, o.cfg.RefillInterval)
+	}
+	if o.retrySet && (o.cfg.MaxRetries < 0 || o.cfg.RetryBackoff <= 0) {
+		return fmt.Errorf("invalid retry settings: %d retries with %v backoff", o.cfg.MaxRetries, o.cfg.RetryBackoff)
+	}
+	return nil
+}
+
+func NewNoteRepositoryWithOptions(host, authToken string, opts ...Option) (repository.NoteRepository, error) {
+	o := options{cfg: Config{Host: host, AuthToken: authToken}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	return NewNoteRepository(o.cfg)
+}
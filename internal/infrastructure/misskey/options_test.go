@@ -0,0 +1,101 @@
+package misskey
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewNoteRepositoryWithOptions(t *testing.T) {
+	client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{}}
+
+	noteRepo, err := NewNoteRepositoryWithOptions("misskey.example", "token-a",
+		WithAPIBasePath("/proxy/api/"),
+		WithAdditionalTokens("token-b"),
+		WithRateLimit(7, time.Minute),
+		WithRetry(2, 3*time.Second, BackoffJitterNone),
+		WithHTTPClient(client),
+		WithLocalOnly(true),
+		WithAppName("custom-bot"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := noteRepo.(*noteRepository)
+
+	if repo.host != "misskey.example" || repo.authToken != "token-a" {
+		t.Errorf("unexpected host/token: %s/%s", repo.host, repo.authToken)
+	}
+	if repo.apiBasePath != "/proxy/api" {
+		t.Errorf("expected normalized base path, got %s", repo.apiBasePath)
+	}
+	if len(repo.accounts) != 2 {
+		t.Errorf("expected 2 accounts, got %d", len(repo.accounts))
+	}
+	if repo.rateLimiter.maxPermits != 7 || repo.rateLimiter.refillRate != time.Minute {
+		t.Errorf("unexpected rate limit: %d per %v", repo.rateLimiter.maxPermits, repo.rateLimiter.refillRate)
+	}
+	if repo.maxRetries != 2 || repo.retryBackoff != 3*time.Second || repo.backoffJitter != BackoffJitterNone {
+		t.Errorf("unexpected retry settings: %d, %v, %s", repo.maxRetries, repo.retryBackoff, repo.backoffJitter)
+	}
+	if repo.client.Transport != client.Transport || repo.client.Timeout != client.Timeout {
+		t.Error("expected custom HTTP client to be used")
+	}
+	if !repo.localOnly || repo.appName != "custom-bot" {
+		t.Errorf("unexpected localOnly/appName: %v/%s", repo.localOnly, repo.appName)
+	}
+}
+
+func TestNewNoteRepositoryWithOptions_SharedValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"invalid base path", []Option{WithAPIBasePath("api")}},
+		{"invalid jitter", []Option{WithRetry(1, time.Second, "sometimes")}},
+		{"host not allowed", []Option{WithAllowedHosts("other.example")}},
+		{"TLS with custom client", []Option{WithHTTPClient(&http.Client{}), func(o *options) { o.cfg.TLS.InsecureSkipVerify = true }}},
+		{"explicit zero permits", []Option{WithRateLimit(0, time.Minute)}},
+		{"explicit zero backoff", []Option{WithRetry(1, 0, BackoffJitterNone)}},
+		{"negative retries", []Option{WithRetry(-1, time.Second, BackoffJitterNone)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewNoteRepositoryWithOptions("misskey.example", "token-a", tt.opts...); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewNoteRepositoryWithOptions_ExplicitZero(t *testing.T) {
+	noteRepo, err := NewNoteRepositoryWithOptions("misskey.example", "token-a",
+		WithRetry(0, time.Second, BackoffJitterNone),
+		WithLocalOnly(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := noteRepo.(*noteRepository)
+	if repo.maxRetries != 0 || repo.localOnly {
+		t.Errorf("expected explicit zero values to be kept, got %d retries, localOnly %v", repo.maxRetries, repo.localOnly)
+	}
+}
+
+func TestNewNoteRepositoryWithOptions_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewNoteRepositoryWithOptions("misskey.example", "token-a",
+		WithLogger(log.New(&buf, "", 0)),
+		func(o *options) { o.cfg.TLS.InsecureSkipVerify = true },
+	)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	if !strings.Contains(buf.String(), "TLS certificate verification is disabled") {
+		t.Errorf("expected warning on the configured logger, got %q", buf.String())
+	}
+}
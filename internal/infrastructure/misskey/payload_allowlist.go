@@ -0,0 +1,31 @@
+package misskey
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+func isRequiredPayloadField(key string) bool {
+	return key == "i" || key == "text" || key == "visibility"
+}
+
+func (r *noteRepository) filterPayload(payload map[string]interface{}) map[string]interface{} {
+	if len(r.payloadAllowlist) == 0 {
+		return payload
+	}
+
+	var dropped []string
+	for key := range payload {
+		if isRequiredPayloadField(key) || slices.Contains(r.payloadAllowlist, key) {
+			continue
+		}
+		delete(payload, key)
+		dropped = append(dropped, key)
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		r.logf("Dropped payload fields not in allowlist: %s", strings.Join(dropped, ", "))
+	}
+	return payload
+}
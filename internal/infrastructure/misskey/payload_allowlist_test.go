@@ -0,0 +1,80 @@
+package misskey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_PayloadAllowlist(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowlist    []string
+		expectedKeys []string
+		expectedLog  string
+	}{
+		{"no allowlist", nil, []string{"cw", "fileIds", "i", "localOnly", "replyId", "text", "visibility"}, ""},
+		{"restricted", []string{"cw"}, []string{"cw", "i", "text", "visibility"}, "Dropped payload fields not in allowlist: fileIds, localOnly, replyId\n"},
+		{"required fields always kept", []string{"unknown"}, []string{"i", "text", "visibility"}, "Dropped payload fields not in allowlist: cw, fileIds, localOnly, replyId\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/meta") {
+					w.Write([]byte(`{}`))
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &receivedPayload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			var logs bytes.Buffer
+			repo := &noteRepository{
+				logger:           log.New(&logs, "", 0),
+				host:             server.URL,
+				authToken:        "test-token",
+				client:           &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:      newRateLimiter(3, 10*time.Second),
+				payloadAllowlist: tt.allowlist,
+			}
+
+			note := &entity.Note{Text: "Hello", CW: "Topic", Visibility: entity.VisibilityHome, ReplyID: "parent1", FileIDs: []string{"file1"}}
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if logs.String() != tt.expectedLog {
+				t.Errorf("expected log %q, got %q", tt.expectedLog, logs.String())
+			}
+
+			var keys []string
+			for key := range receivedPayload {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if len(keys) != len(tt.expectedKeys) {
+				t.Fatalf("expected keys %v, got %v", tt.expectedKeys, keys)
+			}
+			for i, key := range tt.expectedKeys {
+				if keys[i] != key {
+					t.Errorf("expected keys %v, got %v", tt.expectedKeys, keys)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type pinState struct {
+	mu      sync.Mutex
+	current string
+	stale   []string
+}
+
+type pinnedNotesResponse struct {
+	PinnedNoteIDs []string `json:"pinnedNoteIds"`
+}
+
+func (r *noteRepository) Pin(ctx context.Context, noteID string) error {
+	if err := r.call(ctx, "i/pin", map[string]interface{}{"noteId": noteID}, nil); err != nil {
+		return fmt.Errorf("failed to pin note [%s]: %w", noteID, err)
+	}
+	return nil
+}
+
+func (r *noteRepository) Unpin(ctx context.Context, noteID string) error {
+	if err := r.call(ctx, "i/unpin", map[string]interface{}{"noteId": noteID}, nil); err != nil {
+		return fmt.Errorf("failed to unpin note [%s]: %w", noteID, err)
+	}
+	return nil
+}
+
+func (r *noteRepository) PinLatest(ctx context.Context, noteID string) error {
+	r.pins.mu.Lock()
+	defer r.pins.mu.Unlock()
+
+	var me pinnedNotesResponse
+	if err := r.call(ctx, "i", nil, &me); err != nil {
+		return fmt.Errorf("failed to fetch pinned notes: %w", err)
+	}
+	pinned := make(map[string]bool, len(me.PinnedNoteIDs))
+	for _, id := range me.PinnedNoteIDs {
+		pinned[id] = true
+	}
+
+	var previous []string
+	for _, id := range append(r.pins.stale, r.pins.current) {
+		if id != "" && id != noteID && pinned[id] {
+			previous = append(previous, id)
+		}
+	}
+
+	if !pinned[noteID] {
+		err := r.Pin(ctx, noteID)
+		if hasErrorCode(err, errCodePinLimitExceeded) && len(previous) > 0 {
+			r.logf("Pin limit reached, unpinning previous bot notes before pinning [%s]", noteID)
+			previous = r.unpinAll(ctx, previous)
+			err = r.Pin(ctx, noteID)
+		}
+		if err != nil && !hasErrorCode(err, errCodeAlreadyPinned) {
+			return err
+		}
+	}
+
+	errs := newMultiError(fmt.Sprintf("pinned note [%s] but previous pins remain", noteID), len(previous))
+	r.pins.stale = nil
+	for _, id := range previous {
+		if err := r.Unpin(ctx, id); err != nil && !isNoSuchNote(err) {
+			r.pins.stale = append(r.pins.stale, id)
+			errs.Add(id, err)
+		}
+	}
+	r.pins.current = noteID
+	r.saveState(stateNamespaceCursors, stateKeyPins, persistedPins{Current: noteID, Stale: r.pins.stale})
+
+	return errs.ErrOrNil()
+}
+
+func (r *noteRepository) unpinAll(ctx context.Context, noteIDs []string) []string {
+	var remaining []string
+	for _, id := range noteIDs {
+		if err := r.Unpin(ctx, id); err != nil && !isNoSuchNote(err) {
+			r.logf("Failed to unpin note [%s]: %v", id, err)
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,111 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakePinServer struct {
+	mu        sync.Mutex
+	pinned    []string
+	limit     int
+	failUnpin map[string]bool
+}
+
+func (s *fakePinServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var payload map[string]interface{}
+	body, _ := io.ReadAll(r.Body)
+	json.Unmarshal(body, &payload)
+	noteID, _ := payload["noteId"].(string)
+
+	switch r.URL.Path {
+	case "/api/i":
+		json.NewEncoder(w).Encode(map[string]interface{}{"pinnedNoteIds": s.pinned})
+	case "/api/i/pin":
+		if slices.Contains(s.pinned, noteID) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "ALREADY_PINNED"}}`))
+			return
+		}
+		if len(s.pinned) >= s.limit {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": "PIN_LIMIT_EXCEEDED"}}`))
+			return
+		}
+		s.pinned = append(s.pinned, noteID)
+		w.Write([]byte(`{}`))
+	case "/api/i/unpin":
+		if s.failUnpin[noteID] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.pinned = slices.DeleteFunc(s.pinned, func(id string) bool { return id == noteID })
+		w.Write([]byte(`{}`))
+	default:
+		http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+	}
+}
+
+func TestNoteRepository_PinLatest(t *testing.T) {
+	tests := []struct {
+		name         string
+		initial      []string
+		limit        int
+		current      string
+		failUnpin    map[string]bool
+		noteID       string
+		expectPinned []string
+		expectErr    bool
+		expectStale  []string
+	}{
+		{"first pin", nil, 5, "", nil, "n1", []string{"n1"}, false, nil},
+		{"replaces previous bot pin", []string{"manual", "n1"}, 5, "n1", nil, "n2", []string{"manual", "n2"}, false, nil},
+		{"idempotent when already pinned", []string{"n1"}, 5, "n1", nil, "n1", []string{"n1"}, false, nil},
+		{"unpins first at pin limit", []string{"manual", "n1"}, 2, "n1", nil, "n2", []string{"manual", "n2"}, false, nil},
+		{"previous unpinned manually", []string{"manual"}, 5, "n1", nil, "n2", []string{"manual", "n2"}, false, nil},
+		{"unpin failure kept as stale", []string{"n1"}, 5, "n1", map[string]bool{"n1": true}, "n2", []string{"n1", "n2"}, true, []string{"n1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakePinServer{pinned: slices.Clone(tt.initial), limit: tt.limit, failUnpin: tt.failUnpin}
+			server := httptest.NewServer(http.HandlerFunc(fake.handle))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+			repo.pins.current = tt.current
+
+			err := repo.PinLatest(context.Background(), tt.noteID)
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(fake.pinned, tt.expectPinned) {
+				t.Errorf("expected pinned %v, got %v", tt.expectPinned, fake.pinned)
+			}
+			if repo.pins.current != tt.noteID {
+				t.Errorf("expected current pin %s, got %s", tt.noteID, repo.pins.current)
+			}
+			if !slices.Equal(repo.pins.stale, tt.expectStale) {
+				t.Errorf("expected stale pins %v, got %v", tt.expectStale, repo.pins.stale)
+			}
+		})
+	}
+}
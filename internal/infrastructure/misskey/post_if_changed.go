@@ -0,0 +1,119 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type noteContent struct {
+	Text       string   `json:"text"`
+	CW         string   `json:"cw"`
+	Visibility string   `json:"visibility"`
+	ReplyID    string   `json:"replyId"`
+	RenoteID   string   `json:"renoteId"`
+	FileIDs    []string `json:"fileIds"`
+}
+
+// contentLockTTL is how long an idle per-key lock is kept; it only has to
+// outlive the post that holds it.
+const contentLockTTL = time.Hour
+
+type contentLocks struct {
+	mu    sync.Mutex
+	locks keyedTTLCache[*sync.Mutex]
+}
+
+func (l *contentLocks) lock(key string) func() {
+	l.mu.Lock()
+	now := time.Now()
+	keyLock, ok := l.locks.get(key, now)
+	if !ok {
+		keyLock = &sync.Mutex{}
+	}
+	l.locks.set(key, keyLock, contentLockTTL, now)
+	l.mu.Unlock()
+
+	keyLock.Lock()
+	return keyLock.Unlock
+}
+
+func contentHash(note *entity.Note) (string, error) {
+	data, err := json.Marshal(noteContent{
+		Text:       note.Text,
+		CW:         note.CW,
+		Visibility: string(note.Visibility),
+		ReplyID:    note.ReplyID,
+		RenoteID:   note.RenoteID,
+		FileIDs:    note.FileIDs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash note content: %w", err)
+	}
+	return hashText(string(data)), nil
+}
+
+func (r *noteRepository) PostIfChanged(ctx context.Context, key string, note *entity.Note) (*PostResult, error) {
+	if r.state == nil && r.dedupe == nil {
+		return nil, fmt.Errorf("%w to post only changed content", ErrStateStoreRequired)
+	}
+
+	hash, err := contentHash(note)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := r.contentLocks.lock(key)
+	defer unlock()
+
+	previous, err := r.loadContentHash(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if previous == hash {
+		return &PostResult{Outcome: PostOutcomeSkippedUnchanged}, nil
+	}
+
+	result, err := r.PostWithOptions(ctx, note, PostOptions{})
+	if result != nil && result.NoteID != "" && (result.Outcome == PostOutcomePosted || result.Outcome == PostOutcomeDeferred) {
+		r.saveContentHash(key, hash)
+	}
+	return result, err
+}
+
+func (r *noteRepository) loadContentHash(ctx context.Context, key string) (string, error) {
+	if r.dedupe != nil {
+		data, _, err := r.dedupe.Get(ctx, stateNamespaceContent+":"+key)
+		if err != nil {
+			return "", fmt.Errorf("failed to load content hash [%s]: %w", key, err)
+		}
+		return string(data), nil
+	}
+
+	data, ok, err := r.state.Get(ctx, stateNamespaceContent, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to load content hash [%s]: %w", key, err)
+	}
+	if !ok {
+		return "", nil
+	}
+	var previous string
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return "", fmt.Errorf("failed to decode content hash [%s]: %w", key, err)
+	}
+	return previous, nil
+}
+
+func (r *noteRepository) saveContentHash(key, hash string) {
+	if r.dedupe == nil {
+		r.saveState(stateNamespaceContent, key, hash)
+		return
+	}
+	if err := r.dedupe.Remember(context.Background(), stateNamespaceContent+":"+key, []byte(hash), 0); err != nil {
+		r.logf("Failed to persist content hash [%s]: %v", key, err)
+	}
+}
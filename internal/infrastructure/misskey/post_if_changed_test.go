@@ -0,0 +1,185 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_PostIfChanged(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	store, err := storage.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("failed to open state store: %v", err)
+	}
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+		state:       store,
+	}
+
+	steps := []struct {
+		name          string
+		key           string
+		note          *entity.Note
+		expectSkipped bool
+		expectedPosts int
+	}{
+		{"first post", "feed-a", entity.NewNote("Hello", entity.VisibilityHome), false, 1},
+		{"unchanged", "feed-a", entity.NewNote("Hello", entity.VisibilityHome), true, 1},
+		{"changed text", "feed-a", entity.NewNote("Hello again", entity.VisibilityHome), false, 2},
+		{"changed visibility", "feed-a", entity.NewNote("Hello again", entity.VisibilityPublic), false, 3},
+		{"other key", "feed-b", entity.NewNote("Hello again", entity.VisibilityPublic), false, 4},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			result, err := repo.PostIfChanged(context.Background(), step.key, step.note)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if skipped := result.Outcome == PostOutcomeSkippedUnchanged; skipped != step.expectSkipped {
+				t.Errorf("expected skipped=%v, got outcome %s", step.expectSkipped, result.Outcome)
+			}
+			if posts != step.expectedPosts {
+				t.Errorf("expected %d posts, got %d", step.expectedPosts, posts)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostIfChanged_SkippedNotRemembered(t *testing.T) {
+	now := time.Now()
+	offset := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+
+	tests := []struct {
+		name            string
+		note            *entity.Note
+		quietHours      QuietHours
+		expectedOutcome PostOutcome
+	}{
+		{"quiet hours drop", entity.NewNote("Night post", entity.VisibilityHome), QuietHours{
+			Start:    (offset + 23*time.Hour) % (24 * time.Hour),
+			End:      (offset + time.Hour) % (24 * time.Hour),
+			Location: now.Location(),
+			Policy:   QuietHoursDrop,
+		}, PostOutcomeSkippedQuietHours},
+		{"empty note skipped", &entity.Note{Visibility: entity.VisibilityHome}, QuietHours{}, PostOutcomeSkippedEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(10, 10*time.Second),
+				dedupe:      storage.NewMemoryDedupeStore(),
+				quietHours:  tt.quietHours,
+				onEmptyNote: EmptyNoteSkip,
+			}
+
+			result, err := repo.PostIfChanged(context.Background(), "feed-a", tt.note)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Outcome != tt.expectedOutcome {
+				t.Fatalf("expected outcome %s, got %s", tt.expectedOutcome, result.Outcome)
+			}
+			if hash, _ := repo.loadContentHash(context.Background(), "feed-a"); hash != "" {
+				t.Errorf("expected skipped content not to be remembered, got hash %s", hash)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostIfChangedRequiresStateStore(t *testing.T) {
+	repo := &noteRepository{rateLimiter: newRateLimiter(1, time.Second)}
+
+	_, err := repo.PostIfChanged(context.Background(), "feed-a", entity.NewNote("Hello", entity.VisibilityHome))
+	if !errors.Is(err, ErrStateStoreRequired) {
+		t.Errorf("expected ErrStateStoreRequired, got %v", err)
+	}
+}
+
+func TestNoteRepository_PostIfChanged_DedupeStore(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+		dedupe:      storage.NewMemoryDedupeStore(),
+	}
+
+	for _, text := range []string{"Hello", "Hello", "Hello again"} {
+		if _, err := repo.PostIfChanged(context.Background(), "feed-a", entity.NewNote(text, entity.VisibilityHome)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if posts != 2 {
+		t.Errorf("expected 2 posts, got %d", posts)
+	}
+}
+
+func TestNoteRepository_PostIfChanged_ConcurrentSameKey(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+		dedupe:      storage.NewMemoryDedupeStore(),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.PostIfChanged(context.Background(), "feed-a", entity.NewNote("Hello", entity.VisibilityHome)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := posts.Load(); got != 1 {
+		t.Errorf("expected 1 post, got %d", got)
+	}
+}
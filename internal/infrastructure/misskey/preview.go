@@ -0,0 +1,42 @@
+package misskey
+
+import (
+	"context"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+// PreviewText returns the text Post would send without making any request,
+// so mentions are left unresolved and unknown emojis are kept. It returns ""
+// when Post would reject the note.
+func (r *noteRepository) PreviewText(note *entity.Note) string {
+	text, _, err := r.composeText(r.withCategoryVisibility(note), nil)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// PreviewPayload returns the notes/create request body Post would send,
+// without the auth token and without waiting on the rate limiter.
+func (r *noteRepository) PreviewPayload(ctx context.Context, note *entity.Note) (map[string]interface{}, error) {
+	note = r.withCategoryVisibility(note)
+	text, cw, err := r.prepareNote(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	account := r.peekAccount()
+	req := noteRequest{replyID: note.ReplyID, localOnly: r.resolveLocalOnly(note)}
+	payload := r.filterPayload(r.buildNotePayload(ctx, account, note, req, text, cw))
+	delete(payload, "i")
+	return payload, nil
+}
+
+func (r *noteRepository) renderText(note *entity.Note) string {
+	text := note.Text
+	if r.trimWhitespace {
+		text = normalizeWhitespace(text)
+	}
+	return r.withPublishedTime(r.transformLinks(text), note)
+}
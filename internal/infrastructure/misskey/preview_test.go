@@ -0,0 +1,111 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_PreviewText_MatchesPostedText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+	}{
+		{"plain", "📰 Title\nhttps://example.tld/article", 0},
+		{"byte limit truncation", "📰 " + strings.Repeat("長い", 40) + "\nhttps://example.tld/article", 120},
+		{"whitespace trimmed", "📰 Title  \n\n\n\nhttps://example.tld/article", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedText string
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				receivedText, _ = payload["text"].(string)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:           server.URL,
+				authToken:      "test-token",
+				client:         &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:    newRateLimiter(3, 10*time.Second),
+				maxNoteBytes:   tt.maxBytes,
+				onNoteTooLarge: NoteSizeTruncate,
+				trimWhitespace: true,
+			}
+
+			note := entity.NewNote(tt.text, entity.VisibilityHome)
+			preview := repo.PreviewText(note)
+			if requests != 0 {
+				t.Fatalf("expected preview to make no requests, got %d", requests)
+			}
+
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if preview != receivedText {
+				t.Errorf("expected preview %q to match posted text %q", preview, receivedText)
+			}
+			if tt.maxBytes > 0 && len(preview) > tt.maxBytes {
+				t.Errorf("expected preview to fit %d bytes, got %d", tt.maxBytes, len(preview))
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PreviewPayload_MatchesPostedPayload(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+		localOnly:   true,
+	}
+
+	note := entity.NewNote("📰 Title\nhttps://example.tld/article", entity.VisibilityPublic)
+	note.CW = "spoiler"
+	note.ReactionAcceptance = entity.ReactionAcceptanceLikeOnly
+
+	preview, err := repo.PreviewPayload(context.Background(), note)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := preview["i"]; ok {
+		t.Error("preview should not include the auth token")
+	}
+	if repo.rateLimiter.stats().Permits != 3 {
+		t.Error("preview should not consume a rate limiter permit")
+	}
+
+	if err := repo.Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delete(received, "i")
+	previewJSON, _ := json.Marshal(preview)
+	receivedJSON, _ := json.Marshal(received)
+	if string(previewJSON) != string(receivedJSON) {
+		t.Errorf("expected preview %s to match posted payload %s", previewJSON, receivedJSON)
+	}
+}
@@ -0,0 +1,60 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type noteReactionsResponse struct {
+	ID            string         `json:"id"`
+	ReactionCount int            `json:"reactionCount"`
+	Reactions     map[string]int `json:"reactions"`
+}
+
+func (n noteReactionsResponse) total() int {
+	if n.ReactionCount > 0 {
+		return n.ReactionCount
+	}
+	total := 0
+	for _, count := range n.Reactions {
+		total += count
+	}
+	return total
+}
+
+func (r *noteRepository) Renote(ctx context.Context, noteID string, visibility entity.NoteVisibility) (*PostResult, error) {
+	return r.PostWithOptions(ctx, &entity.Note{RenoteID: noteID, Visibility: visibility}, PostOptions{})
+}
+
+func (r *noteRepository) PromoteOnReactions(ctx context.Context, noteID string, threshold int) (bool, error) {
+	var resp noteReactionsResponse
+	if err := r.call(ctx, "notes/show", map[string]interface{}{"noteId": noteID}, &resp); err != nil {
+		if isNoSuchNote(err) {
+			r.logf("Warning: Note [%s] no longer exists, skipping promotion", noteID)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to fetch reactions of note [%s]: %w", noteID, err)
+	}
+
+	reactions := resp.total()
+	if reactions < threshold {
+		return false, nil
+	}
+
+	result, err := r.Renote(ctx, noteID, entity.VisibilityPublic)
+	if err != nil {
+		if isNoSuchNote(err) {
+			r.logf("Warning: Note [%s] was deleted before it could be promoted", noteID)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to promote note [%s]: %w", noteID, err)
+	}
+	if result.Outcome == PostOutcomeSkippedQuietHours {
+		return false, nil
+	}
+
+	r.logf("Promoted note [%s] with %d reaction(s) as public renote [%s]", noteID, reactions, result.NoteID)
+	return true, nil
+}
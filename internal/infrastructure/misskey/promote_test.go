@@ -0,0 +1,96 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteReactionsResponse_Total(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     noteReactionsResponse
+		expected int
+	}{
+		{"reaction count present", noteReactionsResponse{ReactionCount: 7, Reactions: map[string]int{"👍": 2}}, 7},
+		{"summed from reactions", noteReactionsResponse{Reactions: map[string]int{"👍": 2, ":heart:": 3}}, 5},
+		{"no reactions", noteReactionsResponse{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.total(); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PromoteOnReactions(t *testing.T) {
+	tests := []struct {
+		name           string
+		showStatus     int
+		showBody       string
+		threshold      int
+		expectPromoted bool
+		expectErr      bool
+	}{
+		{"threshold reached", http.StatusOK, `{"id":"note1","reactions":{"👍":3,"🎉":2}}`, 5, true, false},
+		{"below threshold", http.StatusOK, `{"id":"note1","reactions":{"👍":1}}`, 5, false, false},
+		{"deleted note", http.StatusBadRequest, `{"error":{"code":"NO_SUCH_NOTE","message":"No such note."}}`, 5, false, false},
+		{"server error", http.StatusForbidden, `{"error":{"code":"PERMISSION_DENIED","message":"denied"}}`, 5, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/show":
+					w.WriteHeader(tt.showStatus)
+					w.Write([]byte(tt.showBody))
+				case "/api/notes/create":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &createPayload)
+					w.Write([]byte(`{"createdNote": {"id": "renote1"}}`))
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			promoted, err := repo.PromoteOnReactions(context.Background(), "note1", tt.threshold)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if promoted != tt.expectPromoted {
+				t.Errorf("expected promoted=%v, got %v", tt.expectPromoted, promoted)
+			}
+
+			if !tt.expectPromoted {
+				if createPayload != nil {
+					t.Error("expected no renote to be created")
+				}
+				return
+			}
+			if createPayload["renoteId"] != "note1" {
+				t.Errorf("expected renoteId 'note1', got '%v'", createPayload["renoteId"])
+			}
+			if createPayload["visibility"] != "public" {
+				t.Errorf("expected public visibility, got '%v'", createPayload["visibility"])
+			}
+			if _, ok := createPayload["text"]; ok {
+				t.Errorf("expected pure renote without text, got '%v'", createPayload["text"])
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package misskey
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const TimeFormatMFM = "mfm"
+
+type TimePosition string
+
+const (
+	TimePositionPrefix TimePosition = "prefix"
+	TimePositionSuffix TimePosition = "suffix"
+)
+
+func (p TimePosition) validate() error {
+	switch p {
+	case "", TimePositionPrefix, TimePositionSuffix:
+		return nil
+	}
+	return fmt.Errorf("unknown published time position: %s", p)
+}
+
+func formatPublishedTime(t time.Time, format string, loc *time.Location) string {
+	if format == TimeFormatMFM {
+		return fmt.Sprintf("$[unixtime %d]", t.Unix())
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(format)
+}
+
+func (r *noteRepository) withPublishedTime(text string, note *entity.Note) string {
+	if r.timeFormat == "" || note.PublishedAt == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	stamp := formatPublishedTime(*note.PublishedAt, r.timeFormat, r.timeZone)
+	if r.timePosition == TimePositionPrefix {
+		return stamp + "\n\n" + text
+	}
+	return text + "\n\n" + stamp
+}
@@ -0,0 +1,63 @@
+package misskey
+
+import (
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_RenderText_PublishedTime(t *testing.T) {
+	published := time.Date(2024, 3, 1, 15, 4, 0, 0, time.UTC)
+	jst := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name      string
+		format    string
+		position  TimePosition
+		zone      *time.Location
+		text      string
+		published *time.Time
+		expected  string
+	}{
+		{"disabled", "", "", nil, "Hello", &published, "Hello"},
+		{"mfm suffix by default", TimeFormatMFM, "", nil, "Hello", &published, "Hello\n\n$[unixtime 1709305440]"},
+		{"layout with timezone as prefix", "2006-01-02 15:04 MST", TimePositionPrefix, jst, "Hello", &published, "2024-03-02 00:04 JST\n\nHello"},
+		{"layout in utc", "2006-01-02 15:04", TimePositionSuffix, time.UTC, "Hello", &published, "Hello\n\n2024-03-01 15:04"},
+		{"no publish time", TimeFormatMFM, "", nil, "Hello", nil, "Hello"},
+		{"media only note", TimeFormatMFM, "", nil, "", &published, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{timeFormat: tt.format, timePosition: tt.position, timeZone: tt.zone}
+			note := entity.NewNote(tt.text, entity.VisibilityHome)
+			note.PublishedAt = tt.published
+
+			if got := repo.renderText(note); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTimePosition_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		position  TimePosition
+		expectErr bool
+	}{
+		{"default", "", false},
+		{"prefix", TimePositionPrefix, false},
+		{"suffix", TimePositionSuffix, false},
+		{"unknown", "middle", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.position.validate(); (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
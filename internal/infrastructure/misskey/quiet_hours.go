@@ -0,0 +1,61 @@
+package misskey
+
+import (
+	"fmt"
+	"time"
+)
+
+type QuietHoursPolicy string
+
+const (
+	QuietHoursDefer QuietHoursPolicy = "defer"
+	QuietHoursDrop  QuietHoursPolicy = "drop"
+)
+
+type QuietHours struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+	Policy   QuietHoursPolicy
+}
+
+func (q QuietHours) validate() error {
+	for _, d := range []time.Duration{q.Start, q.End} {
+		if d < 0 || d >= 24*time.Hour {
+			return fmt.Errorf("quiet hours must be within a day: %v", d)
+		}
+	}
+	switch q.Policy {
+	case "", QuietHoursDefer, QuietHoursDrop:
+		return nil
+	}
+	return fmt.Errorf("unknown quiet hours policy: %s", q.Policy)
+}
+
+func (q QuietHours) until(now time.Time) (time.Time, bool) {
+	if q.Start == q.End {
+		return time.Time{}, false
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t := now.In(loc)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	offset := t.Sub(midnight)
+
+	if q.Start < q.End {
+		if offset >= q.Start && offset < q.End {
+			return midnight.Add(q.End), true
+		}
+		return time.Time{}, false
+	}
+	if offset >= q.Start {
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc).Add(q.End), true
+	}
+	if offset < q.End {
+		return midnight.Add(q.End), true
+	}
+	return time.Time{}, false
+}
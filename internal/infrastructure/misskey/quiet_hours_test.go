@@ -0,0 +1,107 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestQuietHours_Until(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, tokyo)
+	}
+
+	tests := []struct {
+		name        string
+		quiet       QuietHours
+		now         time.Time
+		expectQuiet bool
+		expectedEnd time.Time
+	}{
+		{"disabled", QuietHours{}, at(3, 0), false, time.Time{}},
+		{"same-day window inside", QuietHours{Start: 12 * time.Hour, End: 13 * time.Hour, Location: tokyo}, at(12, 30), true, at(13, 0)},
+		{"same-day window outside", QuietHours{Start: 12 * time.Hour, End: 13 * time.Hour, Location: tokyo}, at(13, 0), false, time.Time{}},
+		{"overnight before midnight", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: tokyo}, at(23, 15), true, time.Date(2026, 3, 11, 7, 0, 0, 0, tokyo)},
+		{"overnight after midnight", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: tokyo}, at(2, 0), true, at(7, 0)},
+		{"overnight daytime", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: tokyo}, at(9, 0), false, time.Time{}},
+		{"location applied", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: tokyo}, time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC), true, time.Date(2026, 3, 11, 7, 0, 0, 0, tokyo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := tt.quiet.until(tt.now)
+			if quiet != tt.expectQuiet {
+				t.Fatalf("expected quiet=%v, got %v", tt.expectQuiet, quiet)
+			}
+			if !end.Equal(tt.expectedEnd) {
+				t.Errorf("expected end %v, got %v", tt.expectedEnd, end)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_QuietHours(t *testing.T) {
+	now := time.Now()
+	offset := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	active := QuietHours{
+		Start:    (offset + 23*time.Hour) % (24 * time.Hour),
+		End:      (offset + time.Hour) % (24 * time.Hour),
+		Location: now.Location(),
+	}
+
+	tests := []struct {
+		name            string
+		policy          QuietHoursPolicy
+		expectedOutcome PostOutcome
+		expectRequest   bool
+	}{
+		{"deferred by default", "", PostOutcomeDeferred, true},
+		{"dropped", QuietHoursDrop, PostOutcomeSkippedQuietHours, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			quiet := active
+			quiet.Policy = tt.policy
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+				quietHours:  quiet,
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), entity.NewNote("Night post", entity.VisibilityHome), PostOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Outcome != tt.expectedOutcome {
+				t.Errorf("expected outcome %s, got %s", tt.expectedOutcome, result.Outcome)
+			}
+			if (payload != nil) != tt.expectRequest {
+				t.Fatalf("expected request=%v, got payload %v", tt.expectRequest, payload)
+			}
+			if tt.expectRequest {
+				scheduledAt, _ := payload["scheduledAt"].(float64)
+				if at := time.UnixMilli(int64(scheduledAt)); !at.After(now) || at.Sub(now) > 2*time.Hour {
+					t.Errorf("expected note scheduled for the end of quiet hours, got %v", at)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const (
+	defaultMaxTextLength = 3000
+	quoteLinkPlaceholder = "{link}"
+	truncationEllipsis   = "…"
+)
+
+type QuoteOptions struct {
+	Prefix   string
+	Suffix   string
+	LinkBack bool
+}
+
+func (r *noteRepository) Quote(ctx context.Context, targetNoteID string, note *entity.Note, opts QuoteOptions) (*PostResult, error) {
+	if _, err := r.GetNote(ctx, targetNoteID); err != nil {
+		if isNoSuchNote(err) {
+			return nil, fmt.Errorf("%w [%s]: %w", ErrQuoteTargetNotFound, targetNoteID, err)
+		}
+		return nil, fmt.Errorf("failed to verify quote target: %w", err)
+	}
+
+	quote := *note
+	quote.RenoteID = targetNoteID
+	text, err := formatQuoteText(note.Text, opts, r.noteURL(targetNoteID), r.instanceTextLimit(ctx))
+	if err != nil {
+		return nil, err
+	}
+	quote.Text = text
+
+	return r.PostWithOptions(ctx, &quote, PostOptions{})
+}
+
+// formatQuoteText drops an appended LinkBack line when there is no room
+// left for the body, since the quote already embeds the target note.
+func formatQuoteText(body string, opts QuoteOptions, link string, limit int) (string, error) {
+	prefix := strings.ReplaceAll(opts.Prefix, quoteLinkPlaceholder, link)
+	suffix := strings.ReplaceAll(opts.Suffix, quoteLinkPlaceholder, link)
+	linkBack := ""
+	if opts.LinkBack && !strings.Contains(opts.Prefix+opts.Suffix, quoteLinkPlaceholder) {
+		linkBack = "\n" + link
+	}
+
+	fixed := utf8.RuneCountInString(prefix) + utf8.RuneCountInString(suffix)
+	available := limit - fixed - utf8.RuneCountInString(linkBack)
+	if available <= 0 && body != "" {
+		linkBack = ""
+		available = limit - fixed
+	}
+	if available < 0 || available == 0 && body != "" {
+		return "", fmt.Errorf("%w: quote prefix and suffix are %d characters, limit is %d", ErrTextTooLong, fixed, limit)
+	}
+	return prefix + entity.TruncateRunes(body, available) + suffix + linkBack, nil
+}
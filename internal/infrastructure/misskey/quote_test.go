@@ -0,0 +1,128 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestFormatQuoteText(t *testing.T) {
+	link := "https://example.tld/notes/abc"
+
+	tests := []struct {
+		name      string
+		body      string
+		opts      QuoteOptions
+		limit     int
+		expected  string
+		expectErr error
+	}{
+		{"body only", "news", QuoteOptions{}, 100, "news", nil},
+		{"prefix", "news", QuoteOptions{Prefix: "Updated: "}, 100, "Updated: news", nil},
+		{"link back appended", "news", QuoteOptions{LinkBack: true}, 100, "news\n" + link, nil},
+		{"link placeholder in suffix", "news", QuoteOptions{Suffix: " ({link})", LinkBack: true}, 100, "news (" + link + ")", nil},
+		{"body truncated to fit", "longbodytext", QuoteOptions{Prefix: "Up: "}, 10, "Up: longb…", nil},
+		{"link back dropped when it leaves no room", "news", QuoteOptions{Prefix: "Up: ", LinkBack: true}, 20, "Up: news", nil},
+		{"prefix and suffix too long", "news", QuoteOptions{Prefix: "Updated: ", Suffix: " ({link})"}, 20, "", ErrTextTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatQuoteText(tt.body, tt.opts, link, tt.limit)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+			if utf8.RuneCountInString(got) > tt.limit {
+				t.Errorf("expected at most %d characters, got %d", tt.limit, utf8.RuneCountInString(got))
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Quote(t *testing.T) {
+	tests := []struct {
+		name         string
+		targetExists bool
+		expectErr    error
+		expectCreate bool
+	}{
+		{"existing target", true, nil, true},
+		{"missing target", false, ErrQuoteTargetNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createPayload map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/show":
+					if !tt.targetExists {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error":{"code":"NO_SUCH_NOTE","message":"No such note."}}`))
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"id":"target1","text":"original"}`))
+				case "/api/notes/create":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &createPayload)
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"createdNote": {"id": "quote1"}}`))
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, 10*time.Second),
+			}
+
+			note := entity.NewNote("follow-up", entity.VisibilityHome)
+			result, err := repo.Quote(context.Background(), "target1", note, QuoteOptions{Prefix: "Updated: ", LinkBack: true})
+
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.expectCreate {
+				if createPayload != nil {
+					t.Error("expected no note to be created")
+				}
+				return
+			}
+
+			if result.NoteID != "quote1" {
+				t.Errorf("expected note ID 'quote1', got '%s'", result.NoteID)
+			}
+			if createPayload["renoteId"] != "target1" {
+				t.Errorf("expected renoteId 'target1', got '%v'", createPayload["renoteId"])
+			}
+			text, _ := createPayload["text"].(string)
+			if !strings.HasPrefix(text, "Updated: follow-up") || !strings.HasSuffix(text, server.URL+"/notes/target1") {
+				t.Errorf("unexpected quote text: '%s'", text)
+			}
+			if note.RenoteID != "" {
+				t.Error("expected original note to be left unchanged")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package misskey
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const adaptiveDecreaseFactor = 2
+
+type adaptiveRate struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+func newAdaptiveRate(refillInterval, minInterval, maxInterval time.Duration) (*adaptiveRate, error) {
+	if minInterval == 0 {
+		minInterval = refillInterval / 10
+	}
+	if maxInterval == 0 {
+		maxInterval = refillInterval * 10
+	}
+	if minInterval <= 0 || maxInterval < minInterval {
+		return nil, fmt.Errorf("invalid adaptive rate limit bounds: min %v, max %v", minInterval, maxInterval)
+	}
+	return &adaptiveRate{minInterval: minInterval, maxInterval: maxInterval}, nil
+}
+
+func (a *adaptiveRate) clamp(d time.Duration) time.Duration {
+	if d > a.maxInterval {
+		return a.maxInterval
+	}
+	return max(d, a.minInterval)
+}
+
+func (a *adaptiveRate) relaxed(interval time.Duration) time.Duration {
+	rate := 1/interval.Seconds() + 1/a.maxInterval.Seconds()
+	return a.clamp(time.Duration(float64(time.Second) / rate))
+}
+
+func (a *adaptiveRate) tightened(interval time.Duration) time.Duration {
+	return a.clamp(interval * adaptiveDecreaseFactor)
+}
+
+func isThrottled(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Code == errCodeRateLimitExceeded
+}
+
+func isOverloaded(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+func (rl *rateLimiter) observe(err error) {
+	throttled := isThrottled(err) || isOverloaded(err)
+	if err != nil && !throttled {
+		return
+	}
+
+	rl.mu.Lock()
+	if rl.adaptive == nil {
+		rl.mu.Unlock()
+		return
+	}
+	previous := rl.refillRate
+	if throttled {
+		rl.refillRate = rl.adaptive.tightened(previous)
+	} else {
+		rl.refillRate = rl.adaptive.relaxed(previous)
+	}
+	current := rl.refillRate
+	rl.mu.Unlock()
+
+	if throttled && current != previous {
+		loggerOrDefault(rl.logger).Printf("Warning: Throttled or overloaded server, slowing rate limiter from one post per %v to one per %v", previous, current)
+	}
+}
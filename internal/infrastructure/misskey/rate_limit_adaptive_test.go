@@ -0,0 +1,169 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestAdaptiveRate_Simulation(t *testing.T) {
+	tests := []struct {
+		name         string
+		start        time.Duration
+		realInterval time.Duration
+	}{
+		{"starts too fast", time.Second, 30 * time.Second},
+		{"starts too slow", 2 * time.Minute, 30 * time.Second},
+		{"tight instance", 10 * time.Second, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptive, err := newAdaptiveRate(tt.start, time.Second, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			rl := newRateLimiter(1, tt.start)
+			rl.adaptive = adaptive
+
+			const steps, measured = 600, 200
+			var now, windowStart time.Duration
+			lastAccepted := -tt.realInterval
+			throttled, accepted := 0, 0
+
+			for i := 0; i < steps; i++ {
+				if i == steps-measured {
+					windowStart = now
+				}
+				now += time.Duration(float64(time.Second) / rl.stats().EffectiveRate)
+
+				var err error
+				if now-lastAccepted < tt.realInterval {
+					err = &APIError{StatusCode: http.StatusTooManyRequests, Code: errCodeRateLimitExceeded}
+				} else {
+					lastAccepted = now
+				}
+				rl.observe(err)
+
+				if i >= steps-measured {
+					if err != nil {
+						throttled++
+					} else {
+						accepted++
+					}
+				}
+			}
+
+			utilization := float64(accepted) * tt.realInterval.Seconds() / (now - windowStart).Seconds()
+			if throttled > measured/4 {
+				t.Errorf("expected under a quarter of posts throttled after convergence, got %d/%d", throttled, measured)
+			}
+			if utilization < 0.5 {
+				t.Errorf("expected at least half of the real limit to be used, got %.2f", utilization)
+			}
+		})
+	}
+}
+
+func TestAdaptiveRate_Bounds(t *testing.T) {
+	adaptive, err := newAdaptiveRate(10*time.Second, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adaptive.minInterval != time.Second || adaptive.maxInterval != 100*time.Second {
+		t.Errorf("expected default bounds 1s..100s, got %v..%v", adaptive.minInterval, adaptive.maxInterval)
+	}
+
+	interval := 10 * time.Second
+	for i := 0; i < 10; i++ {
+		interval = adaptive.tightened(interval)
+	}
+	if interval != adaptive.maxInterval {
+		t.Errorf("expected interval capped at %v, got %v", adaptive.maxInterval, interval)
+	}
+	for i := 0; i < 200; i++ {
+		interval = adaptive.relaxed(interval)
+	}
+	if interval != adaptive.minInterval {
+		t.Errorf("expected interval floored at %v, got %v", adaptive.minInterval, interval)
+	}
+
+	if _, err := newAdaptiveRate(10*time.Second, time.Minute, time.Second); err == nil {
+		t.Error("expected error for inverted bounds")
+	}
+}
+
+func TestNoteRepository_Post_AdaptiveTightensOn429(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"code":"RATE_LIMIT_EXCEEDED"}}`))
+			return
+		}
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	created, err := NewNoteRepository(Config{
+		Host:           server.URL,
+		AuthToken:      "test-token",
+		MaxPermits:     5,
+		RefillInterval: 10 * time.Second,
+		MaxRetries:     1,
+		RetryBackoff:   time.Millisecond,
+		Adaptive:       true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo := created.(*noteRepository)
+
+	if rate := repo.Stats().RateLimiter.EffectiveRate; rate != 0.1 {
+		t.Fatalf("expected initial rate 0.1/s, got %v", rate)
+	}
+	if err := repo.Post(context.Background(), entity.NewNote("Hello", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rate := repo.Stats().RateLimiter.EffectiveRate
+	if rate >= 0.1 || rate <= 0.05 {
+		t.Errorf("expected rate halved by the 429 then relaxed by the success, got %v", rate)
+	}
+}
+
+func TestRateLimiter_ObserveSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected time.Duration
+	}{
+		{"success relaxes", nil, 10 * time.Second * 100 / 110},
+		{"429 tightens", &APIError{StatusCode: http.StatusTooManyRequests}, 20 * time.Second},
+		{"rate limit code tightens", &APIError{StatusCode: http.StatusBadRequest, Code: errCodeRateLimitExceeded}, 20 * time.Second},
+		{"5xx tightens", &APIError{StatusCode: http.StatusServiceUnavailable}, 20 * time.Second},
+		{"client error ignored", &APIError{StatusCode: http.StatusBadRequest, Code: "INVALID_PARAM"}, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptive, err := newAdaptiveRate(10*time.Second, time.Second, 100*time.Second)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			rl := newRateLimiter(1, 10*time.Second)
+			rl.adaptive = adaptive
+
+			rl.observe(tt.err)
+			if got := rl.refillRate; got.Round(time.Millisecond) != tt.expected.Round(time.Millisecond) {
+				t.Errorf("expected refill interval %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package misskey
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const rateLimitRefreshInterval = time.Hour
+
+type rateLimitTuning struct {
+	mu           sync.Mutex
+	configuredAt time.Time
+}
+
+type advertisedRateLimit struct {
+	limit          int
+	remaining      int
+	refillInterval time.Duration
+}
+
+func parseRateLimitHeaders(h http.Header) (advertisedRateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return advertisedRateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 || remaining >= limit {
+		return advertisedRateLimit{}, false
+	}
+	clearAfter, err := strconv.ParseFloat(h.Get("X-RateLimit-Clear"), 64)
+	if err != nil || clearAfter <= 0 {
+		return advertisedRateLimit{}, false
+	}
+
+	refill := time.Duration(clearAfter * float64(time.Second) / float64(limit-remaining))
+	return advertisedRateLimit{limit: limit, remaining: remaining, refillInterval: refill}, true
+}
+
+// observeRateLimit applies X-RateLimit-* headers to the account whose token
+// made the request; other accounts have their own quota.
+func (r *noteRepository) observeRateLimit(account *postingAccount, endpoint string, h http.Header) {
+	account.recordServerRateLimit(endpoint, h)
+	if !r.autoConfigureRateLimit || endpoint != "notes/create" {
+		return
+	}
+	advertised, ok := parseRateLimitHeaders(h)
+	if !ok {
+		return
+	}
+
+	account.rateLimitTuning.mu.Lock()
+	defer account.rateLimitTuning.mu.Unlock()
+
+	now := time.Now()
+	if !account.rateLimitTuning.configuredAt.IsZero() && now.Sub(account.rateLimitTuning.configuredAt) < rateLimitRefreshInterval {
+		return
+	}
+	account.rateLimitTuning.configuredAt = now
+
+	account.rateLimiter.reconfigure(advertised.limit, advertised.refillInterval, advertised.remaining)
+	r.logf("Configured rate limiter from server limits for notes/create: %d permits, refill every %v", advertised.limit, advertised.refillInterval)
+}
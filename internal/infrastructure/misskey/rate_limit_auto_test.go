@@ -0,0 +1,124 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		expectOK     bool
+		expectLimit  int
+		expectRefill time.Duration
+	}{
+		{"advertised", map[string]string{"X-RateLimit-Limit": "300", "X-RateLimit-Remaining": "299", "X-RateLimit-Clear": "12"}, true, 300, 12 * time.Second},
+		{"fractional clear", map[string]string{"X-RateLimit-Limit": "10", "X-RateLimit-Remaining": "6", "X-RateLimit-Clear": "2.0"}, true, 10, 500 * time.Millisecond},
+		{"missing headers", map[string]string{}, false, 0, 0},
+		{"full bucket", map[string]string{"X-RateLimit-Limit": "10", "X-RateLimit-Remaining": "10", "X-RateLimit-Clear": "0"}, false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			got, ok := parseRateLimitHeaders(h)
+			if ok != tt.expectOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectOK, ok)
+			}
+			if ok && (got.limit != tt.expectLimit || got.refillInterval != tt.expectRefill) {
+				t.Errorf("expected limit=%d refill=%v, got limit=%d refill=%v", tt.expectLimit, tt.expectRefill, got.limit, got.refillInterval)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_AutoConfigureRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "20")
+		w.Header().Set("X-RateLimit-Remaining", "18")
+		w.Header().Set("X-RateLimit-Clear", "4")
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		expectPermits int
+		expectRefill  time.Duration
+	}{
+		{"disabled keeps config", false, 3, 10 * time.Second},
+		{"enabled adopts server limits", true, 20, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{
+				host:                   server.URL,
+				authToken:              "test-token",
+				client:                 &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:            newRateLimiter(3, 10*time.Second),
+				autoConfigureRateLimit: tt.enabled,
+			}
+
+			if err := repo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityHome)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if repo.rateLimiter.maxPermits != tt.expectPermits || repo.rateLimiter.refillRate != tt.expectRefill {
+				t.Errorf("expected %d permits every %v, got %d every %v", tt.expectPermits, tt.expectRefill, repo.rateLimiter.maxPermits, repo.rateLimiter.refillRate)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_RateLimitHeadersPerAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "20")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Clear", "40")
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	first := newPostingAccount("a", newRateLimiter(3, 10*time.Second), 0, 0)
+	second := newPostingAccount("b", newRateLimiter(3, 10*time.Second), 0, 0)
+	repo := &noteRepository{
+		host:                   server.URL,
+		client:                 &http.Client{Timeout: 30 * time.Second},
+		accounts:               []*postingAccount{first, second},
+		autoConfigureRateLimit: true,
+	}
+
+	if err := repo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.rateLimiter.maxPermits != 20 || first.rateLimiter.refillRate != 2*time.Second {
+		t.Errorf("expected posting account to adopt server limits, got %d every %v", first.rateLimiter.maxPermits, first.rateLimiter.refillRate)
+	}
+	if second.rateLimiter.maxPermits != 3 || second.rateLimiter.refillRate != 10*time.Second {
+		t.Errorf("expected other account to keep its limits, got %d every %v", second.rateLimiter.maxPermits, second.rateLimiter.refillRate)
+	}
+	if remaining, _, ok := first.serverRateLimit.status(time.Now()); !ok || remaining != 0 {
+		t.Errorf("expected posting account quota to be exhausted, got %d (observed %v)", remaining, ok)
+	}
+	if _, _, ok := second.serverRateLimit.status(time.Now()); ok {
+		t.Error("expected other account to have no server quota recorded")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome)); err != nil {
+		t.Errorf("expected the other account to post without waiting, got %v", err)
+	}
+}
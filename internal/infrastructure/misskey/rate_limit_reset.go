@@ -0,0 +1,87 @@
+package misskey
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type rateLimitResetInfo struct {
+	ResetMs  *float64 `json:"resetMs"`
+	ResetSec *float64 `json:"resetSec"`
+}
+
+func parseRateLimitResetBody(body []byte) (time.Duration, bool) {
+	var errBody struct {
+		rateLimitResetInfo
+		Error struct {
+			rateLimitResetInfo
+			Info rateLimitResetInfo `json:"info"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		return 0, false
+	}
+
+	for _, info := range []rateLimitResetInfo{errBody.Error.Info, errBody.Error.rateLimitResetInfo, errBody.rateLimitResetInfo} {
+		if info.ResetMs != nil && *info.ResetMs >= 0 {
+			return time.Duration(*info.ResetMs * float64(time.Millisecond)), true
+		}
+		if info.ResetSec != nil && *info.ResetSec >= 0 {
+			return time.Duration(*info.ResetSec * float64(time.Second)), true
+		}
+	}
+	return 0, false
+}
+
+func parseRetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func rateLimitResetAfter(h http.Header, body []byte) (time.Duration, bool) {
+	if wait, ok := parseRateLimitResetBody(body); ok {
+		return wait, true
+	}
+	return parseRetryAfter(h, time.Now())
+}
+
+func (r *noteRepository) penalizeRateLimit(account *postingAccount, endpoint string, wait time.Duration, known bool) time.Duration {
+	limit := &account.serverRateLimit
+	limit.mu.Lock()
+	defer limit.mu.Unlock()
+
+	limit.penalties++
+	if !known {
+		wait = r.retryBackoffStrategy().Next(limit.penalties, limit.lastPenalty)
+	}
+	limit.lastPenalty = wait
+	r.logf("Warning: %s rate limited by server, next request allowed in %v", endpoint, wait)
+
+	if endpoint == "notes/create" {
+		limit.observed = true
+		limit.remaining = 0
+		limit.reset = time.Now().Add(wait)
+	}
+	return wait
+}
+
+func (a *postingAccount) clearRateLimitPenalty() {
+	a.serverRateLimit.mu.Lock()
+	defer a.serverRateLimit.mu.Unlock()
+	a.serverRateLimit.penalties = 0
+	a.serverRateLimit.lastPenalty = 0
+}
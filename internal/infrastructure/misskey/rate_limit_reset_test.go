@@ -0,0 +1,94 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestRateLimitResetAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		body       string
+		expected   time.Duration
+		expectOK   bool
+	}{
+		{"resetMs in error info", "", `{"error":{"code":"RATE_LIMIT_EXCEEDED","info":{"resetMs":1250,"resetSec":2}}}`, 1250 * time.Millisecond, true},
+		{"resetMs preferred over header", "3", `{"error":{"code":"RATE_LIMIT_EXCEEDED","info":{"resetMs":1250}}}`, 1250 * time.Millisecond, true},
+		{"resetMs at top level", "", `{"resetMs":400}`, 400 * time.Millisecond, true},
+		{"resetSec only", "", `{"error":{"info":{"resetSec":2}}}`, 2 * time.Second, true},
+		{"header fallback", "3", `{"error":{"code":"RATE_LIMIT_EXCEEDED"}}`, 3 * time.Second, true},
+		{"header with non-json body", "1", `Too Many Requests`, time.Second, true},
+		{"negative header", "-1", ``, 0, false},
+		{"nothing provided", "", `{"error":{"code":"RATE_LIMIT_EXCEEDED"}}`, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.retryAfter != "" {
+				h.Set("Retry-After", tt.retryAfter)
+			}
+			got, ok := rateLimitResetAfter(h, []byte(tt.body))
+			if ok != tt.expectOK || got != tt.expected {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.expectOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := http.Header{}
+	h.Set("Retry-After", now.Add(5*time.Second).Format(http.TimeFormat))
+
+	got, ok := parseRetryAfter(h, now)
+	if !ok || got != 5*time.Second {
+		t.Errorf("expected 5s, got %v (ok=%v)", got, ok)
+	}
+}
+
+func TestNoteRepository_Post_RetryUsesResetMs(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"code":"RATE_LIMIT_EXCEEDED","message":"Rate limit exceeded.","info":{"resetMs":50}}}`))
+			return
+		}
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := newRetryTestRepository(server.URL, 1, 10*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := repo.Post(ctx, entity.NewNote("Hello", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected retry after ~50ms, took %v", elapsed)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts.Load())
+	}
+
+	remaining, _, err := repo.RateLimitStatus(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected status error: %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("expected penalty to have expired, got remaining %d", remaining)
+	}
+}
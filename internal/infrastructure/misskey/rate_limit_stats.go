@@ -0,0 +1,62 @@
+package misskey
+
+import "time"
+
+type RateLimiterStats struct {
+	Permits       int
+	MaxPermits    int
+	Waits         uint64
+	BlockedWaits  uint64
+	BlockedTime   time.Duration
+	EffectiveRate float64
+}
+
+type Stats struct {
+	RateLimiter RateLimiterStats
+}
+
+func (rl *rateLimiter) recordBlocked(start time.Time) {
+	rl.blockedNanos.Add(int64(time.Since(start)))
+}
+
+func (rl *rateLimiter) stats() RateLimiterStats {
+	rl.mu.Lock()
+	permits, maxPermits, refillRate := rl.permits, rl.maxPermits, rl.refillRate
+	if elapsed := rl.clock().Sub(rl.lastRefill); elapsed > 0 && rl.refillRate > 0 {
+		permits = min(permits+int(elapsed/rl.refillRate), maxPermits)
+	}
+	rl.mu.Unlock()
+
+	var effectiveRate float64
+	if refillRate > 0 {
+		effectiveRate = 1 / refillRate.Seconds()
+	}
+
+	return RateLimiterStats{
+		Permits:       permits,
+		MaxPermits:    maxPermits,
+		Waits:         rl.waits.Load(),
+		BlockedWaits:  rl.blockedWaits.Load(),
+		BlockedTime:   time.Duration(rl.blockedNanos.Load()),
+		EffectiveRate: effectiveRate,
+	}
+}
+
+func (s RateLimiterStats) add(other RateLimiterStats) RateLimiterStats {
+	return RateLimiterStats{
+		Permits:       s.Permits + other.Permits,
+		MaxPermits:    s.MaxPermits + other.MaxPermits,
+		Waits:         s.Waits + other.Waits,
+		BlockedWaits:  s.BlockedWaits + other.BlockedWaits,
+		BlockedTime:   s.BlockedTime + other.BlockedTime,
+		EffectiveRate: s.EffectiveRate + other.EffectiveRate,
+	}
+}
+
+func (r *noteRepository) Stats() Stats {
+	var limiter RateLimiterStats
+	for _, account := range r.postingAccounts() {
+		limiter = limiter.add(account.rateLimiter.stats())
+	}
+	return Stats{RateLimiter: limiter}
+}
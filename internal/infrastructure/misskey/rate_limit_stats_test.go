@@ -0,0 +1,52 @@
+package misskey
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Stats(t *testing.T) {
+	rl := newRateLimiter(2, 30*time.Millisecond)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats := rl.stats()
+	if stats.Waits != 3 {
+		t.Errorf("expected 3 waits, got %d", stats.Waits)
+	}
+	if stats.BlockedWaits != 1 {
+		t.Errorf("expected 1 blocked wait, got %d", stats.BlockedWaits)
+	}
+	if stats.BlockedTime <= 0 || stats.BlockedTime > time.Second {
+		t.Errorf("expected blocked time around one refill interval, got %v", stats.BlockedTime)
+	}
+	if stats.Permits != 0 || stats.MaxPermits != 2 {
+		t.Errorf("expected 0/2 permits, got %d/%d", stats.Permits, stats.MaxPermits)
+	}
+
+	time.Sleep(70 * time.Millisecond)
+	if permits := rl.stats().Permits; permits != 2 {
+		t.Errorf("expected permits to reflect refill, got %d", permits)
+	}
+}
+
+func TestNoteRepository_Stats(t *testing.T) {
+	repo := &noteRepository{
+		accounts: []*postingAccount{
+			{authToken: "token-a", rateLimiter: newRateLimiter(3, time.Hour)},
+			{authToken: "token-b", rateLimiter: newRateLimiter(2, time.Hour)},
+		},
+	}
+	repo.accounts[0].rateLimiter.Wait(context.Background())
+
+	stats := repo.Stats().RateLimiter
+	if stats.Permits != 4 || stats.MaxPermits != 5 || stats.Waits != 1 {
+		t.Errorf("expected aggregated stats 4/5 with 1 wait, got %+v", stats)
+	}
+}
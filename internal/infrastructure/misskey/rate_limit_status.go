@@ -0,0 +1,104 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type serverRateLimit struct {
+	mu        sync.Mutex
+	observed  bool
+	remaining int
+	reset     time.Time
+
+	penalties   int
+	lastPenalty time.Duration
+}
+
+func parseServerRateLimit(h http.Header, now time.Time) (int, time.Time, bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 {
+		return 0, time.Time{}, false
+	}
+	resetAfter, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset"), 64)
+	if err != nil {
+		resetAfter, err = strconv.ParseFloat(h.Get("X-RateLimit-Clear"), 64)
+	}
+	if err != nil || resetAfter < 0 {
+		return 0, time.Time{}, false
+	}
+	return remaining, now.Add(time.Duration(resetAfter * float64(time.Second))), true
+}
+
+func (a *postingAccount) recordServerRateLimit(endpoint string, h http.Header) {
+	if endpoint != "notes/create" {
+		return
+	}
+	remaining, reset, ok := parseServerRateLimit(h, time.Now())
+	if !ok {
+		return
+	}
+
+	a.serverRateLimit.mu.Lock()
+	defer a.serverRateLimit.mu.Unlock()
+	a.serverRateLimit.observed = true
+	a.serverRateLimit.remaining = remaining
+	a.serverRateLimit.reset = reset
+}
+
+func (s *serverRateLimit) status(now time.Time) (int, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.observed {
+		return 0, time.Time{}, false
+	}
+	if !now.Before(s.reset) {
+		return s.remaining + 1, time.Time{}, true
+	}
+	return s.remaining, s.reset, true
+}
+
+// RateLimitStatus reports the notes/create quota of the posting account with
+// the most quota left.
+func (r *noteRepository) RateLimitStatus(ctx context.Context) (int, time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	observed := false
+	var bestRemaining int
+	var bestReset time.Time
+	now := time.Now()
+	for _, account := range r.postingAccounts() {
+		remaining, reset, ok := account.serverRateLimit.status(now)
+		if !ok || observed && remaining <= bestRemaining {
+			continue
+		}
+		observed = true
+		bestRemaining, bestReset = remaining, reset
+	}
+	if !observed {
+		return 0, time.Time{}, fmt.Errorf("instance has not reported rate limits for notes/create: %w", errors.ErrUnsupported)
+	}
+	return bestRemaining, bestReset, nil
+}
+
+func (r *noteRepository) waitServerRateLimit(ctx context.Context, account *postingAccount) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	remaining, reset, ok := account.serverRateLimit.status(time.Now())
+	if !ok || remaining > 0 {
+		return nil
+	}
+
+	wait := time.Until(reset)
+	r.logf("Server reports no remaining notes/create quota, waiting %v before posting", wait)
+	return sleepContext(ctx, wait)
+}
@@ -0,0 +1,88 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestParseServerRateLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		headers           map[string]string
+		expectOK          bool
+		expectedRemaining int
+		expectedReset     time.Time
+	}{
+		{"reset header", map[string]string{"X-RateLimit-Remaining": "2", "X-RateLimit-Reset": "1.5"}, true, 2, now.Add(1500 * time.Millisecond)},
+		{"clear fallback", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Clear": "30"}, true, 0, now.Add(30 * time.Second)},
+		{"missing remaining", map[string]string{"X-RateLimit-Reset": "1"}, false, 0, time.Time{}},
+		{"missing reset", map[string]string{"X-RateLimit-Remaining": "1"}, false, 0, time.Time{}},
+		{"no headers", nil, false, 0, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			remaining, reset, ok := parseServerRateLimit(h, now)
+			if ok != tt.expectOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectOK, ok)
+			}
+			if remaining != tt.expectedRemaining || !reset.Equal(tt.expectedReset) {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.expectedRemaining, tt.expectedReset, remaining, reset)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_RateLimitStatus(t *testing.T) {
+	posts := 0
+	var postTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		postTimes = append(postTimes, time.Now())
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0.2")
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(10, 10*time.Second),
+	}
+
+	if _, _, err := repo.RateLimitStatus(context.Background()); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported before any observation, got %v", err)
+	}
+
+	if err := repo.Post(context.Background(), entity.NewNote("first", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remaining, reset, err := repo.RateLimitStatus(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remaining != 0 || reset.IsZero() {
+		t.Errorf("expected exhausted quota with a reset time, got %d, %v", remaining, reset)
+	}
+
+	if err := repo.Post(context.Background(), entity.NewNote("second", entity.VisibilityHome)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gap := postTimes[1].Sub(postTimes[0]); gap < 150*time.Millisecond {
+		t.Errorf("expected second post to wait for the server reset, posted after %v", gap)
+	}
+}
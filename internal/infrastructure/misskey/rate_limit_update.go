@@ -0,0 +1,32 @@
+package misskey
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetRateLimit changes the local rate limit of every posting account at
+// runtime. Permits already spent stay spent, and adaptive bounds derived
+// from the old refill interval are derived again from the new one.
+func (r *noteRepository) SetRateLimit(maxPermits int, refillInterval time.Duration) error {
+	if maxPermits <= 0 || refillInterval <= 0 {
+		return fmt.Errorf("%w: %d permits every %v", ErrInvalidRateLimit, maxPermits, refillInterval)
+	}
+	var adaptive *adaptiveRate
+	if r.adaptive {
+		var err error
+		if adaptive, err = newAdaptiveRate(refillInterval, r.adaptiveMinInterval, r.adaptiveMaxInterval); err != nil {
+			return err
+		}
+	}
+	for _, account := range r.postingAccounts() {
+		rl := account.rateLimiter
+		rl.reconfigure(maxPermits, refillInterval, rl.stats().Permits)
+		if adaptive != nil {
+			rl.mu.Lock()
+			rl.adaptive = adaptive
+			rl.mu.Unlock()
+		}
+	}
+	return nil
+}
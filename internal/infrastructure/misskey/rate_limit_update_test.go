@@ -0,0 +1,85 @@
+package misskey
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_SetRateLimit(t *testing.T) {
+	tests := []struct {
+		name            string
+		spent           int
+		maxPermits      int
+		refillInterval  time.Duration
+		expectErr       error
+		expectedPermits int
+		expectedMax     int
+	}{
+		{"raise limit keeps spent permits", 2, 10, time.Second, nil, 1, 10},
+		{"lower limit clamps permits", 0, 2, time.Second, nil, 2, 2},
+		{"invalid permits", 0, 0, time.Second, ErrInvalidRateLimit, 3, 3},
+		{"invalid interval", 0, 5, 0, ErrInvalidRateLimit, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			limiter := newRateLimiter(3, time.Hour)
+			limiter.now = func() time.Time { return now }
+			limiter.lastRefill = now
+			limiter.permits -= tt.spent
+			repo := &noteRepository{rateLimiter: limiter}
+
+			err := repo.SetRateLimit(tt.maxPermits, tt.refillInterval)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			stats := limiter.stats()
+			if stats.Permits != tt.expectedPermits || stats.MaxPermits != tt.expectedMax {
+				t.Errorf("expected %d/%d permits, got %d/%d", tt.expectedPermits, tt.expectedMax, stats.Permits, stats.MaxPermits)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_SetRateLimit_Adaptive(t *testing.T) {
+	throttled := &APIError{StatusCode: http.StatusTooManyRequests, Code: errCodeRateLimitExceeded}
+	tests := []struct {
+		name        string
+		minInterval time.Duration
+		maxInterval time.Duration
+		observed    error
+		expected    time.Duration
+	}{
+		{"derived bounds follow new interval on success", 0, 0, nil, time.Second * 10 / 11},
+		{"derived bounds follow new interval when throttled", 0, 0, throttled, 2 * time.Second},
+		{"explicit bounds kept", 30 * time.Second, 2 * time.Hour, nil, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptive, err := newAdaptiveRate(time.Hour, tt.minInterval, tt.maxInterval)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			limiter := newRateLimiter(1, time.Hour)
+			limiter.adaptive = adaptive
+			repo := &noteRepository{
+				rateLimiter:         limiter,
+				adaptive:            true,
+				adaptiveMinInterval: tt.minInterval,
+				adaptiveMaxInterval: tt.maxInterval,
+			}
+
+			if err := repo.SetRateLimit(1, time.Second); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			limiter.observe(tt.observed)
+			if limiter.refillRate != tt.expected {
+				t.Errorf("expected refill interval %v, got %v", tt.expected, limiter.refillRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package misskey
+
+import (
+	"context"
+	"time"
+)
+
+type rateOverrideKey struct{}
+
+// WithRateOverride returns a context whose posts draw from their own token
+// bucket of maxTokens refilled every refill instead of the account's bucket.
+// Posts sharing the returned context share that bucket. Priority posts still
+// bypass rate limiting entirely, and the server-reported quota is honored
+// either way. Non-positive values leave the context unchanged.
+func WithRateOverride(ctx context.Context, maxTokens int, refill time.Duration) context.Context {
+	if maxTokens <= 0 || refill <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, rateOverrideKey{}, newRateLimiter(maxTokens, refill))
+}
+
+func postingLimiter(ctx context.Context, account *postingAccount) *rateLimiter {
+	if override, ok := ctx.Value(rateOverrideKey{}).(*rateLimiter); ok {
+		return override
+	}
+	return account.rateLimiter
+}
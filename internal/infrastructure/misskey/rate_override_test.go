@@ -0,0 +1,54 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_RateOverride(t *testing.T) {
+	tests := []struct {
+		name          string
+		withOverride  func(context.Context) context.Context
+		posts         int
+		expectBlocked bool
+	}{
+		{"default bucket allows burst", func(ctx context.Context) context.Context { return ctx }, 3, false},
+		{"tighter override throttles", func(ctx context.Context) context.Context { return WithRateOverride(ctx, 1, 200*time.Millisecond) }, 2, true},
+		{"looser override allows larger burst", func(ctx context.Context) context.Context { return WithRateOverride(ctx, 5, time.Hour) }, 5, false},
+		{"invalid override ignored", func(ctx context.Context) context.Context { return WithRateOverride(ctx, 0, 0) }, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: newRateLimiter(3, time.Hour),
+			}
+
+			ctx, cancel := context.WithTimeout(tt.withOverride(context.Background()), 2*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			for i := 0; i < tt.posts; i++ {
+				if err := repo.Post(ctx, entity.NewNote("burst", entity.VisibilityHome)); err != nil {
+					t.Fatalf("post %d: unexpected error: %v", i, err)
+				}
+			}
+			if blocked := time.Since(start) >= 150*time.Millisecond; blocked != tt.expectBlocked {
+				t.Errorf("expected blocked=%v, took %v", tt.expectBlocked, time.Since(start))
+			}
+		})
+	}
+}
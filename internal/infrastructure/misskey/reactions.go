@@ -0,0 +1,68 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const acknowledgePageSize = 100
+
+type mentionCursor struct {
+	mu     sync.Mutex
+	lastID string
+}
+
+func (r *noteRepository) React(ctx context.Context, noteID, reaction string) error {
+	params := map[string]interface{}{"noteId": noteID, "reaction": reaction}
+	if err := r.call(ctx, "notes/reactions/create", params, nil); err != nil {
+		return fmt.Errorf("failed to react to note [%s]: %w", noteID, err)
+	}
+	return nil
+}
+
+func (r *noteRepository) AcknowledgeMentions(ctx context.Context, sinceID, reaction string) (int, error) {
+	r.mentions.mu.Lock()
+	defer r.mentions.mu.Unlock()
+
+	cursor := sinceID
+	if cursor == "" {
+		cursor = r.mentions.lastID
+	}
+
+	acknowledged := 0
+	for {
+		notifications, err := r.ListNotifications(ctx, NotificationQuery{SinceID: cursor, Limit: acknowledgePageSize})
+		if err != nil {
+			return acknowledged, err
+		}
+
+		for _, n := range notifications {
+			if n.ID > cursor {
+				cursor = n.ID
+			}
+			if n.Type != NotificationMention || n.NoteID == "" {
+				continue
+			}
+
+			if err := r.React(ctx, n.NoteID, reaction); err != nil {
+				if hasErrorCode(err, errCodeAlreadyReacted) {
+					continue
+				}
+				return acknowledged, err
+			}
+			acknowledged++
+		}
+		r.mentions.lastID = cursor
+		r.saveState(stateNamespaceCursors, stateKeyMentions, cursor)
+
+		if len(notifications) < acknowledgePageSize {
+			break
+		}
+	}
+
+	if acknowledged > 0 {
+		r.logf("Acknowledged %d mention(s) with %s", acknowledged, reaction)
+	}
+	return acknowledged, nil
+}
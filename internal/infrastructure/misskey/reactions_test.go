@@ -0,0 +1,78 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_AcknowledgeMentions(t *testing.T) {
+	var reacted []string
+	var sinceIDs []interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+
+		switch r.URL.Path {
+		case "/api/i/notifications":
+			sinceIDs = append(sinceIDs, payload["sinceId"])
+			if payload["sinceId"] == "a3" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[
+				{"id": "a3", "type": "mention", "note": {"id": "note3"}},
+				{"id": "a2", "type": "reaction", "note": {"id": "note2"}},
+				{"id": "a1", "type": "mention", "note": {"id": "note1"}}
+			]`))
+		case "/api/i":
+			w.Write([]byte(`{"id": "bot"}`))
+		case "/api/notes/reactions/create":
+			if payload["reaction"] != "👀" {
+				t.Errorf("unexpected reaction: %v", payload["reaction"])
+			}
+			if payload["noteId"] == "note1" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"code": "ALREADY_REACTED"}}`))
+				return
+			}
+			reacted = append(reacted, payload["noteId"].(string))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	ctx := context.Background()
+
+	count, err := repo.AcknowledgeMentions(ctx, "a0", "👀")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 || len(reacted) != 1 || reacted[0] != "note3" {
+		t.Errorf("expected only note3 to be acknowledged, got count=%d reacted=%v", count, reacted)
+	}
+
+	count, err = repo.AcknowledgeMentions(ctx, "", "👀")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no new mentions, got %d", count)
+	}
+	if len(sinceIDs) != 2 || sinceIDs[1] != "a3" {
+		t.Errorf("expected second sweep to resume from a3, got %v", sinceIDs)
+	}
+}
@@ -0,0 +1,84 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const recentNotesPageSize = 100
+
+type PostedNote struct {
+	ID         string
+	Text       string
+	CW         string
+	Visibility string
+	ReplyID    string
+	RenoteID   string
+	CreatedAt  time.Time
+}
+
+type postedNoteResponse struct {
+	noteResponse
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (r *noteRepository) RecentNotes(ctx context.Context, limit int) ([]PostedNote, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	userID, err := r.selfUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []PostedNote
+	untilID := ""
+	for len(notes) < limit {
+		pageSize := min(recentNotesPageSize, limit-len(notes))
+		params := map[string]interface{}{"userId": userID, "limit": pageSize}
+		if untilID != "" {
+			params["untilId"] = untilID
+		}
+
+		var page []postedNoteResponse
+		if err := r.call(ctx, "users/notes", params, &page); err != nil {
+			return notes, fmt.Errorf("failed to fetch recent notes: %w", err)
+		}
+		for _, n := range page {
+			notes = append(notes, PostedNote{
+				ID:         n.ID,
+				Text:       n.Text,
+				CW:         n.CW,
+				Visibility: n.Visibility,
+				ReplyID:    n.ReplyID,
+				RenoteID:   n.RenoteID,
+				CreatedAt:  n.CreatedAt,
+			})
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+		untilID = page[len(page)-1].ID
+	}
+
+	return notes, nil
+}
+
+func (r *noteRepository) selfUserID(ctx context.Context) (string, error) {
+	account := r.postingAccounts()[0]
+	if userID := account.getUserID(); userID != "" {
+		return userID, nil
+	}
+
+	var me struct {
+		ID string `json:"id"`
+	}
+	if err := r.call(ctx, "i", map[string]interface{}{"i": account.authToken}, &me); err != nil {
+		return "", fmt.Errorf("failed to resolve bot account: %w", err)
+	}
+	account.setUserID(me.ID)
+	return me.ID, nil
+}
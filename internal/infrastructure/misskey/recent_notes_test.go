@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_RecentNotes(t *testing.T) {
+	const available = 250
+
+	tests := []struct {
+		name          string
+		limit         int
+		expectedCount int
+		expectedPages int
+	}{
+		{"single page", 20, 20, 1},
+		{"multiple pages", 150, 150, 2},
+		{"history exhausted", 400, available, 3},
+		{"zero limit", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var payload map[string]interface{}
+				json.Unmarshal(body, &payload)
+
+				switch r.URL.Path {
+				case "/api/i":
+					w.Write([]byte(`{"id":"bot1"}`))
+				case "/api/users/notes":
+					pages++
+					if payload["userId"] != "bot1" {
+						t.Errorf("expected userId 'bot1', got %v", payload["userId"])
+					}
+					start := 0
+					if untilID, ok := payload["untilId"].(string); ok {
+						fmt.Sscanf(untilID, "note%d", &start)
+						start++
+					}
+					limit := int(payload["limit"].(float64))
+					var notes []map[string]interface{}
+					for i := start; i < min(start+limit, available); i++ {
+						notes = append(notes, map[string]interface{}{
+							"id":        fmt.Sprintf("note%d", i),
+							"text":      fmt.Sprintf("post %d", i),
+							"createdAt": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Minute),
+						})
+					}
+					json.NewEncoder(w).Encode(notes)
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			notes, err := repo.RecentNotes(context.Background(), tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(notes) != tt.expectedCount {
+				t.Fatalf("expected %d notes, got %d", tt.expectedCount, len(notes))
+			}
+			if pages != tt.expectedPages {
+				t.Errorf("expected %d pages, got %d", tt.expectedPages, pages)
+			}
+			for i, note := range notes {
+				if note.ID != fmt.Sprintf("note%d", i) {
+					t.Fatalf("expected note%d at position %d, got %s", i, i, note.ID)
+				}
+			}
+			if len(notes) > 0 && notes[0].CreatedAt.IsZero() {
+				t.Error("expected createdAt to be decoded")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package misskey
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const maxRedirects = 10
+
+type RedirectPolicy string
+
+const (
+	RedirectSameHost RedirectPolicy = "same-host"
+	RedirectRefuse   RedirectPolicy = "refuse"
+	RedirectFollow   RedirectPolicy = "follow"
+)
+
+func (p RedirectPolicy) validate() error {
+	switch p {
+	case "", RedirectSameHost, RedirectRefuse, RedirectFollow:
+		return nil
+	}
+	return fmt.Errorf("unknown redirect policy: %s", p)
+}
+
+func (p RedirectPolicy) checkRedirect() func(req *http.Request, via []*http.Request) error {
+	if p == RedirectFollow {
+		return nil
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if p == RedirectRefuse {
+			return fmt.Errorf("%w to %s", ErrUnexpectedRedirect, req.URL.Host)
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", ErrUnexpectedRedirect, len(via))
+		}
+		if original := via[0].URL; req.URL.Host != original.Host || req.URL.Scheme != original.Scheme {
+			return fmt.Errorf("%w from %s to %s", ErrUnexpectedRedirect, original.Host, req.URL.Host)
+		}
+		if req.Method != via[0].Method {
+			return fmt.Errorf("%w: %d redirect would change %s to %s", ErrUnexpectedRedirect, req.Response.StatusCode, via[0].Method, req.Method)
+		}
+		return nil
+	}
+}
+
+// guardRedirects returns a copy of client that checks every redirect hop
+// against the host allowlist before the client's own redirect policy, so no
+// policy can send the token in a re-sent body to another host.
+func (r *noteRepository) guardRedirects(client *http.Client) *http.Client {
+	guarded := *client
+	next := client.CheckRedirect
+	guarded.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := r.checkHostAllowed(req.URL.String()); err != nil {
+			return fmt.Errorf("%w: %w", ErrUnexpectedRedirect, err)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", ErrUnexpectedRedirect, len(via))
+		}
+		return nil
+	}
+	return &guarded
+}
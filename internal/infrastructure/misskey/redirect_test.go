@@ -0,0 +1,92 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_Redirects(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         RedirectPolicy
+		status         int
+		crossHost      bool
+		allowlisted    bool
+		expectErr      error
+		expectReceived bool
+	}{
+		{"same-host permanent redirect preserves POST", "", http.StatusPermanentRedirect, false, false, nil, true},
+		{"same-host found redirect would drop body", RedirectSameHost, http.StatusFound, false, false, ErrUnexpectedRedirect, false},
+		{"cross-host redirect refused", "", http.StatusPermanentRedirect, true, true, ErrUnexpectedRedirect, false},
+		{"refuse policy blocks same-host redirect", RedirectRefuse, http.StatusPermanentRedirect, false, false, ErrUnexpectedRedirect, false},
+		{"follow policy allows allowlisted cross-host redirect", RedirectFollow, http.StatusPermanentRedirect, true, true, nil, true},
+		{"follow policy refuses non-allowlisted 307", RedirectFollow, http.StatusTemporaryRedirect, true, false, ErrHostNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedToken interface{}
+			moved := func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				receivedToken = body["i"]
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}
+
+			other := httptest.NewServer(http.HandlerFunc(moved))
+			defer other.Close()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/moved/notes/create" {
+					moved(w, r)
+					return
+				}
+				target := server.URL
+				if tt.crossHost {
+					target = strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+				}
+				http.Redirect(w, r, target+"/moved/notes/create", tt.status)
+			}))
+			defer server.Close()
+
+			allowedHosts := []string{"127.0.0.1"}
+			if tt.allowlisted {
+				allowedHosts = append(allowedHosts, "localhost")
+			}
+			noteRepo, err := NewNoteRepository(Config{
+				Host:         server.URL,
+				AuthToken:    "test-token",
+				Redirects:    tt.policy,
+				AllowedHosts: allowedHosts,
+			})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+
+			err = noteRepo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityHome))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectReceived && receivedToken != "test-token" {
+				t.Errorf("expected redirected request to carry the token body, got %v", receivedToken)
+			}
+			if !tt.expectReceived && receivedToken != nil {
+				t.Errorf("expected redirect target not to receive the request, got token %v", receivedToken)
+			}
+		})
+	}
+}
+
+func TestRedirectPolicy_Validate(t *testing.T) {
+	if _, err := NewNoteRepository(Config{Host: "misskey.example", AuthToken: "token", Redirects: "sometimes"}); err == nil {
+		t.Error("expected error for unknown redirect policy, got nil")
+	}
+}
@@ -0,0 +1,33 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type RenderedNote struct {
+	Text string
+	CW   string
+	Raw  json.RawMessage
+}
+
+func (r *noteRepository) fetchRendered(ctx context.Context, account *postingAccount, noteID string, priority bool) (*RenderedNote, error) {
+	if !priority {
+		if remaining, err := account.rateLimiter.WaitRemaining(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", &RateLimitWaitError{Remaining: remaining, Err: err})
+		}
+	}
+
+	var raw json.RawMessage
+	params := map[string]interface{}{"i": account.authToken, "noteId": noteID}
+	if err := r.call(ctx, "notes/show", params, &raw); err != nil {
+		return nil, fmt.Errorf("failed to fetch note [%s]: %w", noteID, err)
+	}
+
+	var note noteResponse
+	if err := json.Unmarshal(raw, &note); err != nil {
+		return nil, fmt.Errorf("failed to decode note [%s]: %w", noteID, err)
+	}
+	return &RenderedNote{Text: note.Text, CW: note.CW, Raw: raw}, nil
+}
@@ -0,0 +1,48 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_PostWithOptions_FetchRendered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/notes/create":
+			w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+		case "/api/notes/show":
+			w.Write([]byte(`{"id": "note123", "text": "**bold**", "cw": null, "emojis": {}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	result, err := repo.PostWithOptions(context.Background(), entity.NewNote("**bold**", entity.VisibilityHome), PostOptions{FetchRendered: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Rendered == nil || result.Rendered.Text != "**bold**" {
+		t.Fatalf("expected rendered text, got %+v", result.Rendered)
+	}
+	if !strings.Contains(string(result.Rendered.Raw), `"emojis"`) {
+		t.Errorf("expected raw server representation, got %s", result.Rendered.Raw)
+	}
+	if repo.rateLimiter.permits != 1 {
+		t.Errorf("expected the fetch to consume a rate limit permit, %d remaining", repo.rateLimiter.permits)
+	}
+}
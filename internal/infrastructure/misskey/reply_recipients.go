@@ -0,0 +1,35 @@
+package misskey
+
+import (
+	"context"
+	"slices"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (r *noteRepository) withReplyRecipients(ctx context.Context, account *postingAccount, note *entity.Note) *entity.Note {
+	if !r.inheritReplyRecipients || note.ReplyID == "" || note.Visibility != entity.VisibilitySpecified {
+		return note
+	}
+
+	var parent noteResponse
+	if err := r.call(ctx, "notes/show", map[string]interface{}{"noteId": note.ReplyID}, &parent); err != nil {
+		r.logf("Warning: could not fetch parent note [%s] for recipients, using the explicit recipients only: %v", note.ReplyID, err)
+		return note
+	}
+	if parent.Visibility != string(entity.VisibilitySpecified) {
+		return note
+	}
+
+	self := account.getUserID()
+	recipients := slices.Clone(note.VisibleUserIDs)
+	for _, id := range append([]string{parent.UserID}, parent.VisibleUserIDs...) {
+		if id != "" && id != self && !slices.Contains(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+
+	reply := *note
+	reply.VisibleUserIDs = recipients
+	return &reply
+}
@@ -0,0 +1,127 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_InheritReplyRecipients(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		visibility entity.NoteVisibility
+		explicit   []string
+		parentErr  bool
+		parent     string
+		expected   []string
+	}{
+		{"union with explicit", true, entity.VisibilitySpecified, []string{"carol"}, false, `{"id":"parent1","visibility":"specified","userId":"alice","visibleUserIds":["bot","bob","carol"]}`, []string{"carol", "alice", "bob"}},
+		{"parent only", true, entity.VisibilitySpecified, nil, false, `{"id":"parent1","visibility":"specified","userId":"alice","visibleUserIds":["bob"]}`, []string{"alice", "bob"}},
+		{"parent not specified", true, entity.VisibilitySpecified, []string{"carol"}, false, `{"id":"parent1","visibility":"home","userId":"alice"}`, []string{"carol"}},
+		{"parent fetch fails", true, entity.VisibilitySpecified, []string{"carol"}, true, ``, []string{"carol"}},
+		{"disabled", false, entity.VisibilitySpecified, []string{"carol"}, false, `{"id":"parent1","visibility":"specified","userId":"alice"}`, []string{"carol"}},
+		{"reply not specified", true, entity.VisibilityHome, nil, false, `{"id":"parent1","visibility":"specified","userId":"alice"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/show":
+					if tt.parentErr {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error":{"code":"NO_SUCH_NOTE","message":"No such note."}}`))
+						return
+					}
+					w.Write([]byte(tt.parent))
+				case "/api/notes/create":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &payload)
+					w.Write([]byte(`{"createdNote": {"id": "reply1"}}`))
+				}
+			}))
+			defer server.Close()
+
+			account := &postingAccount{authToken: "test-token", rateLimiter: newRateLimiter(3, 10*time.Second)}
+			account.setUserID("bot")
+			repo := &noteRepository{
+				host:                   server.URL,
+				authToken:              "test-token",
+				client:                 &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:            account.rateLimiter,
+				accounts:               []*postingAccount{account},
+				inheritReplyRecipients: tt.enabled,
+			}
+
+			note := entity.NewNote("reply", tt.visibility)
+			note.ReplyID = "parent1"
+			note.VisibleUserIDs = tt.explicit
+			if err := repo.Post(context.Background(), note); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			if ids, ok := payload["visibleUserIds"].([]interface{}); ok {
+				for _, id := range ids {
+					got = append(got, id.(string))
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected visibleUserIds %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_InheritReplyRecipients_ExcludesPostingAccount(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/notes/show":
+			w.Write([]byte(`{"id":"parent1","visibility":"specified","userId":"alice","visibleUserIds":["bot1","bot2","bob"]}`))
+		case "/api/notes/create":
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &payload)
+			w.Write([]byte(`{"createdNote": {"id": "reply1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	first := &postingAccount{authToken: "token-a", rateLimiter: newRateLimiter(3, 10*time.Second), userID: "bot1"}
+	second := &postingAccount{authToken: "token-b", rateLimiter: newRateLimiter(3, 10*time.Second), userID: "bot2"}
+	repo := &noteRepository{
+		host:                   server.URL,
+		authToken:              "token-a",
+		client:                 &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:            first.rateLimiter,
+		accounts:               []*postingAccount{first, second},
+		inheritReplyRecipients: true,
+	}
+	repo.nextAccount.Store(1)
+
+	note := entity.NewNote("reply", entity.VisibilitySpecified)
+	note.ReplyID = "parent1"
+	if err := repo.Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payload["i"] != "token-b" {
+		t.Fatalf("expected the second account to post, got %v", payload["i"])
+	}
+	var got []string
+	for _, id := range payload["visibleUserIds"].([]interface{}) {
+		got = append(got, id.(string))
+	}
+	if expected := []string{"alice", "bot1", "bob"}; !slices.Equal(got, expected) {
+		t.Errorf("expected visibleUserIds %v, got %v", expected, got)
+	}
+}
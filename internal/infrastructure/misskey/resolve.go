@@ -0,0 +1,60 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+type noteResolutionCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func (c *noteResolutionCache) get(uri string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, ok := c.ids[uri]
+	return id, ok
+}
+
+func (c *noteResolutionCache) set(uri, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ids == nil {
+		c.ids = make(map[string]string)
+	}
+	c.ids[uri] = id
+}
+
+type apShowResponse struct {
+	Type   string `json:"type"`
+	Object struct {
+		ID string `json:"id"`
+	} `json:"object"`
+}
+
+func (r *noteRepository) ResolveNoteURL(ctx context.Context, uri string) (string, error) {
+	if id, ok := r.resolvedNotes.get(uri); ok {
+		return id, nil
+	}
+
+	var resp apShowResponse
+	if err := r.call(ctx, "ap/show", map[string]interface{}{"uri": uri}, &resp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+			return "", fmt.Errorf("%w [%s]: %w", ErrUnresolvableNote, uri, err)
+		}
+		return "", fmt.Errorf("failed to resolve note [%s]: %w", uri, err)
+	}
+	if resp.Type != "Note" || resp.Object.ID == "" {
+		return "", fmt.Errorf("%w [%s]: resolved to %q instead of a note", ErrUnresolvableNote, uri, resp.Type)
+	}
+
+	r.resolvedNotes.set(uri, resp.Object.ID)
+	return resp.Object.ID, nil
+}
@@ -0,0 +1,110 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_ResolveNoteURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		body             string
+		expectedID       string
+		expectUnresolved bool
+		expectErr        bool
+	}{
+		{"resolved note", http.StatusOK, `{"type": "Note", "object": {"id": "local1"}}`, "local1", false, false},
+		{"resolved user", http.StatusOK, `{"type": "User", "object": {"id": "user1"}}`, "", true, true},
+		{"unresolvable", http.StatusBadRequest, `{"error": {"code": "NO_SUCH_OBJECT"}}`, "", true, true},
+		{"server error", http.StatusInternalServerError, `{"error": {"code": "INTERNAL_ERROR"}}`, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/ap/show" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				calls++
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			uri := "https://remote.example/notes/abc"
+			id, err := repo.ResolveNoteURL(context.Background(), uri)
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if errors.Is(err, ErrUnresolvableNote) != tt.expectUnresolved {
+				t.Errorf("expected ErrUnresolvableNote=%v, got %v", tt.expectUnresolved, err)
+			}
+			if id != tt.expectedID {
+				t.Errorf("expected id %q, got %q", tt.expectedID, id)
+			}
+
+			repo.ResolveNoteURL(context.Background(), uri)
+			expectedCalls := 2
+			if !tt.expectErr {
+				expectedCalls = 1
+			}
+			if calls != expectedCalls {
+				t.Errorf("expected %d ap/show calls, got %d", expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostWithReplyToURL(t *testing.T) {
+	var replyID interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/ap/show":
+			w.Write([]byte(`{"type": "Note", "object": {"id": "local1"}}`))
+		case "/api/notes/create":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			replyID = body["replyId"]
+			w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	note := entity.NewNote("Reply", entity.VisibilityHome)
+	if _, err := repo.PostWithOptions(context.Background(), note, PostOptions{ReplyToURL: "https://remote.example/notes/abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replyID != "local1" {
+		t.Errorf("expected replyId local1, got %v", replyID)
+	}
+	if note.ReplyID != "" {
+		t.Errorf("expected caller's note to be left unchanged, got ReplyID %q", note.ReplyID)
+	}
+}
@@ -0,0 +1,55 @@
+package misskey
+
+import (
+	"time"
+)
+
+// Default lifetimes for cached instance lookups; override them with Config.CacheTTL.
+const (
+	defaultMetaCacheTTL         = time.Hour
+	defaultEmojisCacheTTL       = time.Hour
+	defaultAccountStatsCacheTTL = 5 * time.Minute
+	defaultAnnouncementsTTL     = 5 * time.Minute
+)
+
+type CacheTTLs struct {
+	Meta          time.Duration
+	Emojis        time.Duration
+	AccountStats  time.Duration
+	Announcements time.Duration
+}
+
+type responseCache struct {
+	meta          ttlCache[instanceMeta]
+	emojis        ttlCache[map[string]struct{}]
+	accountStats  ttlCache[accountStats]
+	announcements ttlCache[[]announcement]
+}
+
+func (t CacheTTLs) withDefaults() CacheTTLs {
+	if t.Meta == 0 {
+		t.Meta = defaultMetaCacheTTL
+	}
+	if t.Emojis == 0 {
+		t.Emojis = defaultEmojisCacheTTL
+	}
+	if t.AccountStats == 0 {
+		t.AccountStats = defaultAccountStatsCacheTTL
+	}
+	if t.Announcements == 0 {
+		t.Announcements = defaultAnnouncementsTTL
+	}
+	return t
+}
+
+func (c *responseCache) invalidate() {
+	c.meta.invalidate()
+	c.emojis.invalidate()
+	c.accountStats.invalidate()
+	c.announcements.invalidate()
+}
+
+func (r *noteRepository) InvalidateCache() {
+	r.cache.invalidate()
+	r.logf("Invalidated cached instance meta, emojis, account stats and announcements")
+}
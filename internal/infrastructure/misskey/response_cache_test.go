@@ -0,0 +1,70 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheTTLs_WithDefaults(t *testing.T) {
+	ttls := CacheTTLs{Emojis: time.Minute}.withDefaults()
+
+	if ttls.Meta != defaultMetaCacheTTL {
+		t.Errorf("expected default meta TTL %v, got %v", defaultMetaCacheTTL, ttls.Meta)
+	}
+	if ttls.Emojis != time.Minute {
+		t.Errorf("expected configured emojis TTL %v, got %v", time.Minute, ttls.Emojis)
+	}
+	if ttls.AccountStats != defaultAccountStatsCacheTTL {
+		t.Errorf("expected default account stats TTL %v, got %v", defaultAccountStatsCacheTTL, ttls.AccountStats)
+	}
+}
+
+func TestNoteRepository_InvalidateCache(t *testing.T) {
+	var metaCalls, emojiCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/meta":
+			metaCalls.Add(1)
+			w.Write([]byte(`{"federation": "all"}`))
+		case "/api/emojis":
+			emojiCalls.Add(1)
+			w.Write([]byte(`{"emojis": [{"name": "blobcat"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+		cache: responseCache{
+			meta:   ttlCache[instanceMeta]{ttl: time.Hour},
+			emojis: ttlCache[map[string]struct{}]{ttl: time.Hour},
+		},
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := repo.fetchMeta(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := repo.fetchEmojiNames(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if metaCalls.Load() != 1 || emojiCalls.Load() != 1 {
+		t.Fatalf("expected cached lookups, got meta=%d emojis=%d calls", metaCalls.Load(), emojiCalls.Load())
+	}
+
+	repo.InvalidateCache()
+
+	repo.fetchMeta(ctx)
+	repo.fetchEmojiNames(ctx)
+	if metaCalls.Load() != 2 || emojiCalls.Load() != 2 {
+		t.Errorf("expected refetch after invalidation, got meta=%d emojis=%d calls", metaCalls.Load(), emojiCalls.Load())
+	}
+}
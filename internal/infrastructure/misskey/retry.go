@@ -0,0 +1,194 @@
+package misskey
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func (r *noteRepository) callWithRetry(ctx context.Context, endpoint string, params map[string]interface{}, out interface{}) error {
+	return r.callWithRetryObserved(ctx, endpoint, params, out, nil)
+}
+
+func (r *noteRepository) callWithRetryObserved(ctx context.Context, endpoint string, params map[string]interface{}, out interface{}, observe func(error)) error {
+	var lastErr error
+	var longestAttempt, lastDelay time.Duration
+
+	for attempt := 0; attempt <= r.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := r.retryDelay(attempt, lastDelay)
+			var apiErr *APIError
+			if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+			lastDelay = delay
+			if !fitsDeadline(ctx, delay+longestAttempt) {
+				return fmt.Errorf("%w after %d attempts: %w", ErrRetryBudgetExhausted, attempt, lastErr)
+			}
+
+			r.logf("Retrying %s in %v (attempt %d/%d): %v", endpoint, delay, attempt, r.maxRetries, lastErr)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("retry aborted: %w: %w", err, lastErr)
+			}
+		}
+
+		start := time.Now()
+		err := r.call(ctx, endpoint, params, out)
+		longestAttempt = max(longestAttempt, time.Since(start))
+		if observe != nil {
+			observe(err)
+		}
+
+		if err == nil || !r.shouldRetry(endpoint, err) {
+			return err
+		}
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+type BackoffJitter string
+
+const (
+	BackoffJitterFull  BackoffJitter = "full"
+	BackoffJitterEqual BackoffJitter = "equal"
+	BackoffJitterNone  BackoffJitter = "none"
+)
+
+func (j BackoffJitter) validate() error {
+	switch j {
+	case "", BackoffJitterFull, BackoffJitterEqual, BackoffJitterNone:
+		return nil
+	}
+	return fmt.Errorf("unknown backoff jitter strategy: %s", j)
+}
+
+func (j BackoffJitter) apply(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	switch j {
+	case BackoffJitterNone:
+		return d
+	case BackoffJitterEqual:
+		return d/2 + rand.N(d-d/2+1)
+	default:
+		if d == math.MaxInt64 {
+			// rand.N takes an exclusive bound, so d+1 would overflow here.
+			return rand.N(d)
+		}
+		return rand.N(d + 1)
+	}
+}
+
+func (r *noteRepository) retryDelay(attempt int, lastDelay time.Duration) time.Duration {
+	return r.retryBackoffStrategy().Next(attempt, lastDelay)
+}
+
+func (r *noteRepository) retryBackoffStrategy() Backoff {
+	if r.backoff != nil {
+		return r.backoff
+	}
+	base := r.retryBackoff
+	if base <= 0 {
+		base = time.Second
+	}
+	return NewExponentialBackoff(base, 0, r.backoffJitter)
+}
+
+func defaultRetryableErrorCodes() []string {
+	return []string{errCodeRateLimitExceeded, errCodeInternalError}
+}
+
+func (r *noteRepository) retryableCodes() []string {
+	if len(r.retryableErrorCodes) > 0 {
+		return r.retryableErrorCodes
+	}
+	return defaultRetryableErrorCodes()
+}
+
+// shouldRetry only retries notes/create when the note cannot have been
+// created: Misskey has no idempotency key, so a retry after a timeout or a
+// 5xx could post the same note twice.
+func (r *noteRepository) shouldRetry(endpoint string, err error) bool {
+	if endpoint == "notes/create" {
+		return isRetryableCreate(err, r.retryableCodes())
+	}
+	return isRetryable(err, r.retryableCodes())
+}
+
+func isRetryableCreate(err error, retryableCodes []string) bool {
+	if !isRetryable(err, retryableCodes) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode < http.StatusInternalServerError && apiErr.Code != errCodeInternalError
+	}
+	return isNotSent(err)
+}
+
+// isNotSent reports whether err happened before the request reached the
+// server, while resolving, dialing or completing the TLS handshake.
+func isNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &recordErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isRetryable(err error, retryableCodes []string) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrAccountSuspended) || errors.Is(err, ErrDailyNoteCapReached) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Code != "" && slices.Contains(retryableCodes, apiErr.Code) {
+			return true
+		}
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func fitsDeadline(ctx context.Context, needed time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Until(deadline) > needed
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
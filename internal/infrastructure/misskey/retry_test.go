@@ -0,0 +1,231 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func newRetryTestRepository(url string, maxRetries int, backoff time.Duration) *noteRepository {
+	return &noteRepository{
+		host:          url,
+		authToken:     "test-token",
+		client:        &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:   newRateLimiter(3, 10*time.Second),
+		maxRetries:    maxRetries,
+		retryBackoff:  backoff,
+		backoffJitter: BackoffJitterNone,
+	}
+}
+
+func TestNoteRepository_Post_RetryOnServerError(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int32
+		status           int
+		body             string
+		maxRetries       int
+		expectErr        bool
+		expectedAttempts int32
+	}{
+		{"no retries configured", 1, http.StatusInternalServerError, "", 0, true, 1},
+		{"recovers after retry", 2, http.StatusTooManyRequests, "", 3, false, 3},
+		{"retries exhausted", 5, http.StatusTooManyRequests, "", 2, true, 3},
+		{"server error is not resent", 1, http.StatusBadGateway, "", 3, true, 1},
+		{"too many requests is retried", 1, http.StatusTooManyRequests, "", 1, false, 2},
+		{"client error is not retried", 5, http.StatusBadRequest, "", 3, true, 1},
+		{"retryable error code is retried", 1, http.StatusBadRequest, `{"error": {"code": "RATE_LIMIT_EXCEEDED"}}`, 1, false, 2},
+		{"permanent error code is not retried", 5, http.StatusBadRequest, `{"error": {"code": "NO_SUCH_USER"}}`, 3, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			repo := newRetryTestRepository(server.URL, tt.maxRetries, time.Millisecond)
+
+			err := repo.Post(context.Background(), entity.NewNote("Test", entity.VisibilityHome))
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if attempts.Load() != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_RetryBudget(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	repo := newRetryTestRepository(server.URL, 10, 40*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := repo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome))
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected last API error to be preserved, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected to stop before the deadline, took %v", elapsed)
+	}
+	if attempts.Load() >= 10 {
+		t.Errorf("expected budget to cut retries short, got %d attempts", attempts.Load())
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		codes    []string
+		expected bool
+	}{
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, nil, true},
+		{"too many requests", &APIError{StatusCode: http.StatusTooManyRequests}, nil, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, nil, false},
+		{"retryable code on bad request", &APIError{StatusCode: http.StatusBadRequest, Code: "RATE_LIMIT_EXCEEDED"}, defaultRetryableErrorCodes(), true},
+		{"permanent code on bad request", &APIError{StatusCode: http.StatusBadRequest, Code: "NO_SUCH_USER"}, defaultRetryableErrorCodes(), false},
+		{"custom retryable code", &APIError{StatusCode: http.StatusBadRequest, Code: "TEMPORARY"}, []string{"TEMPORARY"}, true},
+		{"context canceled", fmt.Errorf("wrapped: %w", context.Canceled), defaultRetryableErrorCodes(), false},
+		{"account suspended", fmt.Errorf("%w: %w", ErrAccountSuspended, &APIError{StatusCode: http.StatusInternalServerError, Code: "INTERNAL_ERROR"}), defaultRetryableErrorCodes(), false},
+		{"plain error", errors.New("boom"), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err, tt.codes); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsRetryableCreate(t *testing.T) {
+	timeout := &net.OpError{Op: "read", Net: "tcp", Err: &timeoutError{}}
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"too many requests", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"rate limit code", &APIError{StatusCode: http.StatusBadRequest, Code: "RATE_LIMIT_EXCEEDED"}, true},
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, false},
+		{"internal error code", &APIError{StatusCode: http.StatusInternalServerError, Code: "INTERNAL_ERROR"}, false},
+		{"dial error", fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), true},
+		{"dns error", fmt.Errorf("request failed: %w", &net.DNSError{Err: "no such host", Name: "example.tld", IsTemporary: true}), true},
+		{"timeout after sending", fmt.Errorf("request failed: %w", timeout), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableCreate(tt.err, defaultRetryableErrorCodes()); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestBackoffJitter_Bounds(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		jitter  BackoffJitter
+		attempt int
+		minimum time.Duration
+		maximum time.Duration
+	}{
+		{"none is exact", BackoffJitterNone, 3, 400 * time.Millisecond, 400 * time.Millisecond},
+		{"full defaults when unset", "", 2, 0, 200 * time.Millisecond},
+		{"full", BackoffJitterFull, 3, 0, 400 * time.Millisecond},
+		{"equal", BackoffJitterEqual, 3, 200 * time.Millisecond, 400 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{retryBackoff: base, backoffJitter: tt.jitter}
+			for i := 0; i < 1000; i++ {
+				delay := repo.retryDelay(tt.attempt, 0)
+				if delay < tt.minimum || delay > tt.maximum {
+					t.Fatalf("delay %v outside [%v, %v]", delay, tt.minimum, tt.maximum)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffJitter_ApplyOutOfRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		maximum time.Duration
+	}{
+		{"negative", -time.Second, 0},
+		{"zero", 0, 0},
+		{"max duration", math.MaxInt64, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, jitter := range []BackoffJitter{BackoffJitterNone, BackoffJitterFull, BackoffJitterEqual} {
+				if got := jitter.apply(tt.delay); got < 0 || got > tt.maximum {
+					t.Errorf("%s: delay %v outside [0, %v]", jitter, got, tt.maximum)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffJitter_Validate(t *testing.T) {
+	if err := BackoffJitter("random").validate(); err == nil {
+		t.Error("expected error for unknown jitter strategy, got nil")
+	}
+	if err := BackoffJitterEqual.validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type ScheduledNote struct {
+	ID          string
+	Text        string
+	Visibility  entity.NoteVisibility
+	ScheduledAt time.Time
+}
+
+type scheduledNoteResponse struct {
+	ID          string    `json:"id"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	Note        struct {
+		Text       string `json:"text"`
+		Visibility string `json:"visibility"`
+	} `json:"note"`
+}
+
+func (r *noteRepository) ListScheduled(ctx context.Context) ([]ScheduledNote, error) {
+	var resp []scheduledNoteResponse
+	if err := r.call(ctx, "notes/scheduled", nil, &resp); err != nil {
+		if isUnsupportedEndpoint(err) {
+			return nil, ErrSchedulingUnsupported
+		}
+		return nil, fmt.Errorf("failed to list scheduled notes: %w", err)
+	}
+
+	notes := make([]ScheduledNote, 0, len(resp))
+	for _, item := range resp {
+		notes = append(notes, ScheduledNote{
+			ID:          item.ID,
+			Text:        item.Note.Text,
+			Visibility:  entity.NoteVisibility(item.Note.Visibility),
+			ScheduledAt: item.ScheduledAt,
+		})
+	}
+	return notes, nil
+}
+
+func (r *noteRepository) CancelScheduled(ctx context.Context, scheduledNoteID string) error {
+	params := map[string]interface{}{"scheduledNoteId": scheduledNoteID}
+	if err := r.call(ctx, "notes/scheduled/cancel", params, nil); err != nil {
+		if isUnsupportedEndpoint(err) {
+			return ErrSchedulingUnsupported
+		}
+		return fmt.Errorf("failed to cancel scheduled note [%s]: %w", scheduledNoteID, err)
+	}
+	return nil
+}
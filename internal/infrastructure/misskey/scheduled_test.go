@@ -0,0 +1,127 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_ListScheduled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/notes/scheduled" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id":"sched1","scheduledAt":"2026-01-02T03:04:05Z","note":{"text":"later","visibility":"home"}}]`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	notes, err := repo.ListScheduled(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 1 {
+		t.Fatalf("expected 1 scheduled note, got %d", len(notes))
+	}
+
+	expectedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if notes[0].ID != "sched1" || notes[0].Text != "later" || notes[0].Visibility != entity.VisibilityHome {
+		t.Errorf("unexpected scheduled note: %+v", notes[0])
+	}
+	if !notes[0].ScheduledAt.Equal(expectedAt) {
+		t.Errorf("expected scheduledAt %v, got %v", expectedAt, notes[0].ScheduledAt)
+	}
+}
+
+func TestNoteRepository_CancelScheduled(t *testing.T) {
+	var receivedPayload map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/notes/scheduled/cancel" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if err := repo.CancelScheduled(context.Background(), "sched1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receivedPayload["scheduledNoteId"] != "sched1" {
+		t.Errorf("expected scheduledNoteId 'sched1', got '%v'", receivedPayload["scheduledNoteId"])
+	}
+}
+
+func TestNoteRepository_Scheduled_Unsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"UNKNOWN_API_ENDPOINT","message":"Unknown API endpoint."}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	ctx := context.Background()
+	if _, err := repo.ListScheduled(ctx); !errors.Is(err, ErrSchedulingUnsupported) {
+		t.Errorf("expected ErrSchedulingUnsupported from ListScheduled, got %v", err)
+	}
+	if err := repo.CancelScheduled(ctx, "sched1"); !errors.Is(err, ErrSchedulingUnsupported) {
+		t.Errorf("expected ErrSchedulingUnsupported from CancelScheduled, got %v", err)
+	}
+}
+
+func TestNoteRepository_Post_ScheduledAt(t *testing.T) {
+	var receivedPayload map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	scheduledAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	note := entity.NewNote("later", entity.VisibilityHome)
+	note.ScheduledAt = &scheduledAt
+
+	if err := repo.Post(context.Background(), note); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedPayload["scheduledAt"] != float64(scheduledAt.UnixMilli()) {
+		t.Errorf("expected scheduledAt %d, got %v", scheduledAt.UnixMilli(), receivedPayload["scheduledAt"])
+	}
+}
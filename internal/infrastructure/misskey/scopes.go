@@ -0,0 +1,79 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	ScopeReadAccount       = "read:account"
+	ScopeWriteAccount      = "write:account"
+	ScopeWriteNotes        = "write:notes"
+	ScopeWriteDrive        = "write:drive"
+	ScopeWriteReactions    = "write:reactions"
+	ScopeReadNotifications = "read:notifications"
+)
+
+type scopeProbe struct {
+	endpoint string
+	params   map[string]interface{}
+}
+
+// Misskey does not expose the permissions of the calling token, but it checks
+// them before validating parameters, so a request with deliberately invalid
+// parameters answers PERMISSION_DENIED or INVALID_PARAM without side effects.
+func scopeProbes() map[string]scopeProbe {
+	return map[string]scopeProbe{
+		ScopeReadAccount:       {"i", nil},
+		ScopeWriteAccount:      {"i/update", map[string]interface{}{"isBot": "invalid"}},
+		ScopeWriteNotes:        {"notes/delete", map[string]interface{}{"noteId": 0}},
+		ScopeWriteDrive:        {"drive/files/delete", map[string]interface{}{"fileId": 0}},
+		ScopeWriteReactions:    {"notes/reactions/create", map[string]interface{}{"noteId": 0}},
+		ScopeReadNotifications: {"i/notifications", map[string]interface{}{"limit": 0}},
+	}
+}
+
+func (r *noteRepository) VerifyScopes(ctx context.Context, scopes ...string) error {
+	probes := scopeProbes()
+	for _, scope := range scopes {
+		if _, ok := probes[scope]; !ok {
+			return fmt.Errorf("unknown permission scope: %s", scope)
+		}
+	}
+
+	accounts := r.postingAccounts()
+	errs := newMultiError("failed to verify token permissions", len(accounts))
+	for i, account := range accounts {
+		missing, err := r.missingScopes(ctx, account.authToken, probes, scopes)
+		if err != nil {
+			errs.Add(fmt.Sprintf("token #%d", i), err)
+			continue
+		}
+		if len(missing) > 0 {
+			errs.Add(fmt.Sprintf("token #%d", i), fmt.Errorf("%w: %s", ErrMissingScopes, strings.Join(missing, ", ")))
+		}
+	}
+	return errs.ErrOrNil()
+}
+
+func (r *noteRepository) missingScopes(ctx context.Context, authToken string, probes map[string]scopeProbe, scopes []string) ([]string, error) {
+	var missing []string
+	for _, scope := range scopes {
+		probe := probes[scope]
+		params := map[string]interface{}{"i": authToken}
+		for k, v := range probe.params {
+			params[k] = v
+		}
+
+		err := r.call(ctx, probe.endpoint, params, nil)
+		switch {
+		case err == nil, hasErrorCode(err, errCodeInvalidParam):
+		case hasErrorCode(err, errCodePermissionDenied):
+			missing = append(missing, scope)
+		default:
+			return nil, fmt.Errorf("failed to check %s permission: %w", scope, err)
+		}
+	}
+	return missing, nil
+}
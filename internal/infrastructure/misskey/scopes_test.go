@@ -0,0 +1,96 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_VerifyScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		denied    []string
+		scopes    []string
+		expectErr error
+		missing   []string
+	}{
+		{"all granted", nil, []string{ScopeReadAccount, ScopeWriteNotes, ScopeWriteDrive}, nil, nil},
+		{"missing drive", []string{"/api/drive/files/delete"}, []string{ScopeWriteNotes, ScopeWriteDrive}, ErrMissingScopes, []string{ScopeWriteDrive}},
+		{"missing several", []string{"/api/notes/delete", "/api/notes/reactions/create"}, []string{ScopeWriteNotes, ScopeWriteReactions}, ErrMissingScopes, []string{ScopeWriteNotes, ScopeWriteReactions}},
+		{"no scopes", nil, nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var payload map[string]interface{}
+				json.Unmarshal(body, &payload)
+				if payload["i"] != "test-token" {
+					t.Errorf("expected token in probe, got %v", payload["i"])
+				}
+
+				for _, path := range tt.denied {
+					if r.URL.Path == path {
+						w.WriteHeader(http.StatusForbidden)
+						w.Write([]byte(`{"error":{"code":"PERMISSION_DENIED","message":"Your app does not have the necessary permissions to use this endpoint."}}`))
+						return
+					}
+				}
+				if r.URL.Path == "/api/i" {
+					w.Write([]byte(`{"id":"user1"}`))
+					return
+				}
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"code":"INVALID_PARAM","message":"Invalid param."}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			err := repo.VerifyScopes(context.Background(), tt.scopes...)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			for _, scope := range tt.missing {
+				if !strings.Contains(err.Error(), scope) {
+					t.Errorf("expected error to name %s, got %v", scope, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_VerifyScopes_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"CREDENTIAL_REQUIRED","message":"Credential required."}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if err := repo.VerifyScopes(context.Background(), "write:everything"); err == nil || !strings.Contains(err.Error(), "unknown permission scope") {
+		t.Errorf("expected unknown scope error, got %v", err)
+	}
+
+	err := repo.VerifyScopes(context.Background(), ScopeWriteNotes)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || errors.Is(err, ErrMissingScopes) {
+		t.Errorf("expected API error rather than missing scopes, got %v", err)
+	}
+}
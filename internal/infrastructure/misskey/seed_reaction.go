@@ -0,0 +1,37 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r *noteRepository) seedReaction(ctx context.Context, account *postingAccount, result *PostResult, opts PostOptions) error {
+	if opts.SeedReaction == "" || result.Outcome == PostOutcomeDeferred {
+		return nil
+	}
+
+	err := r.reactAs(ctx, account, result.NoteID, opts.SeedReaction, opts.Priority)
+	if err == nil {
+		result.Seeded = true
+		return nil
+	}
+	if opts.RequireSeed {
+		return fmt.Errorf("note [%s] posted but seeding reaction %s failed: %w", result.NoteID, opts.SeedReaction, err)
+	}
+	r.logf("Warning: Failed to seed reaction %s on note [%s]: %v", opts.SeedReaction, result.NoteID, err)
+	return nil
+}
+
+func (r *noteRepository) reactAs(ctx context.Context, account *postingAccount, noteID, reaction string, priority bool) error {
+	if !priority {
+		if remaining, err := postingLimiter(ctx, account).WaitRemaining(ctx); err != nil {
+			return fmt.Errorf("rate limiter error: %w", &RateLimitWaitError{Remaining: remaining, Err: err})
+		}
+	}
+
+	params := map[string]interface{}{"i": account.authToken, "noteId": noteID, "reaction": reaction}
+	if err := r.call(ctx, "notes/reactions/create", params, nil); err != nil {
+		return fmt.Errorf("failed to react to note [%s]: %w", noteID, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_SeedReaction(t *testing.T) {
+	tests := []struct {
+		name         string
+		reaction     string
+		require      bool
+		reactFails   bool
+		expectErr    bool
+		expectSeeded bool
+		expectReact  bool
+	}{
+		{"seeded", "📢", false, false, false, true, true},
+		{"no seeding", "", false, false, false, false, false},
+		{"failure tolerated", "📢", false, true, false, false, true},
+		{"failure required", "📢", true, true, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reactPayload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/notes/create":
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				case "/api/notes/reactions/create":
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &reactPayload)
+					if tt.reactFails {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"error":{"code":"NO_SUCH_NOTE","message":"No such note."}}`))
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			limiter := newRateLimiter(3, time.Hour)
+			repo := &noteRepository{
+				host:        server.URL,
+				authToken:   "test-token",
+				client:      &http.Client{Timeout: 30 * time.Second},
+				rateLimiter: limiter,
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), entity.NewNote("Announcement", entity.VisibilityPublic), PostOptions{SeedReaction: tt.reaction, RequireSeed: tt.require})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if result == nil || result.NoteID != "note123" {
+				t.Fatalf("expected note ID to be returned, got %+v", result)
+			}
+			if result.Seeded != tt.expectSeeded {
+				t.Errorf("expected seeded=%v, got %v", tt.expectSeeded, result.Seeded)
+			}
+			if (reactPayload != nil) != tt.expectReact {
+				t.Fatalf("expected reaction request=%v, got %v", tt.expectReact, reactPayload)
+			}
+			if tt.expectReact && (reactPayload["noteId"] != "note123" || reactPayload["reaction"] != tt.reaction) {
+				t.Errorf("unexpected reaction payload: %v", reactPayload)
+			}
+
+			expectedPermits := 2
+			if tt.expectReact {
+				expectedPermits = 1
+			}
+			if limiter.permits != expectedPermits {
+				t.Errorf("expected %d permits left, got %d", expectedPermits, limiter.permits)
+			}
+		})
+	}
+}
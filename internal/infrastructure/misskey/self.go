@@ -0,0 +1,38 @@
+package misskey
+
+import (
+	"context"
+)
+
+func (r *noteRepository) IsSelf(authorID string) bool {
+	if authorID == "" {
+		return false
+	}
+	for _, account := range r.postingAccounts() {
+		if account.getUserID() == authorID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *noteRepository) ensureSelfIDs(ctx context.Context) {
+	for _, account := range r.postingAccounts() {
+		if account.getUserID() == "" {
+			if err := r.Ping(ctx); err != nil {
+				r.logf("Warning: Could not resolve the bot's own account IDs, self-notifications may not be filtered: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func (r *noteRepository) withoutSelf(notifications []Notification) []Notification {
+	filtered := notifications[:0]
+	for _, n := range notifications {
+		if !r.IsSelf(n.UserID) {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
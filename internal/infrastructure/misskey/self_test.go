@@ -0,0 +1,83 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_IsSelf(t *testing.T) {
+	repo := &noteRepository{accounts: []*postingAccount{{authToken: "a", userID: "bot1"}, {authToken: "b", userID: "bot2"}, {authToken: "c"}}}
+
+	tests := []struct {
+		name     string
+		authorID string
+		expected bool
+	}{
+		{"primary account", "bot1", true},
+		{"secondary account", "bot2", true},
+		{"other user", "alice", false},
+		{"empty id", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repo.IsSelf(tt.authorID); got != tt.expected {
+				t.Errorf("expected IsSelf(%q)=%v, got %v", tt.authorID, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_ListNotifications_IgnoresSelf(t *testing.T) {
+	selfLookups := 0
+	selfID := "bot"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/i" {
+			selfLookups++
+			w.Write([]byte(`{"id": "` + selfID + `"}`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id": "n3", "type": "mention", "user": {"id": "bot", "username": "rssbot"}, "note": {"id": "note3"}},
+			{"id": "n2", "type": "reply", "user": {"id": "alice", "username": "alice"}, "note": {"id": "note2"}},
+			{"id": "n1", "type": "mention", "user": {"id": "bot2", "username": "rssbot"}, "note": {"id": "note1"}}
+		]`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+		accounts:  []*postingAccount{{authToken: "test-token"}},
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		notifications, err := repo.Notifications(ctx, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notifications) != 2 || notifications[0].ID != "n2" || notifications[1].ID != "n1" {
+			t.Errorf("expected notifications n2 and n1, got %+v", notifications)
+		}
+	}
+	if selfLookups != 1 {
+		t.Errorf("expected the bot's account to be looked up once, got %d", selfLookups)
+	}
+
+	selfID = "bot2"
+	if err := repo.Ping(ctx); err != nil {
+		t.Fatalf("unexpected ping error: %v", err)
+	}
+	notifications, err := repo.Notifications(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 2 || notifications[0].ID != "n3" {
+		t.Errorf("expected refreshed self ID to filter n1 instead, got %+v", notifications)
+	}
+}
@@ -0,0 +1,134 @@
+package misskey
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const (
+	defaultServerDedupeWindow  = 24 * time.Hour
+	serverDedupeSearchBurst    = 3
+	serverDedupeSearchInterval = 10 * time.Second
+	serverDedupeSearchLimit    = 20
+	serverDedupeQueryLength    = 100
+	postedKeyPrefix            = "posted:"
+)
+
+func (r *noteRepository) findPostedDuplicate(ctx context.Context, note *entity.Note) (string, bool) {
+	if !r.serverDedupe {
+		return "", false
+	}
+	text := strings.TrimSpace(r.renderText(note))
+	if text == "" {
+		return "", false
+	}
+
+	if !r.searchUnsupported.Load() {
+		noteID, found, err := r.searchPostedNote(ctx, text)
+		if err == nil {
+			return noteID, found
+		}
+		if isSearchUnavailable(err) {
+			if r.searchUnsupported.CompareAndSwap(false, true) {
+				r.logf("Warning: note search is unavailable on this instance, falling back to local dedupe: %v", err)
+			}
+		} else {
+			r.logf("Warning: server-side dedupe search failed, falling back to local dedupe: %v", err)
+		}
+	}
+	return "", r.postedLocally(ctx, text)
+}
+
+func (r *noteRepository) searchPostedNote(ctx context.Context, text string) (string, bool, error) {
+	if r.searchLimiter != nil {
+		if err := r.searchLimiter.Wait(ctx); err != nil {
+			return "", false, err
+		}
+	}
+
+	userID, err := r.selfUserID(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	link := dedupeLink(text)
+	query := link
+	if query == "" {
+		query = strings.TrimSpace(TruncateText(text, serverDedupeQueryLength, TruncateOptions{Ellipsis: " "}))
+	}
+
+	var notes []postedNoteResponse
+	params := map[string]interface{}{
+		"query":  query,
+		"userId": userID,
+		"limit":  serverDedupeSearchLimit,
+	}
+	if err := r.call(ctx, "notes/search", params, &notes); err != nil {
+		return "", false, err
+	}
+
+	window := r.serverDedupeWindow
+	if window <= 0 {
+		window = defaultServerDedupeWindow
+	}
+	since := time.Now().Add(-window)
+	for _, n := range notes {
+		if n.CreatedAt.Before(since) {
+			continue
+		}
+		if matchesPostedNote(text, link, n.Text) {
+			return n.ID, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func dedupeLink(text string) string {
+	spans := findLinks(text)
+	if len(spans) == 0 {
+		return ""
+	}
+	last := spans[len(spans)-1]
+	return text[last.start:last.end]
+}
+
+func matchesPostedNote(text, link, candidate string) bool {
+	if link != "" {
+		return strings.Contains(candidate, link)
+	}
+	return strings.TrimSpace(candidate) == text
+}
+
+// isSearchUnavailable reports whether search is off on the instance, as
+// opposed to one query failing, which should not disable server dedupe.
+func isSearchUnavailable(err error) bool {
+	return hasErrorCode(err, errCodeUnavailable) || isUnsupportedEndpoint(err)
+}
+
+func (r *noteRepository) postedLocally(ctx context.Context, text string) bool {
+	if r.state == nil && r.dedupe == nil {
+		return false
+	}
+	hash := hashText(text)
+	previous, err := r.loadContentHash(ctx, postedKeyPrefix+hash)
+	if err != nil {
+		r.logf("Warning: local dedupe lookup failed, posting anyway: %v", err)
+		return false
+	}
+	return previous == hash
+}
+
+func (r *noteRepository) rememberPosted(note *entity.Note) {
+	if !r.serverDedupe || (r.state == nil && r.dedupe == nil) {
+		return
+	}
+	text := strings.TrimSpace(r.renderText(note))
+	if text == "" {
+		return
+	}
+	hash := hashText(text)
+	r.saveContentHash(postedKeyPrefix+hash, hash)
+}
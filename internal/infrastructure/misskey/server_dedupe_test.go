@@ -0,0 +1,165 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_Post_ServerDedupe(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	stale := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name            string
+		serverDedupe    bool
+		text            string
+		searchStatus    int
+		searchBody      string
+		expectedOutcome PostOutcome
+		expectedNoteID  string
+		expectedSearch  int
+	}{
+		{
+			"disabled skips search", false, "Hello https://example.com/a",
+			http.StatusOK, `[]`, PostOutcomePosted, "note123", 0,
+		},
+		{
+			"link already posted", true, "Hello https://example.com/a",
+			http.StatusOK, fmt.Sprintf(`[{"id":"old1","text":"Earlier https://example.com/a","createdAt":%q}]`, recent),
+			PostOutcomeSkippedDuplicate, "old1", 1,
+		},
+		{
+			"identical text already posted", true, "Hello world",
+			http.StatusOK, fmt.Sprintf(`[{"id":"old2","text":"Hello world\n","createdAt":%q}]`, recent),
+			PostOutcomeSkippedDuplicate, "old2", 1,
+		},
+		{
+			"match outside window", true, "Hello https://example.com/a",
+			http.StatusOK, fmt.Sprintf(`[{"id":"old1","text":"Hello https://example.com/a","createdAt":%q}]`, stale),
+			PostOutcomePosted, "note123", 1,
+		},
+		{
+			"different link", true, "Hello https://example.com/b",
+			http.StatusOK, fmt.Sprintf(`[{"id":"old1","text":"Hello https://example.com/a","createdAt":%q}]`, recent),
+			PostOutcomePosted, "note123", 1,
+		},
+		{
+			"search unavailable posts", true, "Hello world",
+			http.StatusBadRequest, `{"error":{"code":"UNAVAILABLE"}}`, PostOutcomePosted, "note123", 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searches := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/i":
+					w.Write([]byte(`{"id": "bot1"}`))
+				case "/api/notes/search":
+					searches++
+					w.WriteHeader(tt.searchStatus)
+					w.Write([]byte(tt.searchBody))
+				default:
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				}
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:         server.URL,
+				authToken:    "test-token",
+				client:       &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:  newRateLimiter(10, 10*time.Second),
+				serverDedupe: tt.serverDedupe,
+			}
+
+			result, err := repo.PostWithOptions(context.Background(), entity.NewNote(tt.text, entity.VisibilityHome), PostOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Outcome != tt.expectedOutcome {
+				t.Errorf("expected outcome %s, got %s", tt.expectedOutcome, result.Outcome)
+			}
+			if result.NoteID != tt.expectedNoteID {
+				t.Errorf("expected note ID %s, got %s", tt.expectedNoteID, result.NoteID)
+			}
+			if searches != tt.expectedSearch {
+				t.Errorf("expected %d searches, got %d", tt.expectedSearch, searches)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Post_ServerDedupeLocalFallback(t *testing.T) {
+	searches, posts := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/i":
+			w.Write([]byte(`{"id": "bot1"}`))
+		case "/api/notes/search":
+			searches++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"UNAVAILABLE"}}`))
+		default:
+			posts++
+			w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:         server.URL,
+		authToken:    "test-token",
+		client:       &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:  newRateLimiter(10, 10*time.Second),
+		dedupe:       storage.NewMemoryDedupeStore(),
+		serverDedupe: true,
+	}
+
+	for _, text := range []string{"Hello", "Hello", "Hello again"} {
+		if _, err := repo.PostWithOptions(context.Background(), entity.NewNote(text, entity.VisibilityHome), PostOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if posts != 2 {
+		t.Errorf("expected 2 posts, got %d", posts)
+	}
+	if searches != 1 {
+		t.Errorf("expected search to be attempted once before falling back, got %d", searches)
+	}
+	if !repo.searchUnsupported.Load() {
+		t.Error("expected search to be marked unsupported")
+	}
+}
+
+func TestIsSearchUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"search disabled", &APIError{StatusCode: http.StatusBadRequest, Code: "UNAVAILABLE"}, true},
+		{"unknown endpoint", &APIError{StatusCode: http.StatusBadRequest, Code: "UNKNOWN_API_ENDPOINT"}, true},
+		{"endpoint not found", &APIError{StatusCode: http.StatusNotFound}, true},
+		{"invalid param", &APIError{StatusCode: http.StatusBadRequest, Code: "INVALID_PARAM"}, false},
+		{"forbidden", &APIError{StatusCode: http.StatusForbidden, Code: "PERMISSION_DENIED"}, false},
+		{"network error", errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSearchUnavailable(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type opTracker struct {
+	mu      sync.Mutex
+	closing bool
+	nextID  int
+	ops     map[int]*trackedOp
+	drained chan struct{}
+}
+
+// trackedOp counts the HTTP requests an operation has in flight. Nested
+// operations also count against their parents.
+type trackedOp struct {
+	cancel  context.CancelFunc
+	sending int
+	parent  *trackedOp
+}
+
+type trackedOpKey struct{}
+
+func (t *opTracker) begin(ctx context.Context) (context.Context, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closing {
+		return ctx, nil, ErrRepositoryClosed
+	}
+	if t.ops == nil {
+		t.ops = make(map[int]*trackedOp)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	parent, _ := ctx.Value(trackedOpKey{}).(*trackedOp)
+	op := &trackedOp{cancel: cancel, parent: parent}
+	ctx = context.WithValue(ctx, trackedOpKey{}, op)
+	id := t.nextID
+	t.nextID++
+	t.ops[id] = op
+
+	return ctx, func() {
+		cancel()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.ops, id)
+		if t.closing && len(t.ops) == 0 && t.drained != nil {
+			close(t.drained)
+			t.drained = nil
+		}
+	}, nil
+}
+
+// sending marks the operation in ctx as having a request in flight until
+// the returned func is called.
+func (t *opTracker) sending(ctx context.Context) func() {
+	op, ok := ctx.Value(trackedOpKey{}).(*trackedOp)
+	if !ok {
+		return func() {}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for o := op; o != nil; o = o.parent {
+		o.sending++
+	}
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for o := op; o != nil; o = o.parent {
+			o.sending--
+		}
+	}
+}
+
+func (t *opTracker) close() (int, <-chan struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closing = true
+	if len(t.ops) == 0 {
+		return 0, nil
+	}
+	if t.drained == nil {
+		t.drained = make(chan struct{})
+	}
+	return len(t.ops), t.drained
+}
+
+func (t *opTracker) cancelAll() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, op := range t.ops {
+		op.cancel()
+	}
+	return len(t.ops)
+}
+
+// cancelIdle cancels the operations without a request in flight, such as
+// posts still waiting on a rate limiter.
+func (t *opTracker) cancelIdle() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cancelled := 0
+	for _, op := range t.ops {
+		if op.sending == 0 {
+			op.cancel()
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+func (r *noteRepository) drain(ctx context.Context) error {
+	active, drained := r.ops.close()
+	if !r.waitLimitersOnShutdown {
+		r.releaseLimiters()
+	}
+	if active == 0 {
+		return nil
+	}
+	r.logf("Waiting for %d in-flight operation(s) to finish", active)
+
+	soft := time.NewTimer(r.shutdownGrace)
+	defer soft.Stop()
+	softC := soft.C
+	if r.shutdownGrace <= 0 {
+		softC = nil
+	}
+
+	for {
+		select {
+		case <-drained:
+			return nil
+		case <-softC:
+			softC = nil
+			r.releaseLimiters()
+			cancelled := r.ops.cancelIdle()
+			r.logf("Shutdown grace period of %v elapsed, cancelled %d queued operation(s), waiting for in-flight requests until the hard deadline", r.shutdownGrace, cancelled)
+		case <-ctx.Done():
+			abandoned := r.ops.cancelAll()
+			if abandoned == 0 {
+				return nil
+			}
+			r.logf("Shutdown deadline reached, cancelled %d in-flight operation(s)", abandoned)
+			// Cancelled operations still unwind and may write to the audit
+			// log, so Close must not release it before they are done.
+			<-drained
+			return fmt.Errorf("%w: %d operation(s)", ErrOperationsAbandoned, abandoned)
+		}
+	}
+}
+
+func (r *noteRepository) releaseLimiters() {
+	for _, account := range r.postingAccounts() {
+		if account.rateLimiter != nil {
+			account.rateLimiter.release()
+		}
+	}
+	for _, limiter := range []*rateLimiter{r.rateLimiter, r.pollLimiter, r.searchLimiter} {
+		if limiter != nil {
+			limiter.release()
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package misskey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Close_Drain(t *testing.T) {
+	tests := []struct {
+		name              string
+		grace             time.Duration
+		hardDeadline      time.Duration
+		responseDelay     time.Duration
+		expectErr         error
+		expectPostErr     bool
+		expectCloseBefore time.Duration
+	}{
+		{"finishes within grace", 200 * time.Millisecond, time.Second, 20 * time.Millisecond, nil, false, 500 * time.Millisecond},
+		{"finishes after grace before hard deadline", 20 * time.Millisecond, time.Second, 100 * time.Millisecond, nil, false, 500 * time.Millisecond},
+		{"cancelled at hard deadline", 20 * time.Millisecond, 100 * time.Millisecond, time.Hour, ErrOperationsAbandoned, true, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tt.responseDelay):
+					w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+				case <-release:
+				}
+			}))
+			defer server.Close()
+			defer close(release)
+
+			repo := &noteRepository{
+				host:          server.URL,
+				authToken:     "test-token",
+				client:        &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:   newRateLimiter(3, 10*time.Second),
+				shutdownGrace: tt.grace,
+			}
+
+			postErr := make(chan error, 1)
+			go func() {
+				postErr <- repo.Post(context.Background(), entity.NewNote("In flight", entity.VisibilityHome))
+			}()
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.hardDeadline)
+			defer cancel()
+
+			start := time.Now()
+			err := repo.Close(ctx)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected close error %v, got %v", tt.expectErr, err)
+			}
+			if elapsed := time.Since(start); elapsed > tt.expectCloseBefore {
+				t.Errorf("expected Close to return within %v, took %v", tt.expectCloseBefore, elapsed)
+			}
+
+			if err := <-postErr; (err != nil) != tt.expectPostErr {
+				t.Errorf("expected post error=%v, got %v", tt.expectPostErr, err)
+			}
+			if err := repo.Post(context.Background(), entity.NewNote("Late", entity.VisibilityHome)); !errors.Is(err, ErrRepositoryClosed) {
+				t.Errorf("expected ErrRepositoryClosed after close, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Close_ReleasesLimiterWaiters(t *testing.T) {
+	tests := []struct {
+		name           string
+		waitLimiters   bool
+		expectQueued   error
+		expectCloseErr error
+	}{
+		{"queued posts released", false, ErrShuttingDown, nil},
+		{"queued posts wait for hard deadline", true, context.Canceled, ErrOperationsAbandoned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{}, 2)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				started <- struct{}{}
+				time.Sleep(100 * time.Millisecond)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:                   server.URL,
+				authToken:              "test-token",
+				client:                 &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:            newRateLimiter(1, time.Hour),
+				waitLimitersOnShutdown: tt.waitLimiters,
+			}
+
+			inFlight := make(chan error, 1)
+			go func() {
+				inFlight <- repo.Post(context.Background(), entity.NewNote("In flight", entity.VisibilityHome))
+			}()
+			<-started
+
+			queued := make(chan error, 1)
+			go func() {
+				queued <- repo.Post(context.Background(), entity.NewNote("Queued", entity.VisibilityHome))
+			}()
+			time.Sleep(20 * time.Millisecond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			if err := repo.Close(ctx); !errors.Is(err, tt.expectCloseErr) {
+				t.Errorf("expected close error %v, got %v", tt.expectCloseErr, err)
+			}
+
+			if err := <-inFlight; err != nil {
+				t.Errorf("expected in-flight post to finish, got %v", err)
+			}
+			if err := <-queued; !errors.Is(err, tt.expectQueued) {
+				t.Errorf("expected queued post error %v, got %v", tt.expectQueued, err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Close_CancelsQueuedAtGrace(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:                   server.URL,
+		authToken:              "test-token",
+		client:                 &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:            newRateLimiter(1, time.Hour),
+		shutdownGrace:          30 * time.Millisecond,
+		waitLimitersOnShutdown: true,
+	}
+
+	inFlight := make(chan error, 1)
+	go func() {
+		inFlight <- repo.Post(context.Background(), entity.NewNote("In flight", entity.VisibilityHome))
+	}()
+	<-started
+
+	queued := make(chan error, 1)
+	go func() {
+		queued <- repo.Post(context.Background(), entity.NewNote("Queued", entity.VisibilityHome))
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	closed := make(chan error, 1)
+	start := time.Now()
+	go func() { closed <- repo.Close(ctx) }()
+
+	select {
+	case err := <-queued:
+		if err == nil {
+			t.Error("expected the queued post to be cancelled")
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("expected the queued post to be cancelled at the grace period, took %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued post was not cancelled at the grace period")
+	}
+
+	if err := <-inFlight; err != nil {
+		t.Errorf("expected in-flight post to finish, got %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("expected close to succeed, got %v", err)
+	}
+}
+
+func TestRateLimiter_Release(t *testing.T) {
+	limiter := newRateLimiter(1, time.Hour)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	limiter.release()
+	limiter.release()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrShuttingDown) {
+			t.Errorf("expected ErrShuttingDown, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+	if err := limiter.Wait(context.Background()); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("expected ErrShuttingDown after release, got %v", err)
+	}
+}
+
+func TestNoteRepository_Close_AuditsCancelledOps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := openAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	var logs bytes.Buffer
+	repo := &noteRepository{
+		host:     "https://misskey.example",
+		auditLog: auditLog,
+		logger:   log.New(&logs, "", 0),
+	}
+
+	opCtx, done, err := repo.ops.begin(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		<-opCtx.Done()
+		time.Sleep(50 * time.Millisecond)
+		repo.audit(auditEntry{Action: "delete", NoteID: "note123"})
+		done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := repo.Close(ctx); !errors.Is(err, ErrOperationsAbandoned) {
+		t.Errorf("expected ErrOperationsAbandoned, got %v", err)
+	}
+
+	if strings.Contains(logs.String(), "Failed to record audit entry") {
+		t.Errorf("audit log was closed before the cancelled operation finished: %s", logs.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if !strings.Contains(string(data), `"noteId":"note123"`) {
+		t.Errorf("expected the cancelled operation's audit entry, got %q", data)
+	}
+}
@@ -0,0 +1,94 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	stateNamespaceDeletions = "misskey.deletions"
+	stateNamespaceCursors   = "misskey.cursors"
+	stateNamespaceContent   = "misskey.content"
+	stateNamespaceDead      = "misskey.deadletters"
+
+	stateKeyPins     = "pins"
+	stateKeyMentions = "mentions"
+	stateKeyChain    = "chain"
+)
+
+type persistedPins struct {
+	Current string   `json:"current"`
+	Stale   []string `json:"stale,omitempty"`
+}
+
+func (r *noteRepository) saveState(namespace, key string, value interface{}) {
+	if r.state == nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = r.state.Put(context.Background(), namespace, key, data)
+	}
+	if err != nil {
+		r.logf("Failed to persist state %s/%s: %v", namespace, key, err)
+	}
+}
+
+func (r *noteRepository) deleteState(namespace, key string) {
+	if r.state == nil {
+		return
+	}
+	if err := r.state.Delete(context.Background(), namespace, key); err != nil {
+		r.logf("Failed to remove state %s/%s: %v", namespace, key, err)
+	}
+}
+
+func (r *noteRepository) restoreState(ctx context.Context) error {
+	if r.state == nil {
+		return nil
+	}
+
+	deletions, err := r.state.List(ctx, stateNamespaceDeletions)
+	if err != nil {
+		return fmt.Errorf("failed to load pending deletions: %w", err)
+	}
+	for noteID, data := range deletions {
+		var d PendingDeletion
+		if err := json.Unmarshal(data, &d); err != nil {
+			r.logf("Skipping unreadable pending deletion [%s]: %v", noteID, err)
+			continue
+		}
+		r.scheduleDeletion(d)
+	}
+	if len(deletions) > 0 {
+		r.logf("Restored %d pending note deletion(s)", len(deletions))
+	}
+
+	if data, ok, err := r.state.Get(ctx, stateNamespaceCursors, stateKeyPins); err != nil {
+		return fmt.Errorf("failed to load pin state: %w", err)
+	} else if ok {
+		var pins persistedPins
+		if err := json.Unmarshal(data, &pins); err != nil {
+			return fmt.Errorf("failed to decode pin state: %w", err)
+		}
+		r.pins.current, r.pins.stale = pins.Current, pins.Stale
+	}
+
+	if data, ok, err := r.state.Get(ctx, stateNamespaceCursors, stateKeyMentions); err != nil {
+		return fmt.Errorf("failed to load mention cursor: %w", err)
+	} else if ok {
+		if err := json.Unmarshal(data, &r.mentions.lastID); err != nil {
+			return fmt.Errorf("failed to decode mention cursor: %w", err)
+		}
+	}
+
+	if data, ok, err := r.state.Get(ctx, stateNamespaceCursors, stateKeyChain); err != nil {
+		return fmt.Errorf("failed to load chain cursor: %w", err)
+	} else if ok {
+		if err := json.Unmarshal(data, &r.chain.lastID); err != nil {
+			return fmt.Errorf("failed to decode chain cursor: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/infrastructure/storage"
+)
+
+func TestNoteRepository_StateRestore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("failed to open state store: %v", err)
+	}
+
+	first := &noteRepository{host: server.URL, authToken: "test-token", client: &http.Client{Timeout: 30 * time.Second}, state: store}
+	first.scheduleDeletion(PendingDeletion{NoteID: "note1", DeleteAt: time.Now().Add(time.Hour)})
+	first.saveState(stateNamespaceCursors, stateKeyPins, persistedPins{Current: "pinned1"})
+	first.saveState(stateNamespaceCursors, stateKeyMentions, "cursor1")
+	first.Close(context.Background())
+
+	second := &noteRepository{host: server.URL, authToken: "test-token", client: &http.Client{Timeout: 30 * time.Second}, state: store}
+	if err := second.restoreState(context.Background()); err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+	defer second.Close(context.Background())
+
+	pending := second.PendingDeletions()
+	if len(pending) != 1 || pending[0].NoteID != "note1" {
+		t.Errorf("expected pending deletion to be restored, got %+v", pending)
+	}
+	if second.pins.current != "pinned1" {
+		t.Errorf("expected pin state to be restored, got %q", second.pins.current)
+	}
+	if second.mentions.lastID != "cursor1" {
+		t.Errorf("expected mention cursor to be restored, got %q", second.mentions.lastID)
+	}
+}
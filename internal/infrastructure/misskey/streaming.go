@@ -0,0 +1,228 @@
+package misskey
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const (
+	StreamChannelMain         = "main"
+	StreamChannelHomeTimeline = "homeTimeline"
+
+	maxStreamBackoff          = time.Minute
+	defaultStreamPingInterval = 30 * time.Second
+)
+
+type Event struct {
+	Channel string
+	Type    string
+	Note    *entity.Note
+	Body    json.RawMessage
+}
+
+type streamMessage struct {
+	Type string `json:"type"`
+	Body struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Body json.RawMessage `json:"body"`
+	} `json:"body"`
+}
+
+type streamConnectMessage struct {
+	Type string `json:"type"`
+	Body struct {
+		Channel string `json:"channel"`
+		ID      string `json:"id"`
+	} `json:"body"`
+}
+
+func (r *noteRepository) Stream(ctx context.Context) (<-chan Event, error) {
+	conn, channels, err := r.dialStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go r.runStream(ctx, conn, channels, events)
+	return events, nil
+}
+
+func (r *noteRepository) runStream(ctx context.Context, conn *websocket.Conn, channels map[string]string, events chan<- Event) {
+	defer close(events)
+
+	for {
+		err := r.readStream(ctx, conn, channels, events, r.pingInterval())
+		if ctx.Err() != nil {
+			return
+		}
+		r.logf("Streaming connection lost: %v", err)
+
+		var delay time.Duration
+		for attempt := 1; ; attempt++ {
+			delay = r.retryDelay(min(attempt, 16), delay)
+			if delay > maxStreamBackoff {
+				delay = maxStreamBackoff
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return
+			}
+
+			conn, channels, err = r.dialStream(ctx)
+			if err == nil {
+				r.logf("Streaming connection re-established after %d attempt(s)", attempt)
+				break
+			}
+			r.logf("Streaming reconnect failed (attempt %d): %v", attempt, err)
+		}
+	}
+}
+
+func (r *noteRepository) readStream(ctx context.Context, conn *websocket.Conn, channels map[string]string, events chan<- Event, pingInterval time.Duration) error {
+	deadline := 2 * pingInterval
+	extendDeadline := func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(deadline))
+	}
+	conn.SetPongHandler(extendDeadline)
+	extendDeadline("")
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		defer conn.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingInterval)); err != nil {
+					r.logf("Failed to send streaming ping: %v", err)
+				}
+			}
+		}
+	}()
+
+	for {
+		var msg streamMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		extendDeadline("")
+		if msg.Type != "channel" {
+			continue
+		}
+
+		event := Event{
+			Channel: channels[msg.Body.ID],
+			Type:    msg.Body.Type,
+			Body:    msg.Body.Body,
+		}
+		if isNoteEvent(event.Type) {
+			var note noteResponse
+			if err := json.Unmarshal(msg.Body.Body, &note); err == nil {
+				event.Note = note.toEntity()
+			}
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func isNoteEvent(eventType string) bool {
+	switch eventType {
+	case "note", "mention", "reply":
+		return true
+	}
+	return false
+}
+
+func (r *noteRepository) dialStream(ctx context.Context) (*websocket.Conn, map[string]string, error) {
+	streamURL, err := r.streamURL()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.checkHostAllowed(streamURL); err != nil {
+		return nil, nil, err
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 30 * time.Second,
+		TLSClientConfig:  r.clientTLSConfig(),
+	}
+	conn, resp, err := dialer.DialContext(ctx, streamURL, http.Header{"User-Agent": {r.userAgent()}})
+	if err != nil {
+		if resp != nil {
+			return nil, nil, fmt.Errorf("failed to connect to streaming API (status %d): %w", resp.StatusCode, err)
+		}
+		return nil, nil, fmt.Errorf("failed to connect to streaming API: %w", err)
+	}
+
+	channels := make(map[string]string)
+	for i, channel := range r.subscribedChannels() {
+		var msg streamConnectMessage
+		msg.Type = "connect"
+		msg.Body.Channel = channel
+		msg.Body.ID = strconv.Itoa(i)
+		if err := conn.WriteJSON(msg); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("failed to subscribe to channel %s: %w", channel, err)
+		}
+		channels[msg.Body.ID] = channel
+	}
+
+	return conn, channels, nil
+}
+
+func (r *noteRepository) streamURL() (string, error) {
+	u, err := url.Parse(r.baseURL())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse host: %w", err)
+	}
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/streaming"
+	u.RawQuery = url.Values{"i": {r.authToken}}.Encode()
+	return u.String(), nil
+}
+
+func (r *noteRepository) pingInterval() time.Duration {
+	if r.streamPingInterval > 0 {
+		return r.streamPingInterval
+	}
+	return defaultStreamPingInterval
+}
+
+func (r *noteRepository) subscribedChannels() []string {
+	if len(r.streamChannels) > 0 {
+		return r.streamChannels
+	}
+	return []string{StreamChannelMain}
+}
+
+func (r *noteRepository) clientTLSConfig() *tls.Config {
+	if transport, ok := baseTransport(r.client.Transport).(*http.Transport); ok && transport.TLSClientConfig != nil {
+		return transport.TLSClientConfig.Clone()
+	}
+	return nil
+}
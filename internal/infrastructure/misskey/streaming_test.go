@@ -0,0 +1,183 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestNoteRepository_Stream(t *testing.T) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/streaming" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if token := r.URL.Query().Get("i"); token != "test-token" {
+			t.Errorf("expected token 'test-token', got '%s'", token)
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var connect streamConnectMessage
+		if err := conn.ReadJSON(&connect); err != nil {
+			t.Errorf("failed to read connect message: %v", err)
+			return
+		}
+		if connect.Type != "connect" || connect.Body.Channel != StreamChannelMain {
+			t.Errorf("unexpected connect message: %+v", connect)
+		}
+
+		n := connections.Add(1)
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "channel", "body": {"id": "`+connect.Body.ID+`", "type": "mention", "body": {"id": "note`+string(rune('0'+n))+`", "text": "hello"}}}`))
+		if n == 1 {
+			return
+		}
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:         server.URL,
+		authToken:    "test-token",
+		client:       &http.Client{Timeout: 30 * time.Second},
+		retryBackoff: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := repo.Stream(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expectID := range []string{"note1", "note2"} {
+		event := <-events
+		if event.Channel != StreamChannelMain || event.Type != "mention" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+		if event.Note == nil || event.Note.ID != expectID {
+			t.Errorf("expected note %s, got %+v", expectID, event.Note)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected events channel to be closed after cancellation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("events channel was not closed after cancellation")
+	}
+}
+
+func TestNoteRepository_StreamURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{"bare host", "example.tld", "wss://example.tld/streaming?i=test-token"},
+		{"http host", "http://example.tld", "ws://example.tld/streaming?i=test-token"},
+		{"path prefix", "https://example.tld/misskey", "wss://example.tld/misskey/streaming?i=test-token"},
+		{"path prefix with slash", "https://example.tld/misskey/", "wss://example.tld/misskey/streaming?i=test-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &noteRepository{host: tt.host, authToken: "test-token"}
+			got, err := repo.streamURL()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Stream_HostNotAllowed(t *testing.T) {
+	repo := &noteRepository{
+		host:         "https://example.tld",
+		authToken:    "test-token",
+		client:       &http.Client{Timeout: 30 * time.Second},
+		allowedHosts: []string{"other.tld"},
+	}
+
+	if _, err := repo.Stream(context.Background()); err == nil {
+		t.Error("expected error for disallowed host, got nil")
+	}
+}
+
+func TestNoteRepository_Stream_MissedPongReconnects(t *testing.T) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		n := connections.Add(1)
+		if n == 1 {
+			conn.SetPingHandler(func(string) error { return nil })
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:               server.URL,
+		authToken:          "test-token",
+		client:             &http.Client{Timeout: 30 * time.Second},
+		retryBackoff:       time.Millisecond,
+		streamPingInterval: 20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := repo.Stream(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for connections.Load() < 2 {
+		select {
+		case <-deadline:
+			t.Fatal("expected reconnect after missed pongs")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := connections.Load(); n != 2 {
+		t.Errorf("expected responsive connection to stay open, got %d connections", n)
+	}
+
+	cancel()
+	for range events {
+	}
+}
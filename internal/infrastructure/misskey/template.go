@@ -0,0 +1,91 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type templateSegment struct {
+	literal string
+	field   string
+}
+
+func parseNoteTemplate(template string) ([]templateSegment, error) {
+	var segments []templateSegment
+	var literal strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		switch c := template[i]; {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(template[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed '{' at offset %d", ErrInvalidTemplate, i)
+			}
+			name := template[i+1 : i+end]
+			if name == "" || strings.ContainsAny(name, "{ \n") {
+				return nil, fmt.Errorf("%w: invalid field name %q at offset %d", ErrInvalidTemplate, name, i)
+			}
+			segments = append(segments, templateSegment{literal: literal.String()}, templateSegment{field: name})
+			literal.Reset()
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("%w: unmatched '}' at offset %d", ErrInvalidTemplate, i)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	return append(segments, templateSegment{literal: literal.String()}), nil
+}
+
+func renderTemplate(segments []templateSegment, fields map[string]string) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.field != "" {
+			b.WriteString(fields[seg.field])
+		} else {
+			b.WriteString(seg.literal)
+		}
+	}
+	return normalizeWhitespace(b.String())
+}
+
+func (r *noteRepository) RenderNote(ctx context.Context, template string, fields map[string]string) (*entity.Note, error) {
+	segments, err := parseNoteTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := r.instanceTextLimit(ctx)
+	text := renderTemplate(segments, fields)
+	if overflow := utf8.RuneCountInString(text) - limit; overflow > 0 {
+		longest := ""
+		for _, seg := range segments {
+			if seg.field != "" && utf8.RuneCountInString(fields[seg.field]) > utf8.RuneCountInString(fields[longest]) {
+				longest = seg.field
+			}
+		}
+
+		truncated := make(map[string]string, len(fields))
+		for k, v := range fields {
+			truncated[k] = v
+		}
+		truncated[longest] = entity.TruncateRunes(fields[longest], max(utf8.RuneCountInString(fields[longest])-overflow, 0))
+		text = renderTemplate(segments, truncated)
+
+		if length := utf8.RuneCountInString(text); length > limit {
+			return nil, fmt.Errorf("%w: rendered template is %d characters, limit is %d", ErrTextTooLong, length, limit)
+		}
+	}
+
+	return entity.NewNote(text, entity.VisibilityHome), nil
+}
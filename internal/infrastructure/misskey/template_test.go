@@ -0,0 +1,64 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNoteRepository_RenderNote(t *testing.T) {
+	fields := map[string]string{
+		"title":   "Go 1.26 released",
+		"link":    "https://go.dev/blog/go1.26",
+		"summary": "新しいリリースです",
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		fields    map[string]string
+		limit     int
+		instance  int
+		expected  string
+		expectErr error
+	}{
+		{"all fields", "📰 {title}\n{link}\n\n{summary}", fields, 0, 3000, "📰 Go 1.26 released\nhttps://go.dev/blog/go1.26\n\n新しいリリースです", nil},
+		{"missing field removed", "{title}\n\n{missing}\n\n{link}", fields, 0, 3000, "Go 1.26 released\n\nhttps://go.dev/blog/go1.26", nil},
+		{"escaped braces", "{{{title}}} {{literal}}", fields, 0, 3000, "{Go 1.26 released} {literal}", nil},
+		{"longest field truncated", "{title}\n{link}\n{summary}", map[string]string{"title": "T", "link": "https://a.tld", "summary": strings.Repeat("あ", 50)}, 30, 3000, "T\nhttps://a.tld\n" + strings.Repeat("あ", 13) + "…", nil},
+		{"instance limit applies", "{title}\n{link}\n{summary}", map[string]string{"title": "T", "link": "https://a.tld", "summary": strings.Repeat("あ", 50)}, 0, 30, "T\nhttps://a.tld\n" + strings.Repeat("あ", 13) + "…", nil},
+		{"cannot fit", "{title} fixed text that is far too long", fields, 10, 3000, "", ErrTextTooLong},
+		{"unclosed brace", "{title", fields, 0, 3000, "", ErrInvalidTemplate},
+		{"unmatched closing brace", "title}", fields, 0, 3000, "", ErrInvalidTemplate},
+		{"empty field name", "{}", fields, 0, 3000, "", ErrInvalidTemplate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"maxNoteTextLength": %d}`, tt.instance)
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{host: server.URL, client: server.Client(), maxTextLength: tt.limit}
+			note, err := repo.RenderNote(context.Background(), tt.template, tt.fields)
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr != nil {
+				return
+			}
+			if note.Text != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, note.Text)
+			}
+			if limit := repo.instanceTextLimit(context.Background()); utf8.RuneCountInString(note.Text) > limit {
+				t.Errorf("expected at most %d characters, got %d", limit, utf8.RuneCountInString(note.Text))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type ThreadFailureMode string
+
+const (
+	ThreadStopPartial      ThreadFailureMode = "stop"
+	ThreadContinueNewChain ThreadFailureMode = "continue"
+	ThreadRollback         ThreadFailureMode = "rollback"
+)
+
+func (m ThreadFailureMode) validate() error {
+	switch m {
+	case "", ThreadStopPartial, ThreadContinueNewChain, ThreadRollback:
+		return nil
+	}
+	return fmt.Errorf("unknown thread failure mode: %s", m)
+}
+
+type ThreadResult struct {
+	NoteIDs       []string
+	ChainBrokenAt int
+	FailedParts   []int
+	DeletedIDs    []string
+
+	// authTokens holds the token each of NoteIDs was posted with, so a
+	// rollback deletes every part as the account that owns it.
+	authTokens []string
+}
+
+func (t *ThreadResult) add(noteID, authToken string) {
+	t.NoteIDs = append(t.NoteIDs, noteID)
+	t.authTokens = append(t.authTokens, authToken)
+}
+
+// threadChain is the reply chain state shared by PostThread and attachment
+// overflow replies, so both honour maxThreadDepth.
+type threadChain struct {
+	rootID   string
+	parentID string
+	depth    int
+}
+
+func (c *threadChain) posted(noteID string) {
+	if c.parentID == "" {
+		c.rootID = noteID
+	}
+	c.parentID = noteID
+	c.depth++
+}
+
+// chainReply links note to the chain, or reports that the chain is past
+// maxThreadDepth and returns it as a top-level note linking to the root.
+func (r *noteRepository) chainReply(chain *threadChain, note entity.Note) (entity.Note, bool) {
+	switch {
+	case chain.parentID == "":
+	case r.maxThreadDepth > 0 && chain.depth > r.maxThreadDepth:
+		note.ReplyID = ""
+		note.Text = note.Text + "\n\n" + r.noteURL(chain.rootID)
+		return note, true
+	default:
+		note.ReplyID = chain.parentID
+	}
+	return note, false
+}
+
+func (r *noteRepository) PostThread(ctx context.Context, notes []*entity.Note) (*ThreadResult, error) {
+	result := &ThreadResult{ChainBrokenAt: -1}
+	errs := newMultiError("failed to post thread", len(notes))
+
+	var chain threadChain
+	for i, note := range notes {
+		next, broken := r.chainReply(&chain, *note)
+		if broken && result.ChainBrokenAt < 0 {
+			result.ChainBrokenAt = i
+			r.logf("Thread reached maximum depth %d, posting remaining %d note(s) as top-level notes", r.maxThreadDepth, len(notes)-i)
+		}
+
+		posted, err := r.PostWithOptions(ctx, &next, PostOptions{})
+		if err != nil {
+			err = fmt.Errorf("failed to post thread note %d/%d: %w", i+1, len(notes), err)
+			result.FailedParts = append(result.FailedParts, i)
+
+			switch r.threadFailureMode {
+			case ThreadContinueNewChain:
+				r.logf("Warning: %v, continuing the remaining parts as a new chain", err)
+				errs.Add(fmt.Sprintf("part %d", i+1), err)
+				chain = threadChain{}
+				continue
+			case ThreadRollback:
+				r.rollbackThread(ctx, result)
+			}
+			return result, err
+		}
+
+		result.add(posted.NoteID, r.tokenAt(posted.TokenIndex))
+		chain.posted(posted.NoteID)
+	}
+	return result, errs.ErrOrNil()
+}
+
+// PostDigest posts a digest of items as a thread sized to the instance text
+// limit.
+func (r *noteRepository) PostDigest(ctx context.Context, items []entity.DigestItem, visibility entity.NoteVisibility) (*ThreadResult, error) {
+	return r.PostThread(ctx, entity.BuildDigestThread(items, visibility, r.instanceTextLimit(ctx)))
+}
+
+// rollbackThread deletes the posted parts newest first. It keeps going when
+// ctx was cancelled, since that is often why the thread failed, but is
+// bounded by deletionTimeout.
+func (r *noteRepository) rollbackThread(ctx context.Context, result *ThreadResult) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deletionTimeout)
+	defer cancel()
+
+	for i, noteID := range slices.Backward(result.NoteIDs) {
+		if err := r.deleteNote(ctx, noteID, result.authTokens[i]); err != nil {
+			r.logf("Warning: Failed to roll back thread note [%s]: %v", noteID, err)
+			continue
+		}
+		result.DeletedIDs = append(result.DeletedIDs, noteID)
+	}
+	r.logf("Rolled back %d of %d posted thread note(s)", len(result.DeletedIDs), len(result.NoteIDs))
+}
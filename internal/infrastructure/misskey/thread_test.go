@@ -0,0 +1,250 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_PostThread(t *testing.T) {
+	tests := []struct {
+		name            string
+		notes           int
+		maxDepth        int
+		expectedReplies []interface{}
+		expectedBreak   int
+	}{
+		{"unlimited depth", 4, 0, []interface{}{nil, "note1", "note2", "note3"}, -1},
+		{"within limit", 3, 2, []interface{}{nil, "note1", "note2"}, -1},
+		{"depth limit reached", 5, 2, []interface{}{nil, "note1", "note2", nil, nil}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payloads []map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				payloads = append(payloads, payload)
+				fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(payloads))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:           server.URL,
+				authToken:      "test-token",
+				client:         &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:    newRateLimiter(10, 10*time.Second),
+				maxThreadDepth: tt.maxDepth,
+			}
+
+			var notes []*entity.Note
+			for i := 0; i < tt.notes; i++ {
+				notes = append(notes, entity.NewNote(fmt.Sprintf("Part %d", i+1), entity.VisibilityHome))
+			}
+
+			result, err := repo.PostThread(context.Background(), notes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.NoteIDs) != tt.notes {
+				t.Fatalf("expected %d note IDs, got %v", tt.notes, result.NoteIDs)
+			}
+			if result.ChainBrokenAt != tt.expectedBreak {
+				t.Errorf("expected chain to break at %d, got %d", tt.expectedBreak, result.ChainBrokenAt)
+			}
+			for i, payload := range payloads {
+				if payload["replyId"] != tt.expectedReplies[i] {
+					t.Errorf("note %d: expected replyId %v, got %v", i+1, tt.expectedReplies[i], payload["replyId"])
+				}
+				linksRoot := strings.HasSuffix(payload["text"].(string), server.URL+"/notes/note1")
+				if expectLink := tt.expectedBreak >= 0 && i >= tt.expectedBreak; linksRoot != expectLink {
+					t.Errorf("note %d: expected root link=%v, got text %q", i+1, expectLink, payload["text"])
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostThread_FailureModes(t *testing.T) {
+	tests := []struct {
+		name            string
+		mode            ThreadFailureMode
+		expectedIDs     []string
+		expectedFailed  []int
+		expectedDeleted []string
+		expectedReplies []interface{}
+	}{
+		{"stop partial", "", []string{"note1", "note2"}, []int{2}, nil, []interface{}{nil, "note1", "note2"}},
+		{"continue as new chain", ThreadContinueNewChain, []string{"note1", "note2", "note4", "note5"}, []int{2}, nil, []interface{}{nil, "note1", "note2", nil, "note4"}},
+		{"rollback", ThreadRollback, []string{"note1", "note2"}, []int{2}, []string{"note2", "note1"}, []interface{}{nil, "note1", "note2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payloads []map[string]interface{}
+			var deleted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+
+				if r.URL.Path == "/api/notes/delete" {
+					deleted = append(deleted, payload["noteId"].(string))
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				payloads = append(payloads, payload)
+				if payload["text"] == "Part 3" {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":{"code":"CONTAINS_PROHIBITED_WORDS","message":"Cannot post because it contains prohibited words."}}`))
+					return
+				}
+				fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(payloads))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:              server.URL,
+				authToken:         "test-token",
+				client:            &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:       newRateLimiter(10, 10*time.Second),
+				threadFailureMode: tt.mode,
+			}
+
+			var notes []*entity.Note
+			for i := 0; i < 5; i++ {
+				notes = append(notes, entity.NewNote(fmt.Sprintf("Part %d", i+1), entity.VisibilityHome))
+			}
+
+			result, err := repo.PostThread(context.Background(), notes)
+			if err == nil {
+				t.Fatal("expected error for the failed part, got nil")
+			}
+			if !slices.Equal(result.NoteIDs, tt.expectedIDs) {
+				t.Errorf("expected posted IDs %v, got %v", tt.expectedIDs, result.NoteIDs)
+			}
+			if !slices.Equal(result.FailedParts, tt.expectedFailed) {
+				t.Errorf("expected failed parts %v, got %v", tt.expectedFailed, result.FailedParts)
+			}
+			if !slices.Equal(result.DeletedIDs, tt.expectedDeleted) || !slices.Equal(deleted, tt.expectedDeleted) {
+				t.Errorf("expected deleted %v, got result %v and requests %v", tt.expectedDeleted, result.DeletedIDs, deleted)
+			}
+			if len(payloads) != len(tt.expectedReplies) {
+				t.Fatalf("expected %d create requests, got %d", len(tt.expectedReplies), len(payloads))
+			}
+			for i, payload := range payloads {
+				if payload["replyId"] != tt.expectedReplies[i] {
+					t.Errorf("request %d: expected replyId %v, got %v", i+1, tt.expectedReplies[i], payload["replyId"])
+				}
+			}
+		})
+	}
+}
+
+func TestNoteRepository_PostThread_RollbackPerAccount(t *testing.T) {
+	var mu sync.Mutex
+	created := 0
+	deletedBy := map[string]interface{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/api/notes/delete" {
+			deletedBy[payload["noteId"].(string)] = payload["i"]
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if payload["text"] == "Part 3" {
+			cancel()
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"CONTAINS_PROHIBITED_WORDS","message":"Cannot post because it contains prohibited words."}}`))
+			return
+		}
+		created++
+		fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, created)
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:              server.URL,
+		authToken:         "token-a",
+		client:            &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:       newRateLimiter(10, 10*time.Second),
+		threadFailureMode: ThreadRollback,
+		accounts: []*postingAccount{
+			{authToken: "token-a", rateLimiter: newRateLimiter(10, 10*time.Second)},
+			{authToken: "token-b", rateLimiter: newRateLimiter(10, 10*time.Second)},
+		},
+	}
+
+	var notes []*entity.Note
+	for i := 0; i < 3; i++ {
+		notes = append(notes, entity.NewNote(fmt.Sprintf("Part %d", i+1), entity.VisibilityHome))
+	}
+
+	result, err := repo.PostThread(ctx, notes)
+	if err == nil {
+		t.Fatal("expected error for the failed part, got nil")
+	}
+	if !slices.Equal(result.DeletedIDs, []string{"note2", "note1"}) {
+		t.Errorf("expected both parts to be rolled back after cancellation, got %v", result.DeletedIDs)
+	}
+	if deletedBy["note1"] != "token-a" || deletedBy["note2"] != "token-b" {
+		t.Errorf("expected each part deleted by its own account, got %v", deletedBy)
+	}
+}
+
+func TestNoteRepository_PostDigest(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		fmt.Fprintf(w, `{"createdNote": {"id": "note%d"}}`, len(payloads))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:           server.URL,
+		authToken:      "test-token",
+		client:         &http.Client{Timeout: 30 * time.Second},
+		rateLimiter:    newRateLimiter(10, 10*time.Second),
+		maxTextLength:  80,
+		maxThreadDepth: 1,
+	}
+
+	var items []entity.DigestItem
+	for i := 0; i < 6; i++ {
+		items = append(items, entity.DigestItem{Title: fmt.Sprintf("Item %d", i+1), Link: fmt.Sprintf("https://example.com/%d", i+1)})
+	}
+	pages := len(entity.BuildDigestThread(items, entity.VisibilityHome, 80))
+	if pages < 3 {
+		t.Fatalf("expected the digest to need at least 3 pages, got %d", pages)
+	}
+
+	result, err := repo.PostDigest(context.Background(), items, entity.VisibilityHome)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.NoteIDs) != pages || result.ChainBrokenAt != 2 {
+		t.Errorf("expected %d notes with the chain broken at 2, got %v broken at %d", pages, result.NoteIDs, result.ChainBrokenAt)
+	}
+	if payloads[1]["replyId"] != "note1" || payloads[2]["replyId"] != nil {
+		t.Errorf("expected only the second page to reply, got %v and %v", payloads[1]["replyId"], payloads[2]["replyId"])
+	}
+}
@@ -0,0 +1,63 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const timelinePageSize = 100
+
+// HomeTimeline returns up to limit notes, newest first. With a sinceID
+// Misskey pages oldest first, so those pages are walked forward and the
+// result is reversed.
+func (r *noteRepository) HomeTimeline(ctx context.Context, sinceID string, limit int) ([]*entity.Note, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	if r.pollLimiter != nil {
+		if remaining, err := r.pollLimiter.WaitRemaining(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", &RateLimitWaitError{Remaining: remaining, Err: err})
+		}
+	}
+
+	var notes []*entity.Note
+	forward := sinceID != ""
+	untilID := ""
+	for len(notes) < limit {
+		pageSize := min(timelinePageSize, limit-len(notes))
+		params := map[string]interface{}{"limit": pageSize}
+		if sinceID != "" {
+			params["sinceId"] = sinceID
+		}
+		if untilID != "" {
+			params["untilId"] = untilID
+		}
+
+		var page []noteResponse
+		if err := r.call(ctx, "notes/timeline", params, &page); err != nil {
+			return notes, fmt.Errorf("failed to fetch home timeline: %w", err)
+		}
+		for _, n := range page {
+			notes = append(notes, n.toEntity())
+			switch {
+			case forward && n.ID > sinceID:
+				sinceID = n.ID
+			case !forward && (untilID == "" || n.ID < untilID):
+				untilID = n.ID
+			}
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	if forward {
+		for i, j := 0, len(notes)-1; i < j; i, j = i+1, j-1 {
+			notes[i], notes[j] = notes[j], notes[i]
+		}
+	}
+	return notes, nil
+}
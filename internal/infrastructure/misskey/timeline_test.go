@@ -0,0 +1,189 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func newTimelineServer(ids []string, requests *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*requests = append(*requests, body)
+
+		limit := int(body["limit"].(float64))
+		sinceID, _ := body["sinceId"].(string)
+		untilID, _ := body["untilId"].(string)
+
+		order := ids
+		if sinceID != "" && untilID == "" {
+			order = make([]string, 0, len(ids))
+			for i := len(ids) - 1; i >= 0; i-- {
+				order = append(order, ids[i])
+			}
+		}
+
+		var page []map[string]interface{}
+		for _, id := range order {
+			if len(page) == limit {
+				break
+			}
+			if (sinceID != "" && id <= sinceID) || (untilID != "" && id >= untilID) {
+				continue
+			}
+			page = append(page, map[string]interface{}{
+				"id":     id,
+				"text":   "note " + id,
+				"userId": "user-" + id,
+				"user":   map[string]string{"username": "alice", "host": "remote.tld"},
+			})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestNoteRepository_HomeTimeline(t *testing.T) {
+	ids := []string{"n9", "n8", "n7", "n6", "n5", "n4", "n3", "n2", "n1"}
+
+	tests := []struct {
+		name          string
+		sinceID       string
+		limit         int
+		expectedIDs   []string
+		expectedCalls int
+	}{
+		{"single page", "", 3, []string{"n9", "n8", "n7"}, 1},
+		{"since id stops early", "n6", 10, []string{"n9", "n8", "n7"}, 1},
+		{"zero limit", "", 0, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]interface{}
+			server := newTimelineServer(ids, &requests)
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			notes, err := repo.HomeTimeline(context.Background(), tt.sinceID, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(notes) != len(tt.expectedIDs) {
+				t.Fatalf("expected %d notes, got %d", len(tt.expectedIDs), len(notes))
+			}
+			for i, note := range notes {
+				if note.ID != tt.expectedIDs[i] {
+					t.Errorf("expected note %d to be %s, got %s", i, tt.expectedIDs[i], note.ID)
+				}
+			}
+			if len(requests) != tt.expectedCalls {
+				t.Errorf("expected %d requests, got %d", tt.expectedCalls, len(requests))
+			}
+		})
+	}
+}
+
+func TestNoteRepository_HomeTimeline_Paginates(t *testing.T) {
+	ids := make([]string, 0, 150)
+	for i := 150; i > 0; i-- {
+		ids = append(ids, fmt.Sprintf("n%03d", i))
+	}
+
+	var requests []map[string]interface{}
+	server := newTimelineServer(ids, &requests)
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	notes, err := repo.HomeTimeline(context.Background(), "", 120)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 120 {
+		t.Fatalf("expected 120 notes, got %d", len(notes))
+	}
+	if len(requests) != 2 || requests[1]["untilId"] != "n051" || requests[1]["limit"] != float64(20) {
+		t.Errorf("expected second page until n051 with limit 20, got %v", requests)
+	}
+	if notes[0].UserID != "user-n150" || notes[0].Username != "alice@remote.tld" || notes[0].Text != "note n150" {
+		t.Errorf("unexpected note fields: %+v", notes[0])
+	}
+}
+
+func TestNoteRepository_HomeTimeline_PaginatesSinceID(t *testing.T) {
+	ids := make([]string, 0, 300)
+	for i := 300; i > 0; i-- {
+		ids = append(ids, fmt.Sprintf("n%03d", i))
+	}
+
+	var requests []map[string]interface{}
+	server := newTimelineServer(ids, &requests)
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	notes, err := repo.HomeTimeline(context.Background(), "n050", 250)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 250 {
+		t.Fatalf("expected 250 notes, got %d", len(notes))
+	}
+	for i, note := range notes {
+		if expected := fmt.Sprintf("n%03d", 300-i); note.ID != expected {
+			t.Fatalf("expected note %d to be %s, got %s", i, expected, note.ID)
+		}
+	}
+	if len(requests) != 3 || requests[1]["sinceId"] != "n150" || requests[2]["sinceId"] != "n250" || requests[2]["limit"] != float64(50) {
+		t.Errorf("expected pages since n050, n150 and n250, got %v", requests)
+	}
+	for _, req := range requests {
+		if _, ok := req["untilId"]; ok {
+			t.Errorf("expected no untilId when paging forward, got %v", req)
+		}
+	}
+}
+
+func TestNoteRepository_Renote(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"createdNote": {"id": "renote1"}}`))
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:        server.URL,
+		authToken:   "test-token",
+		client:      &http.Client{Timeout: 30 * time.Second},
+		rateLimiter: newRateLimiter(3, 10*time.Second),
+	}
+
+	result, err := repo.Renote(context.Background(), "n9", entity.VisibilityHome)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.NoteID != "renote1" || body["renoteId"] != "n9" || body["visibility"] != "home" {
+		t.Errorf("unexpected renote: result %+v, payload %v", result, body)
+	}
+}
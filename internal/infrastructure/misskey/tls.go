@@ -0,0 +1,119 @@
+package misskey
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type TLSConfig struct {
+	RootCAs            *x509.CertPool
+	MinVersion         uint16
+	PinnedSPKISHA256   []string
+	InsecureSkipVerify bool
+}
+
+func (c TLSConfig) isZero() bool {
+	return c.RootCAs == nil && c.MinVersion == 0 && len(c.PinnedSPKISHA256) == 0 && !c.InsecureSkipVerify
+}
+
+func newHTTPClient(cfg TLSConfig, redirects RedirectPolicy) (*http.Client, error) {
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Timeout:       30 * time.Second,
+		Transport:     transport,
+		CheckRedirect: redirects.checkRedirect(),
+	}, nil
+}
+
+func buildTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	minVersion := cfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	switch minVersion {
+	case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+	default:
+		return nil, fmt.Errorf("invalid TLS minimum version: %#04x", cfg.MinVersion)
+	}
+
+	pins, err := decodeSPKIPins(cfg.PinnedSPKISHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:            cfg.RootCAs,
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if len(pins) > 0 {
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			return verifyConnectionPins(cs, pins, cfg.InsecureSkipVerify)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func decodeSPKIPins(encoded []string) ([][]byte, error) {
+	pins := make([][]byte, 0, len(encoded))
+	for _, pin := range encoded {
+		decoded, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPKI pin %q: %w", pin, err)
+		}
+		if len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid SPKI pin %q: expected %d bytes, got %d", pin, sha256.Size, len(decoded))
+		}
+		pins = append(pins, decoded)
+	}
+	return pins, nil
+}
+
+// verifyConnectionPins only trusts certificates the handshake verified, so
+// a server cannot pass by appending a pinned certificate to its chain. When
+// verification is skipped there are no verified chains and only the leaf
+// is checked.
+func verifyConnectionPins(cs tls.ConnectionState, pins [][]byte, insecure bool) error {
+	if insecure {
+		if len(cs.PeerCertificates) == 0 {
+			return ErrSPKIPinMismatch
+		}
+		return verifySPKIPins(cs.PeerCertificates[:1], pins)
+	}
+	for _, chain := range cs.VerifiedChains {
+		if verifySPKIPins(chain, pins) == nil {
+			return nil
+		}
+	}
+	return ErrSPKIPinMismatch
+}
+
+func verifySPKIPins(certs []*x509.Certificate, pins [][]byte) error {
+	for _, cert := range certs {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, pin := range pins {
+			if string(sum[:]) == string(pin) {
+				return nil
+			}
+		}
+	}
+	return ErrSPKIPinMismatch
+}
+
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,230 @@
+package misskey
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestBuildTLSConfig_Validation(t *testing.T) {
+	validPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	tests := []struct {
+		name      string
+		cfg       TLSConfig
+		expectErr bool
+	}{
+		{"defaults", TLSConfig{}, false},
+		{"tls 1.3", TLSConfig{MinVersion: tls.VersionTLS13}, false},
+		{"invalid version", TLSConfig{MinVersion: 0x1234}, true},
+		{"valid pin", TLSConfig{PinnedSPKISHA256: []string{validPin}}, false},
+		{"pin not base64", TLSConfig{PinnedSPKISHA256: []string{"not-base64!"}}, true},
+		{"pin wrong length", TLSConfig{PinnedSPKISHA256: []string{base64.StdEncoding.EncodeToString([]byte("short"))}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := buildTLSConfig(tt.cfg)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tlsConfig.MinVersion < tls.VersionTLS12 && tt.cfg.MinVersion == 0 {
+				t.Errorf("expected default minimum version TLS 1.2, got %#04x", tlsConfig.MinVersion)
+			}
+		})
+	}
+}
+
+func TestNewNoteRepository_InvalidTLSConfig(t *testing.T) {
+	_, err := NewNoteRepository(Config{
+		Host:      "example.tld",
+		AuthToken: "test-token",
+		TLS:       TLSConfig{MinVersion: 0x1234},
+	})
+	if err == nil {
+		t.Error("expected error for invalid TLS configuration, got nil")
+	}
+}
+
+func TestNoteRepository_Post_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	tests := []struct {
+		name      string
+		cfg       TLSConfig
+		expectErr bool
+	}{
+		{"unknown CA rejected", TLSConfig{}, true},
+		{"custom CA accepted", TLSConfig{RootCAs: pool}, false},
+		{"matching pin accepted", TLSConfig{RootCAs: pool, PinnedSPKISHA256: []string{SPKIPin(server.Certificate())}}, false},
+		{"mismatched pin rejected", TLSConfig{RootCAs: pool, PinnedSPKISHA256: []string{otherPin}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noteRepo, err := NewNoteRepository(Config{
+				Host:      server.URL,
+				AuthToken: "test-token",
+				TLS:       tt.cfg,
+			})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = noteRepo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome))
+			if tt.expectErr && err == nil {
+				t.Error("expected TLS error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectErr && err != nil && !strings.Contains(err.Error(), "certificate") {
+				t.Errorf("expected certificate error, got %v", err)
+			}
+		})
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) issueLeaf(t *testing.T, extra ...*x509.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create leaf certificate: %v", err)
+	}
+	chain := [][]byte{der}
+	for _, cert := range extra {
+		chain = append(chain, cert.Raw)
+	}
+	return tls.Certificate{Certificate: chain, PrivateKey: key}
+}
+
+func TestNoteRepository_Post_PinnedChain(t *testing.T) {
+	pinnedCA := newTestCA(t, "pinned CA")
+	otherCA := newTestCA(t, "other CA")
+	pool := x509.NewCertPool()
+	pool.AddCert(pinnedCA.cert)
+	pool.AddCert(otherCA.cert)
+
+	tests := []struct {
+		name      string
+		cert      tls.Certificate
+		insecure  bool
+		expectErr bool
+	}{
+		{"leaf from pinned CA", pinnedCA.issueLeaf(t), false, false},
+		{"pinned CA appended to other chain", otherCA.issueLeaf(t, pinnedCA.cert), false, true},
+		{"insecure checks only the leaf", otherCA.issueLeaf(t, pinnedCA.cert), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{tt.cert}}
+			server.StartTLS()
+			defer server.Close()
+
+			noteRepo, err := NewNoteRepository(Config{
+				Host:      server.URL,
+				AuthToken: "test-token",
+				TLS: TLSConfig{
+					RootCAs:            pool,
+					PinnedSPKISHA256:   []string{SPKIPin(pinnedCA.cert)},
+					InsecureSkipVerify: tt.insecure,
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = noteRepo.Post(ctx, entity.NewNote("Test", entity.VisibilityHome))
+			if tt.expectErr && !errors.Is(err, ErrSPKIPinMismatch) {
+				t.Errorf("expected ErrSPKIPinMismatch, got %v", err)
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package misskey
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const zeroWidthJoiner = '\u200d'
+
+type TruncateOptions struct {
+	Ellipsis     string
+	PreserveLink bool
+	CountBytes   bool
+}
+
+func (o TruncateOptions) ellipsis() string {
+	if o.Ellipsis == "" {
+		return truncationEllipsis
+	}
+	return o.Ellipsis
+}
+
+func (o TruncateOptions) measure(s string) int {
+	if o.CountBytes {
+		return len(s)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// TruncateText shortens text to at most limit characters, or bytes with
+// CountBytes, never splitting a grapheme cluster. With PreserveLink the last
+// URL and everything after it are kept and the text before it is shortened.
+func TruncateText(text string, limit int, opts TruncateOptions) string {
+	if opts.measure(text) <= limit {
+		return text
+	}
+
+	if opts.PreserveLink {
+		if spans := findLinks(text); len(spans) > 0 {
+			body := strings.TrimRightFunc(text[:spans[len(spans)-1].start], unicode.IsSpace)
+			tail := text[len(body):]
+			link := strings.TrimLeftFunc(tail, unicode.IsSpace)
+
+			if available := limit - opts.measure(tail); available > opts.measure(opts.ellipsis()) {
+				return cutText(body, available, opts) + tail
+			}
+			if opts.measure(link) <= limit {
+				return link
+			}
+		}
+	}
+	return cutText(text, limit, opts)
+}
+
+func cutText(s string, limit int, opts TruncateOptions) string {
+	if opts.measure(s) <= limit {
+		return s
+	}
+
+	ellipsis := opts.ellipsis()
+	keep := limit - opts.measure(ellipsis)
+	if keep <= 0 {
+		return prefixWithin(ellipsis, limit, opts)
+	}
+	return strings.TrimRightFunc(prefixWithin(s, keep, opts), unicode.IsSpace) + ellipsis
+}
+
+func prefixWithin(s string, limit int, opts TruncateOptions) string {
+	end, used := 0, 0
+	for end < len(s) {
+		_, size := utf8.DecodeRuneInString(s[end:])
+		width := 1
+		if opts.CountBytes {
+			width = size
+		}
+		if used+width > limit {
+			break
+		}
+		used += width
+		end += size
+	}
+
+	for end > 0 && end < len(s) && !isGraphemeBoundary(s, end) {
+		_, size := utf8.DecodeLastRuneInString(s[:end])
+		end -= size
+	}
+	return s[:end]
+}
+
+func isGraphemeBoundary(s string, at int) bool {
+	prev, _ := utf8.DecodeLastRuneInString(s[:at])
+	next, _ := utf8.DecodeRuneInString(s[at:])
+
+	if prev == zeroWidthJoiner || isGraphemeExtender(next) {
+		return false
+	}
+	if isRegionalIndicator(prev) && isRegionalIndicator(next) {
+		count := 0
+		for rest := s[:at]; rest != ""; {
+			r, size := utf8.DecodeLastRuneInString(rest)
+			if !isRegionalIndicator(r) {
+				break
+			}
+			count++
+			rest = rest[:len(rest)-size]
+		}
+		return count%2 == 0
+	}
+	return true
+}
+
+func isGraphemeExtender(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r == zeroWidthJoiner ||
+		(r >= 0xfe00 && r <= 0xfe0f) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,53 @@
+package misskey
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	link := "https://example.tld/item/1"
+
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		opts     TruncateOptions
+		expected string
+	}{
+		{"within limit", "short", 10, TruncateOptions{}, "short"},
+		{"default ellipsis", "hello world", 8, TruncateOptions{}, "hello w…"},
+		{"custom ellipsis", "hello world", 8, TruncateOptions{Ellipsis: "..."}, "hello..."},
+		{"trailing space trimmed before ellipsis", "hello world", 7, TruncateOptions{}, "hello…"},
+		{"link preserved", "長い本文がここに続きます\n" + link, 20 + utf8.RuneCountInString(link), TruncateOptions{PreserveLink: true}, "長い本文がここに続きます\n" + link},
+		{"body cut before link", "長い本文がここに続きます\n" + link, 6 + utf8.RuneCountInString(link), TruncateOptions{PreserveLink: true}, "長い本文…\n" + link},
+		{"only link fits", "本文\n" + link, utf8.RuneCountInString(link) + 1, TruncateOptions{PreserveLink: true}, link},
+		{"link too long falls back", "body " + link, 8, TruncateOptions{PreserveLink: true}, "body ht…"},
+		{"no link with preserve", "hello world", 8, TruncateOptions{PreserveLink: true}, "hello w…"},
+		{"link lost without preserve", "body text " + link, 12, TruncateOptions{}, "body text h…"},
+		{"combining mark kept whole", "cafe\u0301 au lait", 5, TruncateOptions{}, "caf…"},
+		{"zwj sequence kept whole", "ab👩‍💻cd", 4, TruncateOptions{}, "ab…"},
+		{"flag pair kept whole", "a🇯🇵🇺🇸b", 4, TruncateOptions{}, "a🇯🇵…"},
+		{"byte limit", "こんにちは", 10, TruncateOptions{CountBytes: true}, "こん…"},
+		{"limit below ellipsis", "hello", 2, TruncateOptions{Ellipsis: "..."}, ".."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.text, tt.limit, tt.opts)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if length := tt.opts.measure(got); length > tt.limit {
+				t.Errorf("expected at most %d, got %d", tt.limit, length)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("expected valid UTF-8, got %q", got)
+			}
+			if tt.opts.PreserveLink && strings.Contains(tt.text, link) && tt.limit >= utf8.RuneCountInString(link) && !strings.Contains(got, link) {
+				t.Errorf("expected link to survive, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package misskey
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlCache[T any] struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	value     T
+	fetchedAt time.Time
+}
+
+func (c *ttlCache[T]) get(now time.Time) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetchedAt.IsZero() || now.Sub(c.fetchedAt) >= c.ttl {
+		var zero T
+		return zero, false
+	}
+	return c.value, true
+}
+
+func (c *ttlCache[T]) set(value T, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.value = value
+	c.fetchedAt = now
+}
+
+func (c *ttlCache[T]) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero T
+	c.value = zero
+	c.fetchedAt = time.Time{}
+}
+
+// keyedTTLCache keeps one ttlCache per key and drops the expired ones whenever
+// a key is set, so a long-running process does not accumulate them.
+type keyedTTLCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*ttlCache[T]
+}
+
+func (c *keyedTTLCache[T]) get(key string, now time.Time) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return entry.get(now)
+}
+
+func (c *keyedTTLCache[T]) set(key string, value T, ttl time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]*ttlCache[T])
+	}
+	for k, entry := range c.entries {
+		if _, ok := entry.get(now); !ok {
+			delete(c.entries, k)
+		}
+	}
+	entry := &ttlCache[T]{ttl: ttl}
+	entry.set(value, now)
+	c.entries[key] = entry
+}
+
+func (c *keyedTTLCache[T]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
@@ -0,0 +1,54 @@
+package misskey
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLCache_Expiry(t *testing.T) {
+	cache := ttlCache[int]{ttl: time.Minute}
+	now := time.Now()
+
+	if _, ok := cache.get(now); ok {
+		t.Error("expected empty cache miss")
+	}
+
+	cache.set(1, now)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"within ttl", now.Add(30 * time.Second), true},
+		{"at ttl", now.Add(time.Minute), false},
+		{"after ttl", now.Add(2 * time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := cache.get(tt.at); ok != tt.expected {
+				t.Errorf("expected hit=%v, got %v", tt.expected, ok)
+			}
+		})
+	}
+}
+
+func TestKeyedTTLCache_DropsExpiredKeys(t *testing.T) {
+	var cache keyedTTLCache[int]
+	now := time.Now()
+
+	cache.set("a", 1, time.Minute, now)
+	cache.set("b", 2, time.Hour, now)
+	if got, ok := cache.get("a", now.Add(30*time.Second)); !ok || got != 1 {
+		t.Errorf("expected hit for a, got %d ok=%v", got, ok)
+	}
+
+	cache.set("c", 3, time.Minute, now.Add(2*time.Minute))
+	if _, ok := cache.get("a", now.Add(2*time.Minute)); ok {
+		t.Error("expected a to expire")
+	}
+	if got := cache.len(); got != 2 {
+		t.Errorf("expected expired keys to be dropped, got %d entries", got)
+	}
+}
@@ -0,0 +1,125 @@
+package misskey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+type driveFileResponse struct {
+	ID string `json:"id"`
+}
+
+func (r *noteRepository) UploadFileReader(ctx context.Context, name string, reader io.Reader, size int64, contentType string) (string, error) {
+	return r.uploadFileReader(ctx, r.authToken, name, reader, size, contentType)
+}
+
+func (r *noteRepository) uploadFileReader(ctx context.Context, authToken, name string, reader io.Reader, size int64, contentType string) (string, error) {
+	ctx, done, err := r.ops.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	account := r.accountByToken(authToken)
+	if account.suspended.Load() {
+		return "", ErrAccountSuspended
+	}
+
+	body, contentLength, boundary, err := multipartUploadBody(authToken, name, reader, size, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	endpointURL := r.endpointURL("drive/files/create")
+	if err := r.checkHostAllowed(endpointURL); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpointURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.ContentLength = contentLength
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+	req.Header.Set("User-Agent", r.userAgent())
+
+	defer r.ops.sending(ctx)()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file %s: %w", name, classifyNetError(err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read Misskey API response: %w", err)
+	}
+	r.observeRateLimit(account, "drive/files/create", resp.Header)
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := parseAPIError(resp.StatusCode, respBody)
+		if isAccountSuspendedCode(apiErr.Code) {
+			r.markSuspended(account, apiErr)
+			return "", fmt.Errorf("%w: %w", ErrAccountSuspended, apiErr)
+		}
+		if resp.StatusCode == http.StatusRequestEntityTooLarge {
+			return "", fmt.Errorf("%w: %s (%d bytes): %w", ErrFileTooLarge, name, size, apiErr)
+		}
+		return "", fmt.Errorf("failed to upload file %s: %w", name, apiErr)
+	}
+
+	var file driveFileResponse
+	if err := json.Unmarshal(respBody, &file); err != nil {
+		return "", fmt.Errorf("failed to decode Misskey API response: %w", err)
+	}
+
+	r.logf("Uploaded drive file [%s] %s", file.ID, name)
+	return file.ID, nil
+}
+
+func multipartUploadBody(authToken, name string, reader io.Reader, size int64, contentType string) (io.Reader, int64, string, error) {
+	var head bytes.Buffer
+	mw := multipart.NewWriter(&head)
+
+	if err := mw.WriteField("i", authToken); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	if err := mw.WriteField("name", name); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(name)))
+	header.Set("Content-Type", contentType)
+	if _, err := mw.CreatePart(header); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	prefix := bytes.Clone(head.Bytes())
+
+	head.Reset()
+	if err := mw.Close(); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to build upload request: %w", err)
+	}
+	suffix := bytes.Clone(head.Bytes())
+
+	if size < 0 {
+		return io.MultiReader(bytes.NewReader(prefix), reader, bytes.NewReader(suffix)), -1, mw.Boundary(), nil
+	}
+
+	body := io.MultiReader(bytes.NewReader(prefix), io.LimitReader(reader, size), bytes.NewReader(suffix))
+	return body, int64(len(prefix)) + size + int64(len(suffix)), mw.Boundary(), nil
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
@@ -0,0 +1,107 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_UploadFileReader(t *testing.T) {
+	content := strings.Repeat("video-bytes", 1000)
+
+	tests := []struct {
+		name           string
+		size           int64
+		status         int
+		body           string
+		expectedID     string
+		expectErr      error
+		expectChunked  bool
+		expectedLength int
+	}{
+		{"known size", int64(len(content)), http.StatusOK, `{"id":"file1"}`, "file1", nil, false, len(content)},
+		{"known size shorter than reader", 5, http.StatusOK, `{"id":"file1"}`, "file1", nil, false, 5},
+		{"unknown size", -1, http.StatusOK, `{"id":"file1"}`, "file1", nil, true, len(content)},
+		{"too large", int64(len(content)), http.StatusRequestEntityTooLarge, ``, "", ErrFileTooLarge, false, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]string
+			var fileData []byte
+			var fileType string
+			var chunked bool
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/drive/files/create" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				chunked = r.ContentLength < 0
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+
+				reader, err := r.MultipartReader()
+				if err != nil {
+					t.Errorf("expected multipart body: %v", err)
+					return
+				}
+				fields = map[string]string{}
+				for {
+					part, err := reader.NextPart()
+					if err != nil {
+						break
+					}
+					data, _ := io.ReadAll(part)
+					if part.FormName() == "file" {
+						fileData = data
+						fileType = part.Header.Get("Content-Type")
+						continue
+					}
+					fields[part.FormName()] = string(data)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			id, err := repo.UploadFileReader(context.Background(), "clip.mp4", strings.NewReader(content), tt.size, "video/mp4")
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if id != tt.expectedID {
+				t.Errorf("expected file ID '%s', got '%s'", tt.expectedID, id)
+			}
+			if chunked != tt.expectChunked {
+				t.Errorf("expected chunked=%v, got %v", tt.expectChunked, chunked)
+			}
+			if fields["i"] != "test-token" || fields["name"] != "clip.mp4" {
+				t.Errorf("unexpected form fields: %v", fields)
+			}
+			if string(fileData) != content[:tt.expectedLength] {
+				t.Errorf("expected %d file bytes, got %d", tt.expectedLength, len(fileData))
+			}
+			if fileType != "video/mp4" {
+				t.Errorf("expected content type 'video/mp4', got '%s'", fileType)
+			}
+		})
+	}
+}
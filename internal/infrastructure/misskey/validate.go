@@ -0,0 +1,84 @@
+package misskey
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+const (
+	minPollChoices      = 2
+	maxPollChoices      = 10
+	maxPollChoiceLength = 50
+)
+
+func (r *noteRepository) Validate(ctx context.Context, note *entity.Note) error {
+	if r.allSuspended() {
+		return ErrAccountSuspended
+	}
+	if err := validatePoll(note.Poll, time.Now()); err != nil {
+		return err
+	}
+
+	note = r.withCategoryVisibility(note)
+	text, cw, err := r.prepareNote(ctx, note)
+	if err != nil {
+		return err
+	}
+
+	limit := r.instanceTextLimit(ctx)
+	if length := utf8.RuneCountInString(text); length > limit {
+		return fmt.Errorf("%w: %d characters, limit is %d", ErrTextTooLong, length, limit)
+	}
+	if length := utf8.RuneCountInString(cw); length > limit {
+		return fmt.Errorf("%w: content warning is %d characters, limit is %d", ErrTextTooLong, length, limit)
+	}
+	return nil
+}
+
+func (r *noteRepository) MaxNoteLength(ctx context.Context) int {
+	return r.instanceTextLimit(ctx)
+}
+
+func (r *noteRepository) instanceTextLimit(ctx context.Context) int {
+	if r.maxTextLength > 0 {
+		return r.maxTextLength
+	}
+	meta, err := r.fetchMeta(ctx)
+	if err != nil {
+		r.logf("Warning: Could not fetch instance text limit, assuming %d: %v", defaultMaxTextLength, err)
+		return defaultMaxTextLength
+	}
+	if meta.MaxNoteTextLength > 0 {
+		return meta.MaxNoteTextLength
+	}
+	return defaultMaxTextLength
+}
+
+func validatePoll(poll *entity.Poll, now time.Time) error {
+	if poll == nil {
+		return nil
+	}
+	if n := len(poll.Choices); n < minPollChoices || n > maxPollChoices {
+		return fmt.Errorf("%w: %d choices, must be between %d and %d", ErrInvalidPoll, n, minPollChoices, maxPollChoices)
+	}
+
+	seen := make(map[string]bool, len(poll.Choices))
+	for _, choice := range poll.Choices {
+		if length := utf8.RuneCountInString(choice); length == 0 || length > maxPollChoiceLength {
+			return fmt.Errorf("%w: choice %q must be 1 to %d characters", ErrInvalidPoll, choice, maxPollChoiceLength)
+		}
+		if seen[choice] {
+			return fmt.Errorf("%w: duplicate choice %q", ErrInvalidPoll, choice)
+		}
+		seen[choice] = true
+	}
+
+	if poll.ExpiresAt != nil && !poll.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiry %s is in the past", ErrInvalidPoll, poll.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package misskey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		note          *entity.Note
+		maxTextLength int
+		maxNoteBytes  int
+		maxFiles      int
+		suspended     bool
+		expectErr     error
+	}{
+		{"valid", entity.NewNote("Hello", entity.VisibilityHome), 0, 0, 0, false, nil},
+		{"within instance limit", entity.NewNote(strings.Repeat("a", 20), entity.VisibilityHome), 0, 0, 0, false, nil},
+		{"over instance limit", entity.NewNote(strings.Repeat("a", 21), entity.VisibilityHome), 0, 0, 0, false, ErrTextTooLong},
+		{"configured limit overrides instance", entity.NewNote(strings.Repeat("a", 21), entity.VisibilityHome), 30, 0, 0, false, nil},
+		{"over byte limit", entity.NewNote(strings.Repeat("あ", 5), entity.VisibilityHome), 0, 12, 0, false, ErrNoteTooLargeBytes},
+		{"unknown visibility", entity.NewNote("Hello", "direct"), 0, 0, 0, false, ErrInvalidVisibility},
+		{"too many files", &entity.Note{Text: "Hello", Visibility: entity.VisibilityHome, FileIDs: []string{"f1", "f2"}}, 0, 0, 1, false, ErrTooManyFiles},
+		{"empty note", entity.NewNote("  ", entity.VisibilityHome), 0, 0, 0, false, ErrEmptyNote},
+		{"poll with one choice", &entity.Note{Text: "Vote", Visibility: entity.VisibilityHome, Poll: &entity.Poll{Choices: []string{"yes"}}}, 0, 0, 0, false, ErrInvalidPoll},
+		{"poll with duplicate choices", &entity.Note{Text: "Vote", Visibility: entity.VisibilityHome, Poll: &entity.Poll{Choices: []string{"yes", "yes"}}}, 0, 0, 0, false, ErrInvalidPoll},
+		{"expired poll", &entity.Note{Text: "Vote", Visibility: entity.VisibilityHome, Poll: &entity.Poll{Choices: []string{"yes", "no"}, ExpiresAt: &past}}, 0, 0, 0, false, ErrInvalidPoll},
+		{"valid poll", &entity.Note{Text: "Vote", Visibility: entity.VisibilityHome, Poll: &entity.Poll{Choices: []string{"yes", "no"}, ExpiresAt: &future}}, 0, 0, 0, false, nil},
+		{"suspended account", entity.NewNote("Hello", entity.VisibilityHome), 0, 0, 0, true, ErrAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/meta") {
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+				w.Write([]byte(`{"maxNoteTextLength": 20}`))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:            server.URL,
+				authToken:       "test-token",
+				client:          &http.Client{Timeout: 30 * time.Second},
+				rateLimiter:     newRateLimiter(1, time.Second),
+				maxTextLength:   tt.maxTextLength,
+				maxNoteBytes:    tt.maxNoteBytes,
+				maxFilesPerNote: tt.maxFiles,
+				cache:           responseCache{meta: ttlCache[instanceMeta]{ttl: time.Minute}},
+			}
+			repo.postingAccounts()[0].suspended.Store(tt.suspended)
+
+			if err := repo.Validate(context.Background(), tt.note); !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_Validate_MetaUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &noteRepository{
+		host:      server.URL,
+		authToken: "test-token",
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if err := repo.Validate(context.Background(), entity.NewNote(strings.Repeat("a", defaultMaxTextLength), entity.VisibilityHome)); err != nil {
+		t.Errorf("expected default limit to accept note, got %v", err)
+	}
+	if err := repo.Validate(context.Background(), entity.NewNote(strings.Repeat("a", defaultMaxTextLength+1), entity.VisibilityHome)); !errors.Is(err, ErrTextTooLong) {
+		t.Errorf("expected ErrTextTooLong, got %v", err)
+	}
+}
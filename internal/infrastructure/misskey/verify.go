@@ -0,0 +1,26 @@
+package misskey
+
+import (
+	"context"
+	"strings"
+)
+
+func (r *noteRepository) VerifyPosted(ctx context.Context, noteID string) (bool, error) {
+	return r.VerifyPostedText(ctx, noteID, "")
+}
+
+func (r *noteRepository) VerifyPostedText(ctx context.Context, noteID, expectedText string) (bool, error) {
+	note, err := r.GetNote(ctx, noteID)
+	if err != nil {
+		if isNoSuchNote(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if expectedText != "" && strings.TrimSpace(note.Text) != strings.TrimSpace(expectedText) {
+		r.logf("Posted note [%s] text differs from the expected text", noteID)
+		return false, nil
+	}
+	return true, nil
+}
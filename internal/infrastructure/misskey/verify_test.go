@@ -0,0 +1,56 @@
+package misskey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoteRepository_VerifyPostedText(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectedText string
+		expectOK     bool
+		expectErr    bool
+	}{
+		{"exists", http.StatusOK, `{"id": "n1", "text": "hello"}`, "", true, false},
+		{"text matches", http.StatusOK, `{"id": "n1", "text": "hello\n"}`, "hello", true, false},
+		{"text differs", http.StatusOK, `{"id": "n1", "text": "filtered"}`, "hello", false, false},
+		{"not found", http.StatusBadRequest, `{"error": {"code": "NO_SUCH_NOTE"}}`, "", false, false},
+		{"fetch error", http.StatusInternalServerError, `{"error": {"code": "INTERNAL_ERROR"}}`, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/notes/show" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			repo := &noteRepository{
+				host:      server.URL,
+				authToken: "test-token",
+				client:    &http.Client{Timeout: 30 * time.Second},
+			}
+
+			ok, err := repo.VerifyPostedText(context.Background(), "n1", tt.expectedText)
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if ok != tt.expectOK {
+				t.Errorf("expected ok=%v, got %v", tt.expectOK, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package misskey
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func standardVisibilities() []entity.NoteVisibility {
+	return []entity.NoteVisibility{
+		entity.VisibilityPublic,
+		entity.VisibilityHome,
+		entity.VisibilityFollowers,
+		entity.VisibilitySpecified,
+	}
+}
+
+func validateExtraVisibilities(extra []entity.NoteVisibility) error {
+	for _, v := range extra {
+		if v == "" {
+			return fmt.Errorf("%w: extra visibilities must not be empty", ErrInvalidVisibility)
+		}
+	}
+	return nil
+}
+
+func (r *noteRepository) checkVisibility(visibility entity.NoteVisibility) error {
+	if slices.Contains(standardVisibilities(), visibility) || slices.Contains(r.extraVisibilities, visibility) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidVisibility, visibility)
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func normalizeVisibilityRules(rules map[string]entity.NoteVisibility) map[string]entity.NoteVisibility {
+	if len(rules) == 0 {
+		return nil
+	}
+	normalized := make(map[string]entity.NoteVisibility, len(rules))
+	for category, visibility := range rules {
+		normalized[normalizeCategory(category)] = visibility
+	}
+	return normalized
+}
+
+func (r *noteRepository) validateVisibilityRules() error {
+	for category, visibility := range r.visibilityRules {
+		if err := r.checkVisibility(visibility); err != nil {
+			return fmt.Errorf("invalid visibility rule for category %q: %w", category, err)
+		}
+	}
+	if r.defaultVisibility != "" {
+		if err := r.checkVisibility(r.defaultVisibility); err != nil {
+			return fmt.Errorf("invalid default visibility: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *noteRepository) withCategoryVisibility(note *entity.Note) *entity.Note {
+	if note.Visibility != "" {
+		return note
+	}
+
+	visibility, ok := r.visibilityRules[normalizeCategory(note.Category)]
+	if !ok {
+		visibility = r.defaultVisibility
+	}
+	if visibility == "" {
+		return note
+	}
+
+	resolved := *note
+	resolved.Visibility = visibility
+	return &resolved
+}
@@ -0,0 +1,155 @@
+package misskey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNoteRepository_Post_Visibility(t *testing.T) {
+	const mutual entity.NoteVisibility = "mutual"
+
+	tests := []struct {
+		name       string
+		extra      []entity.NoteVisibility
+		visibility entity.NoteVisibility
+		expectErr  error
+	}{
+		{"standard visibility", nil, entity.VisibilityFollowers, nil},
+		{"unknown visibility rejected", nil, mutual, ErrInvalidVisibility},
+		{"registered extra visibility", []entity.NoteVisibility{mutual}, mutual, nil},
+		{"empty visibility rejected", []entity.NoteVisibility{mutual}, "", ErrInvalidVisibility},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo, err := NewNoteRepositoryWithOptions(server.URL, "test-token", WithExtraVisibilities(tt.extra...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = repo.Post(context.Background(), entity.NewNote("Custom visibility", tt.visibility))
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr != nil {
+				if payload != nil {
+					t.Error("expected no request for an invalid visibility")
+				}
+				return
+			}
+			if payload["visibility"] != string(tt.visibility) {
+				t.Errorf("expected visibility %s, got %v", tt.visibility, payload["visibility"])
+			}
+		})
+	}
+}
+
+func TestNewNoteRepository_ExtraVisibilitiesValidation(t *testing.T) {
+	_, err := NewNoteRepository(Config{Host: "https://example.tld", AuthToken: "token", ExtraVisibilities: []entity.NoteVisibility{""}})
+	if !errors.Is(err, ErrInvalidVisibility) {
+		t.Errorf("expected ErrInvalidVisibility, got %v", err)
+	}
+}
+
+func TestNoteRepository_Post_VisibilityRules(t *testing.T) {
+	rules := map[string]entity.NoteVisibility{
+		"news":     entity.VisibilityPublic,
+		"Personal": entity.VisibilityFollowers,
+	}
+
+	tests := []struct {
+		name               string
+		defaultVisibility  entity.NoteVisibility
+		category           string
+		visibility         entity.NoteVisibility
+		expectedVisibility string
+		expectErr          error
+	}{
+		{"mapped category", "", "news", "", "public", nil},
+		{"category is case insensitive", "", "personal", "", "followers", nil},
+		{"explicit visibility wins", "", "news", entity.VisibilityHome, "home", nil},
+		{"unmapped category uses default", entity.VisibilityHome, "sports", "", "home", nil},
+		{"unmapped category without default", "", "sports", "", "", ErrInvalidVisibility},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&payload)
+				w.Write([]byte(`{"createdNote": {"id": "note123"}}`))
+			}))
+			defer server.Close()
+
+			repo, err := NewNoteRepository(Config{
+				Host:              server.URL,
+				AuthToken:         "test-token",
+				VisibilityRules:   rules,
+				DefaultVisibility: tt.defaultVisibility,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			note := entity.NewNote("Categorized", tt.visibility)
+			note.Category = tt.category
+			if err := repo.Post(context.Background(), note); !errors.Is(err, tt.expectErr) {
+				t.Fatalf("expected %v, got %v", tt.expectErr, err)
+			}
+			if tt.expectErr == nil && payload["visibility"] != tt.expectedVisibility {
+				t.Errorf("expected visibility %s, got %v", tt.expectedVisibility, payload["visibility"])
+			}
+			if note.Visibility != tt.visibility {
+				t.Errorf("expected caller's note to be left unmodified, got %q", note.Visibility)
+			}
+		})
+	}
+}
+
+func TestNewNoteRepository_InvalidVisibilityRules(t *testing.T) {
+	tests := []struct {
+		name              string
+		rules             map[string]entity.NoteVisibility
+		extra             []entity.NoteVisibility
+		defaultVisibility entity.NoteVisibility
+		expectErr         bool
+	}{
+		{"valid rules", map[string]entity.NoteVisibility{"news": entity.VisibilityPublic}, nil, entity.VisibilityHome, false},
+		{"unknown rule visibility", map[string]entity.NoteVisibility{"news": "everyone"}, nil, "", true},
+		{"extra visibility in rule", map[string]entity.NoteVisibility{"friends": "mutual"}, []entity.NoteVisibility{"mutual"}, "", false},
+		{"unknown default", nil, nil, "everyone", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNoteRepository(Config{
+				Host:              "misskey.example",
+				AuthToken:         "test-token",
+				VisibilityRules:   tt.rules,
+				ExtraVisibilities: tt.extra,
+				DefaultVisibility: tt.defaultVisibility,
+			})
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidVisibility) {
+				t.Errorf("expected ErrInvalidVisibility, got %v", err)
+			}
+		})
+	}
+}
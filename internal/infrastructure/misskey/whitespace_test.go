@@ -0,0 +1,42 @@
+package misskey
+
+import (
+	"testing"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already clean", "Title\n\nBody", "Title\n\nBody"},
+		{"trim ends", "  \n\tTitle\nBody \n\n", "Title\nBody"},
+		{"collapse blank lines", "Title\n\n\n\nBody", "Title\n\nBody"},
+		{"whitespace-only lines count as blank", "Title\n \n\t\n  \nBody", "Title\n\nBody"},
+		{"trailing spaces per line", "Title  \t\nBody   ", "Title\nBody"},
+		{"leading indentation kept", "Title\n  - item", "Title\n  - item"},
+		{"crlf line endings", "Title\r\n\r\n\r\nBody\r\n", "Title\n\nBody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeWhitespace(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoteRepository_RenderText_TrimWhitespace(t *testing.T) {
+	note := entity.NewNote("  Title  \n\n\n\nBody\n", entity.VisibilityHome)
+
+	if got := (&noteRepository{}).renderText(note); got != note.Text {
+		t.Errorf("expected text unchanged by default, got %q", got)
+	}
+	if got := (&noteRepository{trimWhitespace: true}).renderText(note); got != "Title\n\nBody" {
+		t.Errorf("expected normalized text, got %q", got)
+	}
+}
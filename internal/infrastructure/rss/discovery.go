@@ -0,0 +1,93 @@
+package rss
+
+import (
+	"html"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+func feedLinkTypes() []string {
+	return []string{"application/rss+xml", "application/atom+xml", "application/rdf+xml", "application/feed+json"}
+}
+
+// discoverFeedURL returns the first <link rel="alternate"> feed advertised
+// by an HTML page, resolved against the page URL.
+func discoverFeedURL(page []byte, base *url.URL) (string, bool) {
+	doc := string(page)
+	lower := strings.ToLower(doc)
+	for offset := 0; ; {
+		start := strings.Index(lower[offset:], "<link")
+		if start < 0 {
+			return "", false
+		}
+		start += offset + len("<link")
+		end := strings.IndexByte(lower[start:], '>')
+		if end < 0 {
+			return "", false
+		}
+		end += start
+		offset = end
+
+		attrs := parseAttributes(doc[start:end])
+		rel := strings.Fields(strings.ToLower(attrs["rel"]))
+		linkType := strings.ToLower(strings.TrimSpace(attrs["type"]))
+		if !slices.Contains(rel, "alternate") || !slices.Contains(feedLinkTypes(), linkType) || attrs["href"] == "" {
+			continue
+		}
+		href, err := url.Parse(strings.TrimSpace(attrs["href"]))
+		if err != nil {
+			continue
+		}
+		if base != nil {
+			href = base.ResolveReference(href)
+		}
+		return href.String(), true
+	}
+}
+
+func parseAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for i := 0; i < len(tag); {
+		for i < len(tag) && (tag[i] == ' ' || tag[i] == '\t' || tag[i] == '\n' || tag[i] == '\r' || tag[i] == '/') {
+			i++
+		}
+		nameStart := i
+		for i < len(tag) && tag[i] != '=' && tag[i] != ' ' && tag[i] != '\t' && tag[i] != '\n' && tag[i] != '\r' {
+			i++
+		}
+		name := strings.ToLower(tag[nameStart:i])
+		if i >= len(tag) || tag[i] != '=' {
+			if name != "" {
+				attrs[name] = ""
+			}
+			if i == nameStart {
+				i++
+			}
+			continue
+		}
+		i++
+
+		var value string
+		if i < len(tag) && (tag[i] == '"' || tag[i] == '\'') {
+			quote := tag[i]
+			i++
+			valueStart := i
+			for i < len(tag) && tag[i] != quote {
+				i++
+			}
+			value = tag[valueStart:i]
+			i++
+		} else {
+			valueStart := i
+			for i < len(tag) && tag[i] != ' ' && tag[i] != '\t' && tag[i] != '\n' && tag[i] != '\r' {
+				i++
+			}
+			value = tag[valueStart:i]
+		}
+		if name != "" {
+			attrs[name] = html.UnescapeString(value)
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,80 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDiscoverFeedURL(t *testing.T) {
+	base, _ := url.Parse("https://blog.example/posts/")
+
+	tests := []struct {
+		name     string
+		page     string
+		expected string
+		found    bool
+	}{
+		{"relative rss link", `<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head></html>`, "https://blog.example/feed.xml", true},
+		{"absolute atom link", `<LINK REL='alternate' TYPE='application/atom+xml' HREF='https://feeds.example/atom'/>`, "https://feeds.example/atom", true},
+		{"escaped href", `<link rel="alternate" type="application/rss+xml" href="/feed?a=1&amp;b=2">`, "https://blog.example/feed?a=1&b=2", true},
+		{"first feed wins", `<link rel="stylesheet" href="/style.css"><link rel="alternate" type="text/html" href="/ja/"><link rel="alternate" type="application/rss+xml" href="rss"><link rel="alternate" type="application/atom+xml" href="atom">`, "https://blog.example/posts/rss", true},
+		{"multiple rel tokens", `<link rel="home alternate" type="application/feed+json" href="feed.json">`, "https://blog.example/posts/feed.json", true},
+		{"no feed link", `<html><head><title>Blog</title></head></html>`, "", false},
+		{"missing href", `<link rel="alternate" type="application/rss+xml">`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := discoverFeedURL([]byte(tt.page), base)
+			if found != tt.found || got != tt.expected {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.found, got, found)
+			}
+		})
+	}
+}
+
+func TestFeedRepository_Fetch_DiscoversFeedFromHTML(t *testing.T) {
+	var pageRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		pageRequests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head><body>Blog</body></html>`))
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>T</title>
+<item><title>A</title><link>https://blog.example/a</link><guid>a</guid><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	repo := NewFeedRepository()
+	for i := 0; i < 2; i++ {
+		entries, _, err := repo.Fetch(context.Background(), server.URL+"/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Title != "A" {
+			t.Fatalf("expected the discovered feed's entry, got %v", entries)
+		}
+	}
+	if got := pageRequests.Load(); got != 1 {
+		t.Errorf("expected the HTML page to be requested once, got %d", got)
+	}
+}
+
+func TestFeedRepository_Fetch_HTMLWithoutFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body>No feed here</body></html>`))
+	}))
+	defer server.Close()
+
+	if _, _, err := NewFeedRepository().Fetch(context.Background(), server.URL); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -1,8 +1,15 @@
 package rss
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"misskeyRSSbot/internal/domain/entity"
 	"misskeyRSSbot/internal/domain/repository"
@@ -10,42 +17,207 @@ import (
 	"github.com/mmcdole/gofeed"
 )
 
+const (
+	userAgent    = "misskeyRSSbot"
+	maxFeedBytes = 16 << 20
+)
+
 type feedRepository struct {
-	parser *gofeed.Parser
+	parser     *gofeed.Parser
+	client     *http.Client
+	validators repository.FeedValidatorRepository
+	mu         sync.Mutex
+	discovered map[string]string
+}
+
+type Option func(*feedRepository)
+
+// WithValidatorRepository makes fetches conditional on the ETag and
+// Last-Modified values stored for each feed. Saving new values is left to
+// the caller of Fetch.
+func WithValidatorRepository(validators repository.FeedValidatorRepository) Option {
+	return func(r *feedRepository) {
+		r.validators = validators
+	}
 }
 
-func NewFeedRepository() repository.FeedRepository {
-	return &feedRepository{
-		parser: gofeed.NewParser(),
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *feedRepository) {
+		r.client = client
 	}
 }
 
-func (r *feedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, error) {
-	feed, err := r.parser.ParseURLWithContext(url, ctx)
+func NewFeedRepository(opts ...Option) repository.FeedRepository {
+	r := &feedRepository{
+		parser:     gofeed.NewParser(),
+		client:     &http.Client{Timeout: 30 * time.Second},
+		discovered: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *feedRepository) Fetch(ctx context.Context, url string) ([]*entity.FeedEntry, entity.FeedValidators, error) {
+	target, discovered := r.discoveredURL(url)
+	feed, validators, err := r.fetchFeed(ctx, url, target, !discovered)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		return nil, entity.FeedValidators{}, err
 	}
 
 	entries := make([]*entity.FeedEntry, 0, len(feed.Items))
 	for _, item := range feed.Items {
-		if item.PublishedParsed == nil {
-			continue
+		if entry, ok := newFeedEntry(item); ok {
+			entries = append(entries, entry)
 		}
+	}
 
-		guid := item.GUID
-		if guid == "" {
-			guid = item.Link
+	return entries, validators, nil
+}
+
+// newFeedEntry maps an item parsed from any supported format. Items with
+// neither a published nor an updated date are skipped.
+func newFeedEntry(item *gofeed.Item) (*entity.FeedEntry, bool) {
+	published := item.PublishedParsed
+	if published == nil {
+		published = item.UpdatedParsed
+	}
+	if published == nil {
+		return nil, false
+	}
+
+	link := item.Link
+	if link == "" && len(item.Links) > 0 {
+		link = item.Links[0]
+	}
+	guid := item.GUID
+	if guid == "" {
+		guid = link
+	}
+	description := item.Description
+	if description == "" {
+		description = item.Content
+	}
+
+	entry := entity.NewFeedEntry(item.Title, link, description, *published, guid)
+	entry.Content = item.Content
+	if item.UpdatedParsed != nil {
+		entry.Updated = *item.UpdatedParsed
+	}
+	entry.AttachmentURLs = attachmentURLs(item)
+	entry.Categories = item.Categories
+	switch {
+	case item.Author != nil:
+		entry.Author = item.Author.Name
+	case len(item.Authors) > 0 && item.Authors[0] != nil:
+		entry.Author = item.Authors[0].Name
+	}
+	return entry, true
+}
+
+// fetchFeed requests target and keys validators by the configured url. If
+// discover is set and target serves HTML, the page's advertised feed is
+// fetched instead and remembered for later polls. The returned validators are
+// zero when they match the stored ones.
+func (r *feedRepository) fetchFeed(ctx context.Context, url, target string, discover bool) (*gofeed.Feed, entity.FeedValidators, error) {
+	validators := r.loadValidators(ctx, url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, entity.FeedValidators{}, fmt.Errorf("failed to create feed request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, entity.FeedValidators{}, fmt.Errorf("failed to fetch RSS feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, entity.FeedValidators{}, repository.ErrFeedNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, entity.FeedValidators{}, fmt.Errorf("failed to fetch RSS feed: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, entity.FeedValidators{}, fmt.Errorf("failed to read RSS feed: %w", err)
+	}
+	feed, err := r.parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		if discover {
+			if feedURL, ok := discoverFeedURL(body, resp.Request.URL); ok {
+				log.Printf("Discovered feed [%s] on page [%s]", feedURL, url)
+				r.rememberDiscovered(url, feedURL)
+				return r.fetchFeed(ctx, url, feedURL, false)
+			}
+		}
+		return nil, entity.FeedValidators{}, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+	latest := entity.FeedValidators{
+		FeedURL:      url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if r.validators == nil || latest == validators {
+		return feed, entity.FeedValidators{}, nil
+	}
+	return feed, latest, nil
+}
+
+func (r *feedRepository) discoveredURL(url string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if feedURL, ok := r.discovered[url]; ok {
+		return feedURL, true
+	}
+	return url, false
+}
+
+func (r *feedRepository) rememberDiscovered(url, feedURL string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.discovered[url] = feedURL
+}
+
+func attachmentURLs(item *gofeed.Item) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
 		}
+	}
 
-		entry := entity.NewFeedEntry(
-			item.Title,
-			item.Link,
-			item.Description,
-			*item.PublishedParsed,
-			guid,
-		)
-		entries = append(entries, entry)
+	for _, enclosure := range item.Enclosures {
+		if enclosure != nil && strings.HasPrefix(enclosure.Type, "image/") {
+			add(enclosure.URL)
+		}
 	}
+	if item.Image != nil {
+		add(item.Image.URL)
+	}
+	return urls
+}
 
-	return entries, nil
+func (r *feedRepository) loadValidators(ctx context.Context, url string) entity.FeedValidators {
+	if r.validators == nil {
+		return entity.FeedValidators{FeedURL: url}
+	}
+	validators, err := r.validators.GetFeedValidators(ctx, url)
+	if err != nil {
+		log.Printf("Warning: Fetching feed [%s] unconditionally: %v", url, err)
+		return entity.FeedValidators{FeedURL: url}
+	}
+	return validators
 }
@@ -2,10 +2,14 @@ package rss
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
 )
 
 func TestFeedRepository_Fetch_Success(t *testing.T) {
@@ -40,7 +44,7 @@ func TestFeedRepository_Fetch_Success(t *testing.T) {
 	repo := NewFeedRepository()
 	ctx := context.Background()
 
-	entries, err := repo.Fetch(ctx, server.URL)
+	entries, _, err := repo.Fetch(ctx, server.URL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -84,7 +88,7 @@ func TestFeedRepository_Fetch_EmptyGUID(t *testing.T) {
 	repo := NewFeedRepository()
 	ctx := context.Background()
 
-	entries, err := repo.Fetch(ctx, server.URL)
+	entries, _, err := repo.Fetch(ctx, server.URL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -127,7 +131,7 @@ func TestFeedRepository_Fetch_SkipNoPubDate(t *testing.T) {
 	repo := NewFeedRepository()
 	ctx := context.Background()
 
-	entries, err := repo.Fetch(ctx, server.URL)
+	entries, _, err := repo.Fetch(ctx, server.URL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -145,7 +149,7 @@ func TestFeedRepository_Fetch_InvalidURL(t *testing.T) {
 	repo := NewFeedRepository()
 	ctx := context.Background()
 
-	_, err := repo.Fetch(ctx, "http://invalid-url-that-does-not-exist-12345.com/feed")
+	_, _, err := repo.Fetch(ctx, "http://invalid-url-that-does-not-exist-12345.com/feed")
 	if err == nil {
 		t.Error("expected error for invalid URL, got nil")
 	}
@@ -162,7 +166,7 @@ func TestFeedRepository_Fetch_InvalidXML(t *testing.T) {
 	repo := NewFeedRepository()
 	ctx := context.Background()
 
-	_, err := repo.Fetch(ctx, server.URL)
+	_, _, err := repo.Fetch(ctx, server.URL)
 	if err == nil {
 		t.Error("expected error for invalid XML, got nil")
 	}
@@ -181,8 +185,257 @@ func TestFeedRepository_Fetch_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	_, err := repo.Fetch(ctx, server.URL)
+	_, _, err := repo.Fetch(ctx, server.URL)
 	if err == nil {
 		t.Error("expected error for cancelled context, got nil")
 	}
 }
+
+func TestFeedRepository_Fetch_UserAgent(t *testing.T) {
+	var receivedUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	repo := NewFeedRepository()
+	if _, _, err := repo.Fetch(context.Background(), server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedUserAgent != userAgent {
+		t.Errorf("expected User-Agent %q, got %q", userAgent, receivedUserAgent)
+	}
+}
+
+func TestFeedRepository_Fetch_Attachments(t *testing.T) {
+	rssXML := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+	<channel>
+		<title>Test Feed</title>
+		<item>
+			<title>Article 1</title>
+			<link>https://example.com/article1</link>
+			<guid>guid-1</guid>
+			<pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate>
+			<enclosure url="https://example.com/photo.jpg" length="1024" type="image/jpeg"/>
+			<enclosure url="https://example.com/episode.mp3" length="4096" type="audio/mpeg"/>
+		</item>
+		<item>
+			<title>Article 2</title>
+			<link>https://example.com/article2</link>
+			<guid>guid-2</guid>
+			<pubDate>Tue, 03 Jan 2006 15:04:05 MST</pubDate>
+		</item>
+	</channel>
+</rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(rssXML))
+	}))
+	defer server.Close()
+
+	entries, _, err := NewFeedRepository().Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	if len(entries[0].AttachmentURLs) != 1 || entries[0].AttachmentURLs[0] != "https://example.com/photo.jpg" {
+		t.Errorf("expected only the image enclosure, got %v", entries[0].AttachmentURLs)
+	}
+	if len(entries[1].AttachmentURLs) != 0 {
+		t.Errorf("expected no attachments, got %v", entries[1].AttachmentURLs)
+	}
+}
+
+type mapValidatorRepository struct {
+	validators map[string]entity.FeedValidators
+}
+
+func (m *mapValidatorRepository) GetFeedValidators(ctx context.Context, feedURL string) (entity.FeedValidators, error) {
+	if v, ok := m.validators[feedURL]; ok {
+		return v, nil
+	}
+	return entity.FeedValidators{FeedURL: feedURL}, nil
+}
+
+func (m *mapValidatorRepository) SaveFeedValidators(ctx context.Context, validators entity.FeedValidators) error {
+	m.validators[validators.FeedURL] = validators
+	return nil
+}
+
+func TestFeedRepository_Fetch_Conditional(t *testing.T) {
+	const etag = `"abc123"`
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	rssXML := `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title>
+<item><title>A</title><link>https://example.com/a</link><guid>a</guid><pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate></item>
+</channel></rss>`
+
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(rssXML))
+	}))
+	defer server.Close()
+
+	store := &mapValidatorRepository{validators: make(map[string]entity.FeedValidators)}
+	repo := NewFeedRepository(WithValidatorRepository(store))
+
+	entries, validators, err := repo.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if len(store.validators) != 0 {
+		t.Errorf("expected Fetch to leave saving to the caller, got %+v", store.validators)
+	}
+	if validators.ETag != etag || validators.LastModified != lastModified {
+		t.Errorf("expected new validators to be returned, got %+v", validators)
+	}
+	store.SaveFeedValidators(context.Background(), validators)
+
+	entries, _, err = repo.Fetch(context.Background(), server.URL)
+	if !errors.Is(err, repository.ErrFeedNotModified) {
+		t.Fatalf("expected ErrFeedNotModified, got %v (%d entries)", err, len(entries))
+	}
+	if len(requests) != 2 || requests[1].Header.Get("If-Modified-Since") != lastModified {
+		t.Errorf("expected a conditional second request, got headers %v", requests[len(requests)-1].Header)
+	}
+}
+
+func TestFeedRepository_Fetch_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"not found", http.StatusNotFound},
+		{"server error", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			_, _, err := NewFeedRepository().Fetch(context.Background(), server.URL)
+			if err == nil || errors.Is(err, repository.ErrFeedNotModified) {
+				t.Errorf("expected fetch error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFeedRepository_Fetch_Formats(t *testing.T) {
+	published := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	updated := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    []entity.FeedEntry
+	}{
+		{
+			name:        "rss 2.0 with content:encoded",
+			contentType: "application/rss+xml",
+			body: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel><title>T</title>
+<item><title>RSS item</title><link>https://example.com/rss</link><guid>rss-1</guid>
+<description>Short summary</description><content:encoded><![CDATA[<p>Full body</p>]]></content:encoded>
+<author>writer@example.com (Writer)</author><pubDate>Tue, 02 Jan 2024 15:04:05 GMT</pubDate></item>
+</channel></rss>`,
+			expected: []entity.FeedEntry{
+				{Title: "RSS item", Link: "https://example.com/rss", GUID: "rss-1", Description: "Short summary", Content: "<p>Full body</p>", Author: "Writer", Published: published},
+			},
+		},
+		{
+			name:        "atom 1.0",
+			contentType: "application/atom+xml",
+			body: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title><id>urn:feed</id><updated>2024-01-03T09:00:00Z</updated>
+<entry><title>Published entry</title><id>urn:entry:1</id><link href="https://example.com/atom/1"/>
+<published>2024-01-02T15:04:05Z</published><updated>2024-01-03T09:00:00Z</updated>
+<summary>Atom summary</summary><content type="html">&lt;p&gt;Atom body&lt;/p&gt;</content><author><name>Atom Author</name></author></entry>
+<entry><title>Updated-only entry</title><id>urn:entry:2</id><link rel="alternate" href="https://example.com/atom/2"/>
+<updated>2024-01-03T09:00:00Z</updated><content type="text">Content without summary</content></entry>
+</feed>`,
+			expected: []entity.FeedEntry{
+				{Title: "Published entry", Link: "https://example.com/atom/1", GUID: "urn:entry:1", Description: "Atom summary", Content: "<p>Atom body</p>", Author: "Atom Author", Published: published, Updated: updated},
+				{Title: "Updated-only entry", Link: "https://example.com/atom/2", GUID: "urn:entry:2", Description: "Content without summary", Content: "Content without summary", Published: updated, Updated: updated},
+			},
+		},
+		{
+			name:        "json feed 1.1",
+			contentType: "application/feed+json",
+			body: `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "T",
+  "items": [
+    {"id": "json-1", "url": "https://example.com/json/1", "title": "JSON item", "summary": "JSON summary",
+     "content_html": "<p>JSON body</p>", "date_published": "2024-01-02T15:04:05Z", "date_modified": "2024-01-03T09:00:00Z",
+     "authors": [{"name": "JSON Author"}], "tags": ["go"]},
+    {"id": "json-2", "url": "https://example.com/json/2", "title": "Modified only", "content_text": "Plain body",
+     "date_modified": "2024-01-03T09:00:00Z"},
+    {"id": "json-3", "url": "https://example.com/json/3", "title": "Undated"}
+  ]
+}`,
+			expected: []entity.FeedEntry{
+				{Title: "JSON item", Link: "https://example.com/json/1", GUID: "json-1", Description: "JSON summary", Content: "<p>JSON body</p>", Author: "JSON Author", Categories: []string{"go"}, Published: published, Updated: updated},
+				{Title: "Modified only", Link: "https://example.com/json/2", GUID: "json-2", Description: "Plain body", Content: "Plain body", Published: updated, Updated: updated},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			entries, _, err := NewFeedRepository().Fetch(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("expected %d entries, got %d", len(tt.expected), len(entries))
+			}
+			for i, expected := range tt.expected {
+				got := entries[i]
+				if got.Title != expected.Title || got.Link != expected.Link || got.GUID != expected.GUID {
+					t.Errorf("entry %d: expected %q %s [%s], got %q %s [%s]", i, expected.Title, expected.Link, expected.GUID, got.Title, got.Link, got.GUID)
+				}
+				if got.Description != expected.Description || got.Content != expected.Content {
+					t.Errorf("entry %d: expected description %q and content %q, got %q and %q", i, expected.Description, expected.Content, got.Description, got.Content)
+				}
+				if got.Author != expected.Author {
+					t.Errorf("entry %d: expected author %q, got %q", i, expected.Author, got.Author)
+				}
+				if !got.Published.Equal(expected.Published) || !got.Updated.Equal(expected.Updated) {
+					t.Errorf("entry %d: expected published %v and updated %v, got %v and %v", i, expected.Published, expected.Updated, got.Published, got.Updated)
+				}
+				if len(expected.Categories) > 0 && (len(got.Categories) != 1 || got.Categories[0] != expected.Categories[0]) {
+					t.Errorf("entry %d: expected categories %v, got %v", i, expected.Categories, got.Categories)
+				}
+			}
+		})
+	}
+}
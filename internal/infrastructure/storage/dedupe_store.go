@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const dedupeNamespace = "dedupe"
+
+type dedupeEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+func newDedupeEntry(value []byte, ttl time.Duration, now time.Time) dedupeEntry {
+	entry := dedupeEntry{Value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.ExpiresAt = now.Add(ttl)
+	}
+	return entry
+}
+
+func (e dedupeEntry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+type memoryDedupeStore struct {
+	mu      sync.Mutex
+	entries map[string]dedupeEntry
+	now     func() time.Time
+}
+
+func NewMemoryDedupeStore() repository.DedupeStore {
+	return &memoryDedupeStore{entries: make(map[string]dedupeEntry), now: time.Now}
+}
+
+func (s *memoryDedupeStore) Seen(ctx context.Context, key string) (bool, error) {
+	_, ok, err := s.Get(ctx, key)
+	return ok, err
+}
+
+func (s *memoryDedupeStore) Remember(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = newDedupeEntry(value, ttl, s.now())
+	return nil
+}
+
+func (s *memoryDedupeStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if entry.expired(s.now()) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.Value...), true, nil
+}
+
+type stateDedupeStore struct {
+	state repository.StateStore
+	now   func() time.Time
+}
+
+// NewStateDedupeStore keeps dedupe entries in their own namespace of state,
+// so it can share the file opened for STATE_PATH.
+func NewStateDedupeStore(state repository.StateStore) repository.DedupeStore {
+	return &stateDedupeStore{state: state, now: time.Now}
+}
+
+func (s *stateDedupeStore) Seen(ctx context.Context, key string) (bool, error) {
+	_, ok, err := s.Get(ctx, key)
+	return ok, err
+}
+
+func (s *stateDedupeStore) Remember(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	data, err := json.Marshal(newDedupeEntry(value, ttl, s.now()))
+	if err != nil {
+		return fmt.Errorf("failed to encode dedupe entry [%s]: %w", key, err)
+	}
+	return s.state.Put(ctx, dedupeNamespace, key, data)
+}
+
+func (s *stateDedupeStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, ok, err := s.state.Get(ctx, dedupeNamespace, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var entry dedupeEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, fmt.Errorf("failed to decode dedupe entry [%s]: %w", key, err)
+	}
+	if entry.expired(s.now()) {
+		return nil, false, s.state.Delete(ctx, dedupeNamespace, key)
+	}
+	return entry.Value, true, nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+func TestDedupeStores(t *testing.T) {
+	tests := []struct {
+		name  string
+		open  func(t *testing.T, now func() time.Time) repository.DedupeStore
+		reuse bool
+	}{
+		{"memory", func(t *testing.T, now func() time.Time) repository.DedupeStore {
+			store := NewMemoryDedupeStore().(*memoryDedupeStore)
+			store.now = now
+			return store
+		}, false},
+		{"file", func(t *testing.T, now func() time.Time) repository.DedupeStore {
+			state, err := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatalf("failed to open state: %v", err)
+			}
+			store := NewStateDedupeStore(state)
+			store.(*stateDedupeStore).now = now
+			return store
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			store := tt.open(t, func() time.Time { return now })
+
+			if seen, err := store.Seen(ctx, "a"); err != nil || seen {
+				t.Fatalf("expected unseen key, got seen=%v err=%v", seen, err)
+			}
+
+			if err := store.Remember(ctx, "a", []byte("hash-a"), time.Minute); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := store.Remember(ctx, "b", []byte("hash-b"), 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value, ok, err := store.Get(ctx, "a")
+			if err != nil || !ok || string(value) != "hash-a" {
+				t.Errorf("expected remembered value, got %q ok=%v err=%v", value, ok, err)
+			}
+
+			now = now.Add(time.Minute)
+			if seen, _ := store.Seen(ctx, "a"); seen {
+				t.Error("expected entry to expire after its ttl")
+			}
+			now = now.Add(24 * time.Hour)
+			if seen, _ := store.Seen(ctx, "b"); !seen {
+				t.Error("expected entry without ttl to be kept")
+			}
+		})
+	}
+}
+
+func TestStateDedupeStore_SharesStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	ctx := context.Background()
+
+	state, err := NewFileStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := NewStateDedupeStore(state)
+	if err := state.Put(ctx, "feeds", "cursor", []byte(`"abc"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Remember(ctx, "feed:item", []byte("posted"), time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := state.Put(ctx, "feeds", "etag", []byte(`"def"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, err := NewFileStateStore(path)
+	if err != nil {
+		t.Fatalf("failed to reopen state: %v", err)
+	}
+	value, ok, err := NewStateDedupeStore(reopened).Get(ctx, "feed:item")
+	if err != nil || !ok || string(value) != "posted" {
+		t.Errorf("expected persisted value, got %q ok=%v err=%v", value, ok, err)
+	}
+	feeds, _ := reopened.List(ctx, "feeds")
+	if len(feeds) != 2 {
+		t.Errorf("expected other namespaces to survive dedupe writes, got %v", feeds)
+	}
+}
+
+func TestMemoryDedupeStore_Concurrent(t *testing.T) {
+	store := NewMemoryDedupeStore()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Remember(ctx, "key", []byte("v"), time.Minute)
+			store.Seen(ctx, "key")
+		}()
+	}
+	wg.Wait()
+
+	if seen, _ := store.Seen(ctx, "key"); !seen {
+		t.Error("expected key to be seen")
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+func testFeedValidatorRepository(t *testing.T, repo repository.FeedValidatorRepository) {
+	t.Helper()
+	ctx := context.Background()
+	feedURL := "https://example.tld/rss"
+
+	validators, err := repo.GetFeedValidators(ctx, feedURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !validators.IsZero() || validators.FeedURL != feedURL {
+		t.Errorf("expected empty validators for unknown feed, got %+v", validators)
+	}
+
+	steps := []entity.FeedValidators{
+		{FeedURL: feedURL, ETag: `"v1"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"},
+		{FeedURL: feedURL, ETag: `"v2"`},
+	}
+	for _, step := range steps {
+		if err := repo.SaveFeedValidators(ctx, step); err != nil {
+			t.Fatalf("failed to save validators: %v", err)
+		}
+		got, err := repo.GetFeedValidators(ctx, feedURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != step {
+			t.Errorf("expected %+v, got %+v", step, got)
+		}
+	}
+
+	if other, _ := repo.GetFeedValidators(ctx, "https://example.tld/other"); !other.IsZero() {
+		t.Errorf("expected validators to be kept per feed, got %+v", other)
+	}
+}
+
+func TestMemoryCache_FeedValidators(t *testing.T) {
+	testFeedValidatorRepository(t, NewMemoryCacheRepository().(repository.FeedValidatorRepository))
+}
+
+func TestSQLiteCache_FeedValidators(t *testing.T) {
+	cache, err := NewSQLiteCacheRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	defer closeSQLiteCache(t, cache)
+
+	testFeedValidatorRepository(t, cache.(repository.FeedValidatorRepository))
+}
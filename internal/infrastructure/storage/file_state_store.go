@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+const currentStateVersion = 1
+
+type stateFile struct {
+	Version    int                                   `json:"version"`
+	Namespaces map[string]map[string]json.RawMessage `json:"namespaces"`
+}
+
+// stateMigration upgrades a loaded state file by one version.
+type stateMigration func(state *stateFile) error
+
+// stateMigrations returns the upgrade from version i+1 to i+2 at index i, so
+// bumping currentStateVersion means appending its migration here.
+func stateMigrations() []stateMigration {
+	return nil
+}
+
+type fileStateStore struct {
+	mu         sync.Mutex
+	path       string
+	version    int
+	migrations []stateMigration
+	state      stateFile
+}
+
+func NewFileStateStore(path string) (repository.StateStore, error) {
+	return newFileStateStore(path, currentStateVersion, stateMigrations())
+}
+
+func newFileStateStore(path string, version int, migrations []stateMigration) (*fileStateStore, error) {
+	if len(migrations) != version-1 {
+		return nil, fmt.Errorf("state version %d needs %d migrations, got %d", version, version-1, len(migrations))
+	}
+	store := &fileStateStore{path: path, version: version, migrations: migrations}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *fileStateStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.state = stateFile{Version: s.version, Namespaces: map[string]map[string]json.RawMessage{}}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if s.state.Version < 1 || s.state.Version > s.version {
+		return fmt.Errorf("unsupported state file version %d (expected 1 to %d)", s.state.Version, s.version)
+	}
+	if s.state.Namespaces == nil {
+		s.state.Namespaces = map[string]map[string]json.RawMessage{}
+	}
+
+	loaded := s.state.Version
+	for s.state.Version < s.version {
+		if err := s.migrations[s.state.Version-1](&s.state); err != nil {
+			return fmt.Errorf("failed to migrate state file from version %d: %w", s.state.Version, err)
+		}
+		s.state.Version++
+	}
+
+	// save indents the file, so compact values back to what Put stored.
+	for _, values := range s.state.Namespaces {
+		for key, value := range values {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, value); err != nil {
+				return fmt.Errorf("failed to decode state value [%s]: %w", key, err)
+			}
+			values[key] = buf.Bytes()
+		}
+	}
+
+	if loaded != s.version {
+		log.Printf("Migrated state file %s from version %d to %d", s.path, loaded, s.version)
+		return s.save()
+	}
+	return nil
+}
+
+func (s *fileStateStore) save() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStateStore) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.state.Namespaces[namespace][key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), value...), true, nil
+}
+
+func (s *fileStateStore) Put(ctx context.Context, namespace, key string, value []byte) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("state value for %s/%s is not valid JSON", namespace, key)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.Namespaces[namespace] == nil {
+		s.state.Namespaces[namespace] = map[string]json.RawMessage{}
+	}
+	s.state.Namespaces[namespace][key] = append(json.RawMessage(nil), value...)
+	return s.save()
+}
+
+func (s *fileStateStore) Delete(ctx context.Context, namespace, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.state.Namespaces[namespace][key]; !ok {
+		return nil
+	}
+	delete(s.state.Namespaces[namespace], key)
+	return s.save()
+}
+
+func (s *fileStateStore) List(ctx context.Context, namespace string) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string][]byte, len(s.state.Namespaces[namespace]))
+	for key, value := range s.state.Namespaces[namespace] {
+		values[key] = append([]byte(nil), value...)
+	}
+	return values, nil
+}
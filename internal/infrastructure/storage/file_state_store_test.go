@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStateStore_PutGetDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	ctx := context.Background()
+
+	store, err := NewFileStateStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := store.Put(ctx, "ns", "a", []byte(`{"n":1}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Put(ctx, "ns", "b", []byte(`"two"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Put(ctx, "ns", "bad", []byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON value, got nil")
+	}
+
+	reopened, err := NewFileStateStore(path)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+
+	value, ok, err := reopened.Get(ctx, "ns", "a")
+	if err != nil || !ok || string(value) != `{"n":1}` {
+		t.Errorf("expected persisted value, got %q ok=%v err=%v", value, ok, err)
+	}
+
+	if err := reopened.Delete(ctx, "ns", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values, err := reopened.List(ctx, "ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 1 || string(values["b"]) != `"two"` {
+		t.Errorf("unexpected namespace contents: %v", values)
+	}
+}
+
+func TestFileStateStore_Load(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr bool
+		expectKey string
+	}{
+		{"unversioned file rejected", `{"ns": {"legacy": true}}`, true, ""},
+		{"version zero rejected", `{"version": 0, "namespaces": {"ns": {"legacy": true}}}`, true, ""},
+		{"current version loads", `{"version": 1, "namespaces": {"ns": {"current": 1}}}`, false, "current"},
+		{"newer version rejected", `{"version": 99, "namespaces": {}}`, true, ""},
+		{"corrupt file rejected", `{"version":`, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write state file: %v", err)
+			}
+
+			store, err := NewFileStateStore(path)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, ok, _ := store.Get(context.Background(), "ns", tt.expectKey); !ok {
+				t.Errorf("expected key %s to survive loading", tt.expectKey)
+			}
+		})
+	}
+}
+
+func TestFileStateStore_Migrations(t *testing.T) {
+	if got := len(stateMigrations()); got != currentStateVersion-1 {
+		t.Fatalf("expected %d migrations for version %d, got %d", currentStateVersion-1, currentStateVersion, got)
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "namespaces": {"old": {"key": "value"}}}`), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+	renameNamespace := func(state *stateFile) error {
+		state.Namespaces["new"] = state.Namespaces["old"]
+		delete(state.Namespaces, "old")
+		return nil
+	}
+
+	store, err := newFileStateStore(path, 2, []stateMigration{renameNamespace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, ok, _ := store.Get(context.Background(), "new", "key"); !ok || string(value) != `"value"` {
+		t.Errorf("expected migrated value, got %q ok=%v", value, ok)
+	}
+
+	data, _ := os.ReadFile(path)
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != 2 {
+		t.Errorf("expected the file to be saved at version 2, got %d (%v)", file.Version, err)
+	}
+
+	failing := func(state *stateFile) error { return errors.New("should not run again") }
+	if _, err := newFileStateStore(path, 2, []stateMigration{failing}); err != nil {
+		t.Errorf("expected an upgraded file to skip its migrations, got %v", err)
+	}
+	if _, err := newFileStateStore(path, 2, nil); err == nil {
+		t.Error("expected a missing migration to be rejected")
+	}
+}
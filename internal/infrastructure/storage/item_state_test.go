@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+	"misskeyRSSbot/internal/domain/repository"
+)
+
+func testItemStateRepository(t *testing.T, repo repository.ItemStateRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	recorded := entity.PostedItem{FeedURL: "https://example.tld/rss", GUID: "guid-1", Link: "https://example.tld/1", ContentHash: "hash-1"}
+	if err := repo.RecordPosted(ctx, recorded); err != nil {
+		t.Fatalf("failed to record posted item: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		item     entity.PostedItem
+		expected bool
+	}{
+		{"same GUID", entity.PostedItem{FeedURL: recorded.FeedURL, GUID: "guid-1"}, true},
+		{"same link", entity.PostedItem{FeedURL: recorded.FeedURL, GUID: "guid-2", Link: "https://example.tld/1"}, true},
+		{"same content", entity.PostedItem{FeedURL: recorded.FeedURL, GUID: "guid-3", Link: "https://example.tld/3", ContentHash: "hash-1"}, true},
+		{"different item", entity.PostedItem{FeedURL: recorded.FeedURL, GUID: "guid-4", Link: "https://example.tld/4", ContentHash: "hash-4"}, false},
+		{"empty link and hash do not match", entity.PostedItem{FeedURL: recorded.FeedURL, GUID: "guid-5"}, false},
+		{"other feed", entity.PostedItem{FeedURL: "https://example.tld/other", GUID: "guid-1", Link: "https://example.tld/1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted, err := repo.HasPosted(ctx, tt.item)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if posted != tt.expected {
+				t.Errorf("expected posted=%v, got %v", tt.expected, posted)
+			}
+		})
+	}
+
+	deleted, err := repo.PruneItems(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("expected recent items to be kept, %d deleted", deleted)
+	}
+	deleted, err = repo.PruneItems(ctx, -time.Hour)
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 item pruned, got %d", deleted)
+	}
+	if posted, _ := repo.HasPosted(ctx, recorded); posted {
+		t.Error("expected pruned item to be forgotten")
+	}
+}
+
+func TestSQLiteCache_ItemState(t *testing.T) {
+	cache, err := NewSQLiteCacheRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	defer closeSQLiteCache(t, cache)
+
+	testItemStateRepository(t, cache.(repository.ItemStateRepository))
+}
+
+func TestMemoryCache_ItemState(t *testing.T) {
+	testItemStateRepository(t, NewMemoryCacheRepository().(repository.ItemStateRepository))
+}
+
+func TestSQLiteCache_ItemStatePersistence(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	item := entity.PostedItem{FeedURL: "https://example.tld/rss", GUID: "guid-1", Link: "https://example.tld/1"}
+
+	cache, err := NewSQLiteCacheRepository(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	if err := cache.(repository.ItemStateRepository).RecordPosted(context.Background(), item); err != nil {
+		t.Fatalf("failed to record posted item: %v", err)
+	}
+	closeSQLiteCache(t, cache)
+
+	reopened, err := NewSQLiteCacheRepository(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen cache: %v", err)
+	}
+	defer closeSQLiteCache(t, reopened)
+
+	posted, err := reopened.(repository.ItemStateRepository).HasPosted(context.Background(), item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !posted {
+		t.Error("expected posted item to survive reopening the database")
+	}
+}
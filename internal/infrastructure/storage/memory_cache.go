@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"misskeyRSSbot/internal/domain/entity"
 	"misskeyRSSbot/internal/domain/repository"
 )
 
@@ -12,12 +13,16 @@ type memoryCache struct {
 	mu              sync.RWMutex
 	latestPublished map[string]time.Time
 	processedGUIDs  map[string]bool
+	postedItems     map[string][]postedItemRecord
+	feedValidators  map[string]entity.FeedValidators
 }
 
 func NewMemoryCacheRepository() repository.CacheRepository {
 	return &memoryCache{
 		latestPublished: make(map[string]time.Time),
 		processedGUIDs:  make(map[string]bool),
+		postedItems:     make(map[string][]postedItemRecord),
+		feedValidators:  make(map[string]entity.FeedValidators),
 	}
 }
 
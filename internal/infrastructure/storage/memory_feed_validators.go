@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (c *memoryCache) GetFeedValidators(ctx context.Context, feedURL string) (entity.FeedValidators, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if validators, ok := c.feedValidators[feedURL]; ok {
+		return validators, nil
+	}
+	return entity.FeedValidators{FeedURL: feedURL}, nil
+}
+
+func (c *memoryCache) SaveFeedValidators(ctx context.Context, validators entity.FeedValidators) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.feedValidators[validators.FeedURL] = validators
+	return nil
+}
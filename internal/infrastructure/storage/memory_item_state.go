@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+type postedItemRecord struct {
+	item     entity.PostedItem
+	postedAt time.Time
+}
+
+func (c *memoryCache) HasPosted(ctx context.Context, item entity.PostedItem) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, record := range c.postedItems[item.FeedURL] {
+		if matchesPostedItem(record.item, item) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *memoryCache) RecordPosted(ctx context.Context, item entity.PostedItem) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	records := c.postedItems[item.FeedURL]
+	for i, record := range records {
+		if record.item.GUID == item.GUID {
+			records[i] = postedItemRecord{item: item, postedAt: time.Now()}
+			return nil
+		}
+	}
+	c.postedItems[item.FeedURL] = append(records, postedItemRecord{item: item, postedAt: time.Now()})
+	return nil
+}
+
+func (c *memoryCache) PruneItems(ctx context.Context, olderThan time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := time.Now().Add(-olderThan)
+	var deleted int64
+	for feedURL, records := range c.postedItems {
+		kept := records[:0]
+		for _, record := range records {
+			if record.postedAt.Before(cutoff) {
+				deleted++
+				continue
+			}
+			kept = append(kept, record)
+		}
+		if len(kept) == 0 {
+			delete(c.postedItems, feedURL)
+			continue
+		}
+		c.postedItems[feedURL] = kept
+	}
+	return deleted, nil
+}
+
+func matchesPostedItem(recorded, item entity.PostedItem) bool {
+	if recorded.GUID == item.GUID {
+		return true
+	}
+	if item.Link != "" && recorded.Link == item.Link {
+		return true
+	}
+	return item.ContentHash != "" && recorded.ContentHash == item.ContentHash
+}
@@ -55,6 +55,22 @@ func (c *sqliteCache) initSchema(ctx context.Context) error {
 			processed_at INTEGER NOT NULL
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_processed_guids_processed_at ON processed_guids(processed_at)`,
+		`CREATE TABLE IF NOT EXISTS posted_items (
+			feed_url TEXT NOT NULL,
+			guid TEXT NOT NULL,
+			link TEXT NOT NULL,
+			content_hash TEXT NOT NULL,
+			posted_at INTEGER NOT NULL,
+			PRIMARY KEY (feed_url, guid)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_posted_items_link ON posted_items(feed_url, link)`,
+		`CREATE INDEX IF NOT EXISTS idx_posted_items_content_hash ON posted_items(feed_url, content_hash)`,
+		`CREATE INDEX IF NOT EXISTS idx_posted_items_posted_at ON posted_items(posted_at)`,
+		`CREATE TABLE IF NOT EXISTS feed_validators (
+			feed_url TEXT PRIMARY KEY,
+			etag TEXT NOT NULL,
+			last_modified TEXT NOT NULL
+		)`,
 	}
 
 	for _, query := range queries {
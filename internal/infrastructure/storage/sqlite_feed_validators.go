@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (c *sqliteCache) GetFeedValidators(ctx context.Context, feedURL string) (entity.FeedValidators, error) {
+	validators := entity.FeedValidators{FeedURL: feedURL}
+	err := c.db.QueryRowContext(
+		ctx,
+		"SELECT etag, last_modified FROM feed_validators WHERE feed_url = ?",
+		feedURL,
+	).Scan(&validators.ETag, &validators.LastModified)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return validators, nil
+	}
+	if err != nil {
+		return validators, fmt.Errorf("failed to get feed validators: %w", err)
+	}
+
+	return validators, nil
+}
+
+func (c *sqliteCache) SaveFeedValidators(ctx context.Context, validators entity.FeedValidators) error {
+	_, err := c.db.ExecContext(
+		ctx,
+		`INSERT INTO feed_validators (feed_url, etag, last_modified) VALUES (?, ?, ?)
+		ON CONFLICT(feed_url) DO UPDATE SET
+			etag = excluded.etag,
+			last_modified = excluded.last_modified`,
+		validators.FeedURL,
+		validators.ETag,
+		validators.LastModified,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save feed validators: %w", err)
+	}
+
+	return nil
+}
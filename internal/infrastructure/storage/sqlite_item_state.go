@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+)
+
+func (c *sqliteCache) HasPosted(ctx context.Context, item entity.PostedItem) (bool, error) {
+	var exists int
+	err := c.db.QueryRowContext(
+		ctx,
+		`SELECT 1 FROM posted_items WHERE feed_url = ? AND (
+			guid = ?
+			OR (link <> '' AND link = ?)
+			OR (content_hash <> '' AND content_hash = ?)
+		) LIMIT 1`,
+		item.FeedURL,
+		item.GUID,
+		item.Link,
+		item.ContentHash,
+	).Scan(&exists)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check posted item: %w", err)
+	}
+
+	return true, nil
+}
+
+func (c *sqliteCache) RecordPosted(ctx context.Context, item entity.PostedItem) error {
+	_, err := c.db.ExecContext(
+		ctx,
+		`INSERT INTO posted_items (feed_url, guid, link, content_hash, posted_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(feed_url, guid) DO UPDATE SET
+			link = excluded.link,
+			content_hash = excluded.content_hash,
+			posted_at = excluded.posted_at`,
+		item.FeedURL,
+		item.GUID,
+		item.Link,
+		item.ContentHash,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record posted item: %w", err)
+	}
+
+	return nil
+}
+
+func (c *sqliteCache) PruneItems(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan).Unix()
+	result, err := c.db.ExecContext(
+		ctx,
+		"DELETE FROM posted_items WHERE posted_at < ?",
+		cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune posted items: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
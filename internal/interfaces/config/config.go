@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +16,7 @@ import (
 type Config struct {
 	MisskeyHost string   `envconfig:"MISSKEY_HOST" required:"true"`
 	AuthToken   string   `envconfig:"AUTH_TOKEN" required:"true"`
+	AuthTokens  []string `envconfig:"AUTH_TOKENS"`
 	RSSURL      []string `envconfig:"RSS_URL"`
 
 	FetchInterval int `envconfig:"FETCH_INTERVAL" default:"30"`
@@ -21,8 +25,23 @@ type Config struct {
 
 	RefillInterval int `envconfig:"REFILL_INTERVAL" default:"10"`
 
+	MaxRetries int `envconfig:"MAX_RETRIES" default:"3"`
+
+	RetryBackoff int `envconfig:"RETRY_BACKOFF" default:"1"`
+
+	AdaptiveRateLimit bool `envconfig:"ADAPTIVE_RATE_LIMIT" default:"true"`
+
+	ShutdownGrace   int `envconfig:"SHUTDOWN_GRACE" default:"10"`
+	ShutdownTimeout int `envconfig:"SHUTDOWN_TIMEOUT" default:"30"`
+
 	LocalOnly bool `envconfig:"LOCAL_ONLY" default:"false"`
 
+	AllowedHosts []string `envconfig:"ALLOWED_HOSTS"`
+
+	Blocklist []string `envconfig:"BLOCKLIST"`
+
+	SetBotFlag bool `envconfig:"SET_BOT_FLAG" default:"false"`
+
 	LLMProvider          string `envconfig:"LLM_PROVIDER" default:""`
 	LLMAPIKey            string `envconfig:"LLM_API_KEY"`
 	LLMModel             string `envconfig:"LLM_MODEL"`
@@ -32,17 +51,69 @@ type Config struct {
 
 	CacheDBPath string `envconfig:"CACHE_DB_PATH" default:""`
 
+	StatePath string `envconfig:"STATE_PATH" default:""`
+
 	CacheCleanupInterval int `envconfig:"CACHE_CLEANUP_INTERVAL" default:"24"`
 
 	CacheRetentionDays int `envconfig:"CACHE_RETENTION_DAYS" default:"7"`
 
 	FirstRunLatestOnly bool `envconfig:"FIRST_RUN_LATEST_ONLY" default:"true"`
+
+	MastodonHost        string `envconfig:"MASTODON_HOST"`
+	MastodonAccessToken string `envconfig:"MASTODON_ACCESS_TOKEN"`
+
+	MetricsAddr string `envconfig:"METRICS_ADDR" default:""`
+
+	AdminUserID        string `envconfig:"ADMIN_USER_ID"`
+	AlertAfterFailures int    `envconfig:"ALERT_AFTER_FAILURES" default:"3"`
+	AlertCooldown      int    `envconfig:"ALERT_COOLDOWN" default:"60"`
+
+	FeedsFile string       `envconfig:"FEEDS_FILE" default:""`
+	OPMLFile  string       `envconfig:"OPML_FILE" default:""`
+	Feeds     []FeedConfig `ignored:"true"`
+
+	processEnv map[string]bool
 }
 
 func LoadConfig() (*Config, error) {
+	processEnv := environKeys()
 	_ = godotenv.Load()
+	return loadConfig(processEnv)
+}
+
+// Reload re-reads .env, FEEDS_FILE and OPML_FILE. Variables set in the
+// process environment before startup keep taking precedence over .env.
+func (c *Config) Reload() (*Config, error) {
+	values, err := godotenv.Read()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	for key := range environKeys() {
+		if _, ok := values[key]; !ok && !c.processEnv[key] {
+			os.Unsetenv(key)
+		}
+	}
+	for key, value := range values {
+		if !c.processEnv[key] {
+			os.Setenv(key, value)
+		}
+	}
+
+	return loadConfig(c.processEnv)
+}
+
+func environKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		keys[key] = true
+	}
+	return keys
+}
 
-	var cfg Config
+func loadConfig(processEnv map[string]bool) (*Config, error) {
+	cfg := Config{processEnv: processEnv}
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, err
 	}
@@ -52,8 +123,28 @@ func LoadConfig() (*Config, error) {
 		cfg.RSSURL = rssURLs
 	}
 
-	if len(cfg.RSSURL) == 0 {
-		return nil, fmt.Errorf("no RSS URLs configured, please set RSS_URL or RSS_URL_1, RSS_URL_2, etc")
+	var categories map[string]FeedConfig
+	if cfg.FeedsFile != "" {
+		file, err := loadFeedsFile(cfg.FeedsFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Feeds = file.Feeds
+		categories = file.Categories
+	}
+	if cfg.OPMLFile != "" {
+		feeds, err := LoadOPMLFile(cfg.OPMLFile, categories)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Feeds = mergeFeeds(cfg.Feeds, feeds)
+	}
+	if cfg.usesMastodon() && (cfg.MastodonHost == "" || cfg.MastodonAccessToken == "") {
+		return nil, fmt.Errorf("feeds post to mastodon, please set MASTODON_HOST and MASTODON_ACCESS_TOKEN")
+	}
+
+	if len(cfg.RSSURL) == 0 && len(cfg.Feeds) == 0 {
+		return nil, fmt.Errorf("no RSS URLs configured, please set RSS_URL, RSS_URL_1, RSS_URL_2, etc, FEEDS_FILE or OPML_FILE")
 	}
 
 	return &cfg, nil
@@ -99,6 +190,22 @@ func (c *Config) GetRefillInterval() time.Duration {
 	return time.Duration(c.RefillInterval) * time.Second
 }
 
+func (c *Config) GetRetryBackoff() time.Duration {
+	return time.Duration(c.RetryBackoff) * time.Second
+}
+
+func (c *Config) GetShutdownGrace() time.Duration {
+	return time.Duration(c.ShutdownGrace) * time.Second
+}
+
+func (c *Config) GetShutdownTimeout() time.Duration {
+	return time.Duration(c.ShutdownTimeout) * time.Second
+}
+
+func (c *Config) GetAlertCooldown() time.Duration {
+	return time.Duration(c.AlertCooldown) * time.Minute
+}
+
 type LLMConfig struct {
 	Provider          string
 	APIKey            string
@@ -137,6 +137,99 @@ func TestLoadConfig_LocalOnlyTrue(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_RetryDefaults(t *testing.T) {
+	os.Setenv("MISSKEY_HOST", "test.example.tld")
+	os.Setenv("AUTH_TOKEN", "test_token")
+	os.Setenv("RSS_URL_1", "https://example.tld/rss1")
+
+	defer os.Unsetenv("MISSKEY_HOST")
+	defer os.Unsetenv("AUTH_TOKEN")
+	defer os.Unsetenv("RSS_URL_1")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.MaxRetries != 3 {
+		t.Errorf("expected MaxRetries 3, got %d", cfg.MaxRetries)
+	}
+	if cfg.GetRetryBackoff() != time.Second {
+		t.Errorf("expected RetryBackoff 1s, got %v", cfg.GetRetryBackoff())
+	}
+	if !cfg.AdaptiveRateLimit {
+		t.Error("expected AdaptiveRateLimit to be enabled by default")
+	}
+	if cfg.GetShutdownGrace() != 10*time.Second || cfg.GetShutdownTimeout() != 30*time.Second {
+		t.Errorf("expected shutdown grace 10s and timeout 30s, got %v and %v", cfg.GetShutdownGrace(), cfg.GetShutdownTimeout())
+	}
+}
+
+func TestLoadConfig_AlertDefaults(t *testing.T) {
+	os.Setenv("MISSKEY_HOST", "test.example.tld")
+	os.Setenv("AUTH_TOKEN", "test_token")
+	os.Setenv("RSS_URL_1", "https://example.tld/rss1")
+
+	defer os.Unsetenv("MISSKEY_HOST")
+	defer os.Unsetenv("AUTH_TOKEN")
+	defer os.Unsetenv("RSS_URL_1")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.AdminUserID != "" {
+		t.Errorf("expected alerts to be disabled by default, got admin %q", cfg.AdminUserID)
+	}
+	if cfg.AlertAfterFailures != 3 {
+		t.Errorf("expected AlertAfterFailures 3, got %d", cfg.AlertAfterFailures)
+	}
+	if cfg.GetAlertCooldown() != time.Hour {
+		t.Errorf("expected AlertCooldown 1h, got %v", cfg.GetAlertCooldown())
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("MISSKEY_HOST", "test.example.tld")
+	t.Setenv("AUTH_TOKEN", "test_token")
+	t.Setenv("MAX_PERMITS", "7")
+
+	writeEnv := func(content string) {
+		if err := os.WriteFile(".env", []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+	}
+	writeEnv("RSS_URL=https://example.tld/a.xml\nFETCH_INTERVAL=5\nMAX_PERMITS=1\n")
+	defer os.Unsetenv("RSS_URL")
+	defer os.Unsetenv("FETCH_INTERVAL")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.FetchInterval != 5 {
+		t.Errorf("expected FetchInterval 5, got %d", cfg.FetchInterval)
+	}
+
+	writeEnv("RSS_URL=https://example.tld/b.xml\nMAX_PERMITS=1\n")
+	reloaded, err := cfg.Reload()
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if len(reloaded.RSSURL) != 1 || reloaded.RSSURL[0] != "https://example.tld/b.xml" {
+		t.Errorf("expected reloaded RSS URL, got %v", reloaded.RSSURL)
+	}
+	if reloaded.FetchInterval != 30 {
+		t.Errorf("expected removed FETCH_INTERVAL to fall back to default, got %d", reloaded.FetchInterval)
+	}
+	if reloaded.MaxPermits != 7 {
+		t.Errorf("expected process environment to win over .env, got MaxPermits %d", reloaded.MaxPermits)
+	}
+}
+
 func TestLoadConfig_NoRSSURLs(t *testing.T) {
 	os.Setenv("MISSKEY_HOST", "test.example.tld")
 	os.Setenv("AUTH_TOKEN", "test_token")
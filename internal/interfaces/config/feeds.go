@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"misskeyRSSbot/internal/domain/entity"
+
+	"gopkg.in/yaml.v3"
+)
+
+type FeedConfig struct {
+	URL                string        `yaml:"url"`
+	PollInterval       string        `yaml:"poll_interval"`
+	Visibility         string        `yaml:"visibility"`
+	Hashtags           []string      `yaml:"hashtags"`
+	Channel            string        `yaml:"channel"`
+	Template           string        `yaml:"template"`
+	CW                 string        `yaml:"cw"`
+	LocalOnly          *bool         `yaml:"local_only"`
+	ReactionAcceptance string        `yaml:"reaction_acceptance"`
+	Destinations       []string      `yaml:"destinations"`
+	Filter             *FilterConfig `yaml:"filter"`
+	Backfill           *int          `yaml:"backfill"`
+}
+
+type FilterConfig struct {
+	Match   string             `yaml:"match"`
+	Include []FilterRuleConfig `yaml:"include"`
+	Exclude []FilterRuleConfig `yaml:"exclude"`
+}
+
+type FilterRuleConfig struct {
+	Keyword string   `yaml:"keyword"`
+	Regex   string   `yaml:"regex"`
+	Fields  []string `yaml:"fields"`
+}
+
+type feedsFile struct {
+	Feeds      []FeedConfig          `yaml:"feeds"`
+	Categories map[string]FeedConfig `yaml:"categories"`
+}
+
+func validFeedVisibilities() []string {
+	return []string{"", "public", "home", "followers"}
+}
+
+func validDestinations() []string {
+	return []string{"misskey", "mastodon"}
+}
+
+func validReactionAcceptances() []string {
+	return []string{"", "likeOnly", "likeOnlyForRemote", "nonSensitiveOnly", "nonSensitiveOnlyForLocalLikeOnlyForRemote"}
+}
+
+func LoadFeedsFile(path string) ([]FeedConfig, error) {
+	file, err := loadFeedsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Feeds, nil
+}
+
+func loadFeedsFile(path string) (*feedsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feeds file: %w", err)
+	}
+
+	var file feedsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse feeds file %s: %w", path, err)
+	}
+	for i, feed := range file.Feeds {
+		if err := feed.validate(); err != nil {
+			return nil, fmt.Errorf("invalid feed #%d in %s: %w", i+1, path, err)
+		}
+	}
+	return &file, nil
+}
+
+func (f FeedConfig) validate() error {
+	if f.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if _, err := f.GetPollInterval(); err != nil {
+		return err
+	}
+	if !slices.Contains(validFeedVisibilities(), f.Visibility) {
+		return fmt.Errorf("unsupported visibility %q for %s", f.Visibility, f.URL)
+	}
+	if !slices.Contains(validReactionAcceptances(), f.ReactionAcceptance) {
+		return fmt.Errorf("unsupported reaction_acceptance %q for %s", f.ReactionAcceptance, f.URL)
+	}
+	for _, destination := range f.Destinations {
+		if !slices.Contains(validDestinations(), destination) {
+			return fmt.Errorf("unsupported destination %q for %s", destination, f.URL)
+		}
+	}
+	if f.Backfill != nil && *f.Backfill < 0 {
+		return fmt.Errorf("backfill must not be negative for %s", f.URL)
+	}
+	if _, err := f.Filter.Build(); err != nil {
+		return fmt.Errorf("invalid filter for %s: %w", f.URL, err)
+	}
+	return nil
+}
+
+func (f FeedConfig) GetPollInterval() (time.Duration, error) {
+	if f.PollInterval == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(f.PollInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid poll_interval %q for %s: %w", f.PollInterval, f.URL, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("poll_interval must be positive for %s", f.URL)
+	}
+	return interval, nil
+}
+
+func (c *FilterConfig) Build() (*entity.FeedFilter, error) {
+	if c == nil {
+		return nil, nil
+	}
+	include, err := buildFilterRules(c.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := buildFilterRules(c.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewFeedFilter(c.Match, include, exclude)
+}
+
+func buildFilterRules(configs []FilterRuleConfig) ([]entity.FilterRule, error) {
+	rules := make([]entity.FilterRule, 0, len(configs))
+	for _, cfg := range configs {
+		rule, err := entity.NewFilterRule(cfg.Fields, cfg.Keyword, cfg.Regex)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (c *Config) GetFeeds() []FeedConfig {
+	feeds := append([]FeedConfig(nil), c.Feeds...)
+	seen := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		seen[feed.URL] = true
+	}
+	for _, url := range c.RSSURL {
+		if !seen[url] {
+			seen[url] = true
+			feeds = append(feeds, FeedConfig{URL: url})
+		}
+	}
+	return feeds
+}
+
+func (c *Config) usesMastodon() bool {
+	for _, feed := range c.Feeds {
+		if slices.Contains(feed.Destinations, "mastodon") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFeedsFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expectErr bool
+		expected  int
+	}{
+		{
+			name: "multiple feeds",
+			content: `feeds:
+  - url: https://example.tld/a.xml
+    poll_interval: 15m
+    visibility: public
+    hashtags: [news, tech]
+    channel: channel1
+    template: "{title} {link}"
+  - url: https://example.tld/b.xml
+`,
+			expected: 2,
+		},
+		{"missing url", "feeds:\n  - visibility: home\n", true, 0},
+		{"invalid poll interval", "feeds:\n  - url: https://example.tld/a.xml\n    poll_interval: soon\n", true, 0},
+		{"negative poll interval", "feeds:\n  - url: https://example.tld/a.xml\n    poll_interval: -1m\n", true, 0},
+		{"unsupported visibility", "feeds:\n  - url: https://example.tld/a.xml\n    visibility: specified\n", true, 0},
+		{"unsupported reaction acceptance", "feeds:\n  - url: https://example.tld/a.xml\n    reaction_acceptance: heartOnly\n", true, 0},
+		{"unsupported destination", "feeds:\n  - url: https://example.tld/a.xml\n    destinations: [bluesky]\n", true, 0},
+		{"filter", "feeds:\n  - url: https://example.tld/a.xml\n    filter:\n      match: all\n      include:\n        - keyword: go\n          fields: [title]\n      exclude:\n        - regex: '(?i)sponsored'\n", false, 1},
+		{"invalid filter regex", "feeds:\n  - url: https://example.tld/a.xml\n    filter:\n      include:\n        - regex: '('\n", true, 0},
+		{"invalid filter match", "feeds:\n  - url: https://example.tld/a.xml\n    filter:\n      match: most\n", true, 0},
+		{"zero backfill", "feeds:\n  - url: https://example.tld/a.xml\n    backfill: 0\n", false, 1},
+		{"negative backfill", "feeds:\n  - url: https://example.tld/a.xml\n    backfill: -1\n", true, 0},
+		{"invalid yaml", "feeds: [", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "feeds.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write feeds file: %v", err)
+			}
+
+			feeds, err := LoadFeedsFile(path)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(feeds) != tt.expected {
+				t.Fatalf("expected %d feeds, got %d", tt.expected, len(feeds))
+			}
+		})
+	}
+}
+
+func TestLoadFeedsFile_Settings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.yaml")
+	content := "feeds:\n  - url: https://example.tld/a.xml\n    poll_interval: 15m\n    hashtags: [news]\n    channel: channel1\n    cw: Spoilers\n    local_only: true\n    reaction_acceptance: likeOnly\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write feeds file: %v", err)
+	}
+
+	feeds, err := LoadFeedsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interval, err := feeds[0].GetPollInterval()
+	if err != nil || interval != 15*time.Minute {
+		t.Errorf("expected 15m poll interval, got %v (%v)", interval, err)
+	}
+	if feeds[0].Channel != "channel1" || len(feeds[0].Hashtags) != 1 || feeds[0].Hashtags[0] != "news" {
+		t.Errorf("unexpected feed settings: %+v", feeds[0])
+	}
+	if feeds[0].CW != "Spoilers" || feeds[0].LocalOnly == nil || !*feeds[0].LocalOnly || feeds[0].ReactionAcceptance != "likeOnly" {
+		t.Errorf("unexpected note settings: %+v", feeds[0])
+	}
+}
+
+func TestLoadFeedsFile_Missing(t *testing.T) {
+	if _, err := LoadFeedsFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing feeds file, got nil")
+	}
+}
+
+func TestConfig_GetFeeds(t *testing.T) {
+	cfg := &Config{
+		RSSURL: []string{"https://example.tld/a.xml", "https://example.tld/c.xml"},
+		Feeds:  []FeedConfig{{URL: "https://example.tld/a.xml", Visibility: "public"}, {URL: "https://example.tld/b.xml"}},
+	}
+
+	feeds := cfg.GetFeeds()
+	if len(feeds) != 3 {
+		t.Fatalf("expected 3 feeds, got %d", len(feeds))
+	}
+	if feeds[0].Visibility != "public" {
+		t.Errorf("expected file settings to win for duplicate URL, got %+v", feeds[0])
+	}
+	if feeds[2].URL != "https://example.tld/c.xml" {
+		t.Errorf("expected env-only URL to be appended, got %s", feeds[2].URL)
+	}
+}
+
+func TestLoadConfig_FeedsFileWithoutRSSURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.yaml")
+	if err := os.WriteFile(path, []byte("feeds:\n  - url: https://example.tld/a.xml\n"), 0o600); err != nil {
+		t.Fatalf("failed to write feeds file: %v", err)
+	}
+
+	os.Setenv("MISSKEY_HOST", "test.example.tld")
+	os.Setenv("AUTH_TOKEN", "test_token")
+	os.Setenv("FEEDS_FILE", path)
+	defer os.Unsetenv("MISSKEY_HOST")
+	defer os.Unsetenv("AUTH_TOKEN")
+	defer os.Unsetenv("FEEDS_FILE")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if feeds := cfg.GetFeeds(); len(feeds) != 1 || feeds[0].URL != "https://example.tld/a.xml" {
+		t.Errorf("unexpected feeds: %+v", feeds)
+	}
+}
+
+func TestLoadConfig_MastodonDestination(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		token     string
+		expectErr bool
+	}{
+		{"credentials set", "mastodon.example.tld", "token", false},
+		{"missing credentials", "", "", true},
+		{"missing token", "mastodon.example.tld", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "feeds.yaml")
+			if err := os.WriteFile(path, []byte("feeds:\n  - url: https://example.tld/a.xml\n    destinations: [misskey, mastodon]\n"), 0o600); err != nil {
+				t.Fatalf("failed to write feeds file: %v", err)
+			}
+
+			os.Setenv("MISSKEY_HOST", "test.example.tld")
+			os.Setenv("AUTH_TOKEN", "test_token")
+			os.Setenv("FEEDS_FILE", path)
+			os.Setenv("MASTODON_HOST", tt.host)
+			os.Setenv("MASTODON_ACCESS_TOKEN", tt.token)
+			defer os.Unsetenv("MISSKEY_HOST")
+			defer os.Unsetenv("AUTH_TOKEN")
+			defer os.Unsetenv("FEEDS_FILE")
+			defer os.Unsetenv("MASTODON_HOST")
+			defer os.Unsetenv("MASTODON_ACCESS_TOKEN")
+
+			_, err := LoadConfig()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
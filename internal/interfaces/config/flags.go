@@ -0,0 +1,34 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+)
+
+// Flags holds the command-line options. The "once" subcommand is the same
+// as --once.
+type Flags struct {
+	DryRun bool
+	Once   bool
+}
+
+func ParseFlags(args []string, output io.Writer) (*Flags, error) {
+	var flags Flags
+	fs := flag.NewFlagSet("misskeyRSSbot", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.BoolVar(&flags.DryRun, "dry-run", false, "fetch feeds and log the notes that would be posted without posting them")
+	fs.BoolVar(&flags.Once, "once", false, "run a single fetch and post cycle, then exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch rest := fs.Args(); {
+	case len(rest) == 0:
+	case len(rest) == 1 && rest[0] == "once":
+		flags.Once = true
+	default:
+		return nil, fmt.Errorf("unknown command %q", rest[0])
+	}
+	return &flags, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"io"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectErr bool
+		expected  Flags
+	}{
+		{"no flags", nil, false, Flags{}},
+		{"dry run", []string{"--dry-run"}, false, Flags{DryRun: true}},
+		{"once flag", []string{"-once"}, false, Flags{Once: true}},
+		{"once subcommand", []string{"once"}, false, Flags{Once: true}},
+		{"dry run once", []string{"--dry-run", "once"}, false, Flags{DryRun: true, Once: true}},
+		{"unknown command", []string{"serve"}, true, Flags{}},
+		{"unknown flag", []string{"--verbose"}, true, Flags{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := ParseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if err == nil && *flags != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, *flags)
+			}
+		})
+	}
+}
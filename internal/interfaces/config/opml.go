@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+type opmlDocument struct {
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	HTMLURL  string        `xml:"htmlUrl,attr"`
+	Category string        `xml:"category,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+func (o opmlOutline) name() string {
+	if o.Text != "" {
+		return o.Text
+	}
+	return o.Title
+}
+
+// LoadOPMLFile reads the feeds of an OPML export. A feed takes its settings
+// from the first entry in categories matching its enclosing folders
+// (innermost first) or its category attribute. Outlines without an xmlUrl
+// fall back to their htmlUrl, which is resolved by feed auto-discovery.
+func LoadOPMLFile(path string, categories map[string]FeedConfig) ([]FeedConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OPML file: %w", err)
+	}
+
+	var doc opmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML file %s: %w", path, err)
+	}
+
+	var feeds []FeedConfig
+	var walk func(outlines []opmlOutline, folders []string) error
+	walk = func(outlines []opmlOutline, folders []string) error {
+		for _, outline := range outlines {
+			if len(outline.Outlines) > 0 {
+				if err := walk(outline.Outlines, append([]string{outline.name()}, folders...)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			url := outline.XMLURL
+			if url == "" {
+				url = outline.HTMLURL
+			}
+			if url == "" {
+				continue
+			}
+			feed := categoryDefaults(categories, slices.Concat(folders, opmlCategories(outline.Category)))
+			feed.URL = url
+			if err := feed.validate(); err != nil {
+				return fmt.Errorf("invalid feed %q in %s: %w", outline.name(), path, err)
+			}
+			feeds = append(feeds, feed)
+		}
+		return nil
+	}
+	if err := walk(doc.Body.Outlines, nil); err != nil {
+		return nil, err
+	}
+	return feeds, nil
+}
+
+// opmlCategories splits a category attribute such as "/Tech/Go,News" into
+// candidate names, most specific first.
+func opmlCategories(attr string) []string {
+	var names []string
+	for _, category := range strings.Split(attr, ",") {
+		category = strings.Trim(strings.TrimSpace(category), "/")
+		if category == "" {
+			continue
+		}
+		names = append(names, category)
+		if i := strings.LastIndex(category, "/"); i >= 0 {
+			names = append(names, category[i+1:])
+		}
+	}
+	return names
+}
+
+func categoryDefaults(categories map[string]FeedConfig, names []string) FeedConfig {
+	for _, name := range names {
+		if defaults, ok := categories[name]; ok {
+			return defaults
+		}
+	}
+	return FeedConfig{}
+}
+
+func mergeFeeds(feeds, extra []FeedConfig) []FeedConfig {
+	seen := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		seen[feed.URL] = true
+	}
+	for _, feed := range extra {
+		if !seen[feed.URL] {
+			seen[feed.URL] = true
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Tech">
+      <outline text="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom"/>
+      <outline text="Databases">
+        <outline text="SQLite" type="rss" xmlUrl="https://sqlite.example/rss"/>
+      </outline>
+    </outline>
+    <outline text="Tagged" type="rss" xmlUrl="https://news.example/rss" category="/News/World"/>
+    <outline text="Blog without feed URL" htmlUrl="https://blog.example/"/>
+    <outline text="Empty"/>
+  </body>
+</opml>`
+
+func TestLoadOPMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.opml")
+	if err := os.WriteFile(path, []byte(testOPML), 0o644); err != nil {
+		t.Fatalf("failed to write OPML file: %v", err)
+	}
+
+	categories := map[string]FeedConfig{
+		"Tech":      {Visibility: "public", Hashtags: []string{"tech"}},
+		"Databases": {Visibility: "followers"},
+		"World":     {Hashtags: []string{"world"}},
+	}
+	feeds, err := LoadOPMLFile(path, categories)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		url                string
+		expectedVisibility string
+		expectedHashtags   []string
+	}{
+		{"folder category", "https://go.dev/blog/feed.atom", "public", []string{"tech"}},
+		{"innermost folder wins", "https://sqlite.example/rss", "followers", nil},
+		{"category attribute", "https://news.example/rss", "", []string{"world"}},
+		{"html url for discovery", "https://blog.example/", "", nil},
+	}
+
+	if len(feeds) != len(tests) {
+		t.Fatalf("expected %d feeds, got %d", len(tests), len(feeds))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := feeds[i]
+			if feed.URL != tt.url {
+				t.Errorf("expected URL %s, got %s", tt.url, feed.URL)
+			}
+			if feed.Visibility != tt.expectedVisibility {
+				t.Errorf("expected visibility %q, got %q", tt.expectedVisibility, feed.Visibility)
+			}
+			if !slices.Equal(feed.Hashtags, tt.expectedHashtags) {
+				t.Errorf("expected hashtags %v, got %v", tt.expectedHashtags, feed.Hashtags)
+			}
+		})
+	}
+}
+
+func TestLoadOPMLFile_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		categories map[string]FeedConfig
+	}{
+		{"malformed xml", "<opml><body><outline", nil},
+		{"invalid category defaults", `<opml><body><outline text="Tech"><outline xmlUrl="https://a.example/rss"/></outline></body></opml>`, map[string]FeedConfig{"Tech": {Visibility: "specified"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "feeds.opml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write OPML file: %v", err)
+			}
+			if _, err := LoadOPMLFile(path, tt.categories); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestMergeFeeds(t *testing.T) {
+	feeds := mergeFeeds(
+		[]FeedConfig{{URL: "https://a.example/rss", Visibility: "public"}},
+		[]FeedConfig{{URL: "https://a.example/rss"}, {URL: "https://b.example/rss"}},
+	)
+	if len(feeds) != 2 {
+		t.Fatalf("expected 2 feeds, got %d", len(feeds))
+	}
+	if feeds[0].Visibility != "public" {
+		t.Error("feeds from FEEDS_FILE should take precedence over OPML")
+	}
+}
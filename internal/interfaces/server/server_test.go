@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_Endpoints(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metric 1\n"))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		ready          bool
+		expectedStatus int
+	}{
+		{"healthz before ready", "/healthz", false, http.StatusOK},
+		{"readyz before ready", "/readyz", false, http.StatusServiceUnavailable},
+		{"readyz after ready", "/readyz", true, http.StatusOK},
+		{"metrics", "/metrics", false, http.StatusOK},
+		{"unknown path", "/other", true, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("127.0.0.1:0", metrics)
+			s.SetReady(tt.ready)
+
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,12 +12,16 @@ import (
 	"time"
 
 	"misskeyRSSbot/internal/application"
+	"misskeyRSSbot/internal/domain/entity"
 	"misskeyRSSbot/internal/domain/repository"
 	"misskeyRSSbot/internal/infrastructure/llm"
+	"misskeyRSSbot/internal/infrastructure/mastodon"
+	"misskeyRSSbot/internal/infrastructure/metrics"
 	"misskeyRSSbot/internal/infrastructure/misskey"
 	"misskeyRSSbot/internal/infrastructure/rss"
 	"misskeyRSSbot/internal/infrastructure/storage"
 	"misskeyRSSbot/internal/interfaces/config"
+	"misskeyRSSbot/internal/interfaces/server"
 )
 
 func main() {
@@ -26,18 +31,91 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load configuration:", err)
 	}
+	flags, err := config.ParseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		log.Fatal("Invalid command line:", err)
+	}
+	if flags.DryRun {
+		log.Println("Dry run: notes will be logged instead of posted, and no state will be saved")
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	feedRepo := rss.NewFeedRepository()
-	noteRepo := misskey.NewNoteRepository(misskey.Config{
+	var recorder *metrics.Recorder
+	var middlewares []misskey.Middleware
+	if cfg.MetricsAddr != "" {
+		recorder = metrics.NewRecorder()
+		middlewares = append(middlewares, recorder.APITransport)
+	}
+
+	var stateStore repository.StateStore
+	if cfg.StatePath != "" && !flags.DryRun {
+		stateStore, err = storage.NewFileStateStore(cfg.StatePath)
+		if err != nil {
+			log.Fatal("Failed to open state file:", err)
+		}
+		log.Printf("Using persistent state: %s", cfg.StatePath)
+	}
+
+	noteRepo, err := misskey.NewNoteRepository(misskey.Config{
 		Host:           cfg.MisskeyHost,
 		AuthToken:      cfg.AuthToken,
+		AuthTokens:     cfg.AuthTokens,
 		MaxPermits:     cfg.MaxPermits,
 		RefillInterval: cfg.GetRefillInterval(),
+		MaxRetries:     cfg.MaxRetries,
+		RetryBackoff:   cfg.GetRetryBackoff(),
+		Adaptive:       cfg.AdaptiveRateLimit,
+		ShutdownGrace:  cfg.GetShutdownGrace(),
 		LocalOnly:      cfg.LocalOnly,
+		AllowedHosts:   cfg.AllowedHosts,
+		Blocklist:      cfg.Blocklist,
+		SetBotFlag:     cfg.SetBotFlag,
+		StateStore:     stateStore,
+		Middlewares:    middlewares,
 	})
+	if err != nil {
+		log.Fatal("Failed to initialize Misskey repository:", err)
+	}
+
+	type tokenVerifier interface {
+		Ping(ctx context.Context) error
+	}
+	if verifier, ok := noteRepo.(tokenVerifier); ok {
+		if err := verifier.Ping(ctx); err != nil {
+			var apiErr *misskey.APIError
+			if errors.As(err, &apiErr) {
+				log.Fatal("Failed to verify Misskey auth tokens:", err)
+			}
+			log.Printf("Warning: could not verify Misskey auth tokens: %v", err)
+		}
+	}
+
+	type scopeVerifier interface {
+		VerifyScopes(ctx context.Context, scopes ...string) error
+	}
+	if verifier, ok := noteRepo.(scopeVerifier); ok {
+		if err := verifier.VerifyScopes(ctx, misskey.ScopeWriteNotes); err != nil {
+			if errors.Is(err, misskey.ErrMissingScopes) {
+				log.Fatal("Misskey auth token lacks required permissions:", err)
+			}
+			log.Printf("Warning: could not verify Misskey token permissions: %v", err)
+		}
+	}
+
+	type botFlagger interface {
+		EnsureBotFlag(ctx context.Context) error
+	}
+	if flagger, ok := noteRepo.(botFlagger); ok && !flags.DryRun {
+		if err := flagger.EnsureBotFlag(ctx); err != nil {
+			log.Printf("Warning: could not ensure bot flag: %v", err)
+		}
+	}
+
+	type gracefulCloser interface {
+		Close(ctx context.Context) error
+	}
 
 	type cacheWithCleanup interface {
 		CleanupOldGUIDs(ctx context.Context, olderThan time.Duration) (int64, error)
@@ -47,7 +125,7 @@ func main() {
 	var cacheCloser io.Closer
 	var cacheCleaner cacheWithCleanup
 	firstRunLatestOnly := cfg.FirstRunLatestOnly
-	if cfg.IsPersistentCache() {
+	if cfg.IsPersistentCache() && !flags.DryRun {
 		sqliteCache, cacheErr := storage.NewSQLiteCacheRepository(cfg.CacheDBPath)
 		if cacheErr != nil {
 			log.Fatal("Failed to initialize SQLite cache:", cacheErr)
@@ -88,12 +166,82 @@ func main() {
 		}
 	}
 
+	serviceOpts := []application.RSSFeedServiceOption{application.WithFirstRunLatestOnly(firstRunLatestOnly)}
+	itemStateRepo, hasItemState := cacheRepo.(repository.ItemStateRepository)
+	if hasItemState {
+		serviceOpts = append(serviceOpts, application.WithItemStateRepository(itemStateRepo))
+	}
+	var outboundQueues []*application.OutboundQueue
+	destinationRepo := func(name string, repo repository.NoteRepository) repository.NoteRepository {
+		if flags.DryRun {
+			return application.NewDryRunNoteRepository(name, repo)
+		}
+		if stateStore == nil {
+			return repo
+		}
+		queue := application.NewOutboundQueue(name, repo, stateStore)
+		outboundQueues = append(outboundQueues, queue)
+		return queue
+	}
+	serviceNoteRepo := destinationRepo(application.DefaultDestination, noteRepo)
+	rateLimitedRepos := []any{noteRepo}
+	if cfg.MastodonHost != "" {
+		mastodonRepo, err := mastodon.NewNoteRepository(mastodon.Config{
+			Host:           cfg.MastodonHost,
+			AccessToken:    cfg.MastodonAccessToken,
+			MaxPermits:     cfg.MaxPermits,
+			RefillInterval: cfg.GetRefillInterval(),
+			MaxRetries:     cfg.MaxRetries,
+		})
+		if err != nil {
+			log.Fatal("Failed to initialize Mastodon repository:", err)
+		}
+		serviceOpts = append(serviceOpts, application.WithDestination("mastodon", destinationRepo("mastodon", mastodonRepo)))
+		rateLimitedRepos = append(rateLimitedRepos, mastodonRepo)
+		log.Printf("Mastodon destination enabled: %s", cfg.MastodonHost)
+	}
+
+	if cfg.AdminUserID != "" {
+		notifier := application.NewFeedHealthNotifier(serviceNoteRepo, cfg.AdminUserID, cfg.AlertAfterFailures, cfg.GetAlertCooldown())
+		serviceOpts = append(serviceOpts, application.WithFeedHealthNotifier(notifier))
+		log.Printf("Feed failure alerts enabled after %d consecutive failures", cfg.AlertAfterFailures)
+	}
+
+	var metricsServer *server.Server
+	if recorder != nil {
+		type statsProvider interface {
+			Stats() misskey.Stats
+		}
+		if provider, ok := noteRepo.(statsProvider); ok {
+			recorder.RegisterCounterFunc("rate_limiter_wait_seconds_total", "Time spent waiting for rate limiter permits.", func() float64 {
+				return provider.Stats().RateLimiter.BlockedTime.Seconds()
+			})
+			recorder.RegisterGaugeFunc("rate_limiter_permits", "Rate limiter permits currently available.", func() float64 {
+				return float64(provider.Stats().RateLimiter.Permits)
+			})
+		}
+		serviceOpts = append(serviceOpts, application.WithMetrics(recorder))
+
+		metricsServer = server.NewServer(cfg.MetricsAddr, recorder)
+		if err := metricsServer.Start(); err != nil {
+			log.Fatal("Failed to start metrics server:", err)
+		}
+		log.Printf("Metrics server listening on %s", cfg.MetricsAddr)
+	}
+
+	var feedOpts []rss.Option
+	if validators, ok := cacheRepo.(repository.FeedValidatorRepository); ok {
+		feedOpts = append(feedOpts, rss.WithValidatorRepository(validators))
+		serviceOpts = append(serviceOpts, application.WithFeedValidatorRepository(validators))
+	}
+	feedRepo := rss.NewFeedRepository(feedOpts...)
+
 	service := application.NewRSSFeedService(
 		feedRepo,
-		noteRepo,
+		serviceNoteRepo,
 		cacheRepo,
 		summarizerRepo,
-		application.WithFirstRunLatestOnly(firstRunLatestOnly),
+		serviceOpts...,
 	)
 
 	if firstRunLatestOnly {
@@ -104,6 +252,8 @@ func main() {
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
 
 	go func() {
 		<-sigCh
@@ -111,10 +261,18 @@ func main() {
 		cancel()
 	}()
 
+	for _, queue := range outboundQueues {
+		delivered, err := queue.Drain(ctx)
+		if err != nil {
+			log.Printf("Warning: outbound queue not fully drained: %v", err)
+		}
+		if delivered > 0 {
+			log.Printf("Delivered %d note(s) left over from the previous run", delivered)
+		}
+	}
+
 	interval := cfg.GetFetchInterval()
 	log.Printf("RSS fetch interval: %v", interval)
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
 
 	var cleanupTicker *time.Ticker
 	if cacheCleaner != nil {
@@ -124,11 +282,111 @@ func main() {
 		defer cleanupTicker.Stop()
 	}
 
-	log.Println("Fetching RSS feeds...")
-	if err := service.ProcessAllFeeds(ctx, cfg.RSSURL); err != nil {
-		log.Printf("RSS processing error: %v", err)
+	settings, err := feedSettings(cfg)
+	if err != nil {
+		log.Fatal("Invalid feed settings:", err)
+	}
+	for _, feed := range settings {
+		if err := feed.Validate(); err != nil {
+			log.Fatal("Invalid feed settings:", err)
+		}
+	}
+	closeResources := func() {
+		if closer, ok := noteRepo.(gracefulCloser); ok {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.GetShutdownTimeout())
+			if err := closer.Close(shutdownCtx); err != nil {
+				log.Printf("Failed to close note repository: %v", err)
+			}
+			cancelShutdown()
+		}
+		if cacheCloser != nil {
+			if err := cacheCloser.Close(); err != nil {
+				log.Printf("Failed to close cache: %v", err)
+			}
+		}
+		if metricsServer != nil {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Failed to stop metrics server: %v", err)
+			}
+			cancelShutdown()
+		}
+	}
+
+	cleanupCache := func() {
+		retentionPeriod := cfg.GetCacheRetentionPeriod()
+		deleted, err := cacheCleaner.CleanupOldGUIDs(ctx, retentionPeriod)
+		if err != nil {
+			log.Printf("Cache cleanup error: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Cache cleanup: removed %d old entries", deleted)
+		}
+		if hasItemState {
+			pruned, err := itemStateRepo.PruneItems(ctx, retentionPeriod)
+			if err != nil {
+				log.Printf("Posted item cleanup error: %v", err)
+			} else if pruned > 0 {
+				log.Printf("Posted item cleanup: removed %d old items", pruned)
+			}
+		}
+	}
+
+	scheduler := application.NewFeedScheduler(service, settings, interval)
+	if flags.Once {
+		log.Println("Running a single fetch cycle")
+		err := scheduler.RunOnce(ctx)
+		if cacheCleaner != nil {
+			cleanupCache()
+		}
+		closeResources()
+		if err != nil {
+			log.Fatal("RSS processing failed:", err)
+		}
+		return
+	}
+
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(ctx)
+	}()
+	if metricsServer != nil {
+		metricsServer.SetReady(true)
+	}
+
+	type rateLimitSetter interface {
+		SetRateLimit(maxPermits int, refillInterval time.Duration) error
+	}
+	reloadConfig := func() {
+		newCfg, err := cfg.Reload()
+		if err != nil {
+			log.Printf("Warning: keeping previous configuration: %v", err)
+			return
+		}
+		newSettings, err := feedSettings(newCfg)
+		if err != nil {
+			log.Printf("Warning: keeping previous configuration, invalid feed settings: %v", err)
+			return
+		}
+		for _, feed := range newSettings {
+			if err := feed.Validate(); err != nil {
+				log.Printf("Warning: keeping previous configuration, invalid feed settings: %v", err)
+				return
+			}
+		}
+
+		scheduler.Update(newSettings, newCfg.GetFetchInterval())
+		for _, repo := range rateLimitedRepos {
+			if setter, ok := repo.(rateLimitSetter); ok {
+				if err := setter.SetRateLimit(newCfg.MaxPermits, newCfg.GetRefillInterval()); err != nil {
+					log.Printf("Warning: keeping previous rate limit: %v", err)
+				}
+			}
+		}
+		cfg = newCfg
+		log.Printf("Configuration reloaded: %d feed(s), fetch interval %v", len(newSettings), newCfg.GetFetchInterval())
+		log.Println("Settings other than feeds, fetch interval and rate limits take effect after a restart")
 	}
-	log.Println("RSS feeds fetched")
 
 	cleanupChan := func() <-chan time.Time {
 		if cleanupTicker != nil {
@@ -141,26 +399,47 @@ func main() {
 		select {
 		case <-ctx.Done():
 			log.Println("Shutting down...")
-			if cacheCloser != nil {
-				if err := cacheCloser.Close(); err != nil {
-					log.Printf("Failed to close cache: %v", err)
-				}
+			if metricsServer != nil {
+				metricsServer.SetReady(false)
 			}
+			<-schedulerDone
+			closeResources()
 			return
-		case <-ticker.C:
-			log.Println("Fetching RSS feeds...")
-			if err := service.ProcessAllFeeds(ctx, cfg.RSSURL); err != nil {
-				log.Printf("RSS processing error: %v", err)
-			}
-			log.Println("RSS feeds fetched")
 		case <-cleanupChan:
-			retentionPeriod := cfg.GetCacheRetentionPeriod()
-			deleted, err := cacheCleaner.CleanupOldGUIDs(ctx, retentionPeriod)
-			if err != nil {
-				log.Printf("Cache cleanup error: %v", err)
-			} else if deleted > 0 {
-				log.Printf("Cache cleanup: removed %d old entries", deleted)
-			}
+			cleanupCache()
+		case <-hupCh:
+			log.Println("Reload signal received")
+			reloadConfig()
+		}
+	}
+}
+
+func feedSettings(cfg *config.Config) ([]application.FeedSettings, error) {
+	feeds := cfg.GetFeeds()
+	settings := make([]application.FeedSettings, 0, len(feeds))
+	for _, feed := range feeds {
+		interval, err := feed.GetPollInterval()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build feed settings: %w", err)
+		}
+		filter, err := feed.Filter.Build()
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter for %s: %w", feed.URL, err)
 		}
+		settings = append(settings, application.FeedSettings{
+			URL:                feed.URL,
+			Interval:           interval,
+			Visibility:         entity.NoteVisibility(feed.Visibility),
+			Hashtags:           feed.Hashtags,
+			ChannelID:          feed.Channel,
+			Template:           feed.Template,
+			CW:                 feed.CW,
+			LocalOnly:          feed.LocalOnly,
+			ReactionAcceptance: entity.ReactionAcceptance(feed.ReactionAcceptance),
+			Destinations:       feed.Destinations,
+			Filter:             filter,
+			Backfill:           feed.Backfill,
+		})
 	}
+	return settings, nil
 }